@@ -1,4 +1,4 @@
-// Code generated by zenrpc v2.2.11; DO NOT EDIT.
+// Code generated by zenrpc v2.3.0; DO NOT EDIT.
 
 package api
 
@@ -13,18 +13,27 @@ import (
 )
 
 var RPC = struct {
-	ProjectService struct{ Open, Current, Update, Save, Tables string }
+	PluginService  struct{ List, Run string }
+	ProjectService struct{ Open, Current, Update, Save, RenameEntity, RenameAttribute, MoveEntity, Diagram, Tables string }
 	PublicService  struct{ GoPGVersions, Modes, SearchTypes, Types, DBTypes, HTMLTypes, Ping string }
 	XMLService     struct{ GenerateEntity, LoadEntity, UpdateEntity, GenerateModelCode, GenerateSearchModelCode string }
 	XMLLangService struct{ LoadTranslation, TranslateEntity string }
 	XMLVTService   struct{ GenerateEntity, LoadEntity, UpdateEntity string }
 }{
-	ProjectService: struct{ Open, Current, Update, Save, Tables string }{
-		Open:    "open",
-		Current: "current",
-		Update:  "update",
-		Save:    "save",
-		Tables:  "tables",
+	PluginService: struct{ List, Run string }{
+		List: "list",
+		Run:  "run",
+	},
+	ProjectService: struct{ Open, Current, Update, Save, RenameEntity, RenameAttribute, MoveEntity, Diagram, Tables string }{
+		Open:            "open",
+		Current:         "current",
+		Update:          "update",
+		Save:            "save",
+		RenameEntity:    "renameentity",
+		RenameAttribute: "renameattribute",
+		MoveEntity:      "moveentity",
+		Diagram:         "diagram",
+		Tables:          "tables",
 	},
 	PublicService: struct{ GoPGVersions, Modes, SearchTypes, Types, DBTypes, HTMLTypes, Ping string }{
 		GoPGVersions: "gopgversions",
@@ -53,6 +62,115 @@ var RPC = struct {
 	},
 }
 
+func (PluginService) SMD() smd.ServiceInfo {
+	return smd.ServiceInfo{
+		Methods: map[string]smd.Service{
+			"List": {
+				Description: `List returns plugins found on PATH.`,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Description: `list of plugins`,
+					Type:        smd.Array,
+					TypeName:    "[]PluginPlugin",
+					Items: map[string]string{
+						"$ref": "#/definitions/plugin.Plugin",
+					},
+					Definitions: map[string]smd.Definition{
+						"plugin.Plugin": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "path",
+									Type: smd.String,
+								},
+							},
+						},
+					},
+				},
+			},
+			"Run": {
+				Description: `Run runs plugin with current project and writes returned files.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "name",
+						Description: `plugin name, executable mfd-gen-<name> is run`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "output",
+						Description: `output dir path`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "namespaces",
+						Description: `namespaces to generate, all namespaces if empty`,
+						Type:        smd.Array,
+						TypeName:    "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
+					},
+					{
+						Name:        "params",
+						Description: `plugin parameters`,
+						Type:        smd.Object,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `list of written files`,
+					Type:        smd.Array,
+					TypeName:    "[]",
+					Items: map[string]string{
+						"type": smd.String,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Invoke is as generated code from zenrpc cmd
+func (s PluginService) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+	resp := zenrpc.Response{}
+	var err error
+
+	switch method {
+	case RPC.PluginService.List:
+		resp.Set(s.List())
+
+	case RPC.PluginService.Run:
+		var args = struct {
+			Name       string            `json:"name"`
+			Output     string            `json:"output"`
+			Namespaces []string          `json:"namespaces"`
+			Params     map[string]string `json:"params"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"name", "output", "namespaces", "params"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Run(ctx, args.Name, args.Output, args.Namespaces, args.Params))
+
+	default:
+		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
+	}
+
+	return resp
+}
+
 func (ProjectService) SMD() smd.ServiceInfo {
 	return smd.ServiceInfo{
 		Methods: map[string]smd.Service{
@@ -98,6 +216,54 @@ func (ProjectService) SMD() smd.ServiceInfo {
 								"$ref": "#/definitions/mfd.CustomTypes",
 							},
 						},
+						{
+							Name:     "dict",
+							Optional: true,
+							Ref:      "#/definitions/mfd.Dictionary",
+							Type:     smd.Object,
+						},
+						{
+							Name: "tableMapping",
+							Ref:  "#/definitions/mfd.TableMapping",
+							Type: smd.Object,
+						},
+						{
+							Name:     "lint",
+							Optional: true,
+							Ref:      "#/definitions/mfd.LintConfig",
+							Type:     smd.Object,
+						},
+						{
+							Name: "searchPath",
+							Type: smd.String,
+						},
+						{
+							Name:        "databases",
+							Optional:    true,
+							Description: `named databases of namespaces`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.Database",
+							},
+						},
+						{
+							Name:        "imports",
+							Optional:    true,
+							Description: `namespaces imported from other projects`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.ProjectImport",
+							},
+						},
+						{
+							Name:        "outputs",
+							Optional:    true,
+							Description: `additional files rendered for each entity`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.OutputRule",
+							},
+						},
 						{
 							Name: "namespaces",
 							Type: smd.Array,
@@ -124,6 +290,150 @@ func (ProjectService) SMD() smd.ServiceInfo {
 								},
 							},
 						},
+						"mfd.Dictionary": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.Entry": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "XMLName",
+									Ref:  "#/definitions/xml.Name",
+									Type: smd.Object,
+								},
+								{
+									Name: "Value",
+									Type: smd.String,
+								},
+							},
+						},
+						"xml.Name": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+						"mfd.TableMapping": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.LintConfig": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "rules",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.LintRule",
+									},
+								},
+							},
+						},
+						"mfd.LintRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "severity",
+									Type: smd.String,
+								},
+								{
+									Name:        "ignore",
+									Description: `locations like portal.News.Title, trailing * matches any location with the prefix`,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+							},
+						},
+						"mfd.Database": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces stored in database`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "searchPath",
+									Description: `comma separated schemas of database search_path, tables from these schemas are referenced without schema`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.ProjectImport": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "path",
+									Description: `path to imported mfd project, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated imported namespaces`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "package",
+									Description: `go import path of model package generated for imported project`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "alias",
+									Description: `go package alias used for imported model package, mfd file name is used by default`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.OutputRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "generator",
+									Description: `Generator packs entity data for template: model, repo or vt`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "template",
+									Description: `path to template, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "path",
+									Description: `output file path template relative to generator output dir, e.g. cache/{{.Namespace}}/{{.Name}}_cache.go`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces, all namespaces if empty`,
+									Type:        smd.String,
+								},
+							},
+						},
 						"mfd.NSMapping": {
 							Type: "object",
 							Properties: smd.PropertyList{
@@ -160,40 +470,1335 @@ func (ProjectService) SMD() smd.ServiceInfo {
 								"type": smd.String,
 							},
 						},
-						{
-							Name: "goPGVer",
-							Type: smd.Integer,
-						},
-						{
-							Name: "customTypes",
-							Type: smd.Array,
-							Items: map[string]string{
-								"$ref": "#/definitions/mfd.CustomTypes",
+						{
+							Name: "goPGVer",
+							Type: smd.Integer,
+						},
+						{
+							Name: "customTypes",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.CustomTypes",
+							},
+						},
+						{
+							Name:     "dict",
+							Optional: true,
+							Ref:      "#/definitions/mfd.Dictionary",
+							Type:     smd.Object,
+						},
+						{
+							Name: "tableMapping",
+							Ref:  "#/definitions/mfd.TableMapping",
+							Type: smd.Object,
+						},
+						{
+							Name:     "lint",
+							Optional: true,
+							Ref:      "#/definitions/mfd.LintConfig",
+							Type:     smd.Object,
+						},
+						{
+							Name: "searchPath",
+							Type: smd.String,
+						},
+						{
+							Name:        "databases",
+							Optional:    true,
+							Description: `named databases of namespaces`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.Database",
+							},
+						},
+						{
+							Name:        "imports",
+							Optional:    true,
+							Description: `namespaces imported from other projects`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.ProjectImport",
+							},
+						},
+						{
+							Name:        "outputs",
+							Optional:    true,
+							Description: `additional files rendered for each entity`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.OutputRule",
+							},
+						},
+						{
+							Name: "namespaces",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.NSMapping",
+							},
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"mfd.CustomTypes": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "dbType",
+									Type: smd.String,
+								},
+								{
+									Name: "goType",
+									Type: smd.String,
+								},
+								{
+									Name: "goImport",
+									Type: smd.String,
+								},
+							},
+						},
+						"mfd.Dictionary": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.Entry": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "XMLName",
+									Ref:  "#/definitions/xml.Name",
+									Type: smd.Object,
+								},
+								{
+									Name: "Value",
+									Type: smd.String,
+								},
+							},
+						},
+						"xml.Name": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+						"mfd.TableMapping": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.LintConfig": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "rules",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.LintRule",
+									},
+								},
+							},
+						},
+						"mfd.LintRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "severity",
+									Type: smd.String,
+								},
+								{
+									Name:        "ignore",
+									Description: `locations like portal.News.Title, trailing * matches any location with the prefix`,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+							},
+						},
+						"mfd.Database": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces stored in database`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "searchPath",
+									Description: `comma separated schemas of database search_path, tables from these schemas are referenced without schema`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.ProjectImport": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "path",
+									Description: `path to imported mfd project, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated imported namespaces`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "package",
+									Description: `go import path of model package generated for imported project`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "alias",
+									Description: `go package alias used for imported model package, mfd file name is used by default`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.OutputRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "generator",
+									Description: `Generator packs entity data for template: model, repo or vt`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "template",
+									Description: `path to template, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "path",
+									Description: `output file path template relative to generator output dir, e.g. cache/{{.Namespace}}/{{.Name}}_cache.go`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces, all namespaces if empty`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.NSMapping": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespace",
+									Type: smd.String,
+								},
+								{
+									Name: "entity",
+									Type: smd.String,
+								},
+							},
+						},
+					},
+				},
+			},
+			"Update": {
+				Description: `Update updates project in memory.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "project",
+						Description: `Project`,
+						Type:        smd.Object,
+						TypeName:    "MfdProject",
+						Properties: smd.PropertyList{
+							{
+								Name: "name",
+								Type: smd.String,
+							},
+							{
+								Name: "languages",
+								Type: smd.Array,
+								Items: map[string]string{
+									"type": smd.String,
+								},
+							},
+							{
+								Name: "goPGVer",
+								Type: smd.Integer,
+							},
+							{
+								Name: "customTypes",
+								Type: smd.Array,
+								Items: map[string]string{
+									"$ref": "#/definitions/mfd.CustomTypes",
+								},
+							},
+							{
+								Name:     "dict",
+								Optional: true,
+								Ref:      "#/definitions/mfd.Dictionary",
+								Type:     smd.Object,
+							},
+							{
+								Name: "tableMapping",
+								Ref:  "#/definitions/mfd.TableMapping",
+								Type: smd.Object,
+							},
+							{
+								Name:     "lint",
+								Optional: true,
+								Ref:      "#/definitions/mfd.LintConfig",
+								Type:     smd.Object,
+							},
+							{
+								Name: "searchPath",
+								Type: smd.String,
+							},
+							{
+								Name:        "databases",
+								Optional:    true,
+								Description: `named databases of namespaces`,
+								Type:        smd.Array,
+								Items: map[string]string{
+									"$ref": "#/definitions/mfd.Database",
+								},
+							},
+							{
+								Name:        "imports",
+								Optional:    true,
+								Description: `namespaces imported from other projects`,
+								Type:        smd.Array,
+								Items: map[string]string{
+									"$ref": "#/definitions/mfd.ProjectImport",
+								},
+							},
+							{
+								Name:        "outputs",
+								Optional:    true,
+								Description: `additional files rendered for each entity`,
+								Type:        smd.Array,
+								Items: map[string]string{
+									"$ref": "#/definitions/mfd.OutputRule",
+								},
+							},
+							{
+								Name: "namespaces",
+								Type: smd.Array,
+								Items: map[string]string{
+									"$ref": "#/definitions/mfd.NSMapping",
+								},
+							},
+						},
+						Definitions: map[string]smd.Definition{
+							"mfd.CustomTypes": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name: "dbType",
+										Type: smd.String,
+									},
+									{
+										Name: "goType",
+										Type: smd.String,
+									},
+									{
+										Name: "goImport",
+										Type: smd.String,
+									},
+								},
+							},
+							"mfd.Dictionary": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name: "Entries",
+										Type: smd.Array,
+										Items: map[string]string{
+											"$ref": "#/definitions/mfd.Entry",
+										},
+									},
+								},
+							},
+							"mfd.Entry": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name: "XMLName",
+										Ref:  "#/definitions/xml.Name",
+										Type: smd.Object,
+									},
+									{
+										Name: "Value",
+										Type: smd.String,
+									},
+								},
+							},
+							"xml.Name": {
+								Type:       "object",
+								Properties: smd.PropertyList{},
+							},
+							"mfd.TableMapping": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name: "Entries",
+										Type: smd.Array,
+										Items: map[string]string{
+											"$ref": "#/definitions/mfd.Entry",
+										},
+									},
+								},
+							},
+							"mfd.LintConfig": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name: "rules",
+										Type: smd.Array,
+										Items: map[string]string{
+											"$ref": "#/definitions/mfd.LintRule",
+										},
+									},
+								},
+							},
+							"mfd.LintRule": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name: "name",
+										Type: smd.String,
+									},
+									{
+										Name: "severity",
+										Type: smd.String,
+									},
+									{
+										Name:        "ignore",
+										Description: `locations like portal.News.Title, trailing * matches any location with the prefix`,
+										Type:        smd.Array,
+										Items: map[string]string{
+											"type": smd.String,
+										},
+									},
+								},
+							},
+							"mfd.Database": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name: "name",
+										Type: smd.String,
+									},
+									{
+										Name:        "namespaces",
+										Description: `comma separated namespaces stored in database`,
+										Type:        smd.String,
+									},
+									{
+										Name:        "searchPath",
+										Description: `comma separated schemas of database search_path, tables from these schemas are referenced without schema`,
+										Type:        smd.String,
+									},
+								},
+							},
+							"mfd.ProjectImport": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name:        "path",
+										Description: `path to imported mfd project, relative to project file`,
+										Type:        smd.String,
+									},
+									{
+										Name:        "namespaces",
+										Description: `comma separated imported namespaces`,
+										Type:        smd.String,
+									},
+									{
+										Name:        "package",
+										Description: `go import path of model package generated for imported project`,
+										Type:        smd.String,
+									},
+									{
+										Name:        "alias",
+										Description: `go package alias used for imported model package, mfd file name is used by default`,
+										Type:        smd.String,
+									},
+								},
+							},
+							"mfd.OutputRule": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name:        "generator",
+										Description: `Generator packs entity data for template: model, repo or vt`,
+										Type:        smd.String,
+									},
+									{
+										Name:        "template",
+										Description: `path to template, relative to project file`,
+										Type:        smd.String,
+									},
+									{
+										Name:        "path",
+										Description: `output file path template relative to generator output dir, e.g. cache/{{.Namespace}}/{{.Name}}_cache.go`,
+										Type:        smd.String,
+									},
+									{
+										Name:        "namespaces",
+										Description: `comma separated namespaces, all namespaces if empty`,
+										Type:        smd.String,
+									},
+								},
+							},
+							"mfd.NSMapping": {
+								Type: "object",
+								Properties: smd.PropertyList{
+									{
+										Name: "namespace",
+										Type: smd.String,
+									},
+									{
+										Name: "entity",
+										Type: smd.String,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"Save": {
+				Description: `Save saves project from memory to disk.`,
+				Parameters:  []smd.JSONSchema{},
+			},
+			"RenameEntity": {
+				Description: `RenameEntity renames entity and updates all references to it, project and translations are saved to disk.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "entity",
+						Description: `entity name`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "newName",
+						Description: `new entity name`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `Project`,
+					Optional:    true,
+					Type:        smd.Object,
+					TypeName:    "Project",
+					Properties: smd.PropertyList{
+						{
+							Name: "name",
+							Type: smd.String,
+						},
+						{
+							Name: "languages",
+							Type: smd.Array,
+							Items: map[string]string{
+								"type": smd.String,
+							},
+						},
+						{
+							Name: "goPGVer",
+							Type: smd.Integer,
+						},
+						{
+							Name: "customTypes",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.CustomTypes",
+							},
+						},
+						{
+							Name:     "dict",
+							Optional: true,
+							Ref:      "#/definitions/mfd.Dictionary",
+							Type:     smd.Object,
+						},
+						{
+							Name: "tableMapping",
+							Ref:  "#/definitions/mfd.TableMapping",
+							Type: smd.Object,
+						},
+						{
+							Name:     "lint",
+							Optional: true,
+							Ref:      "#/definitions/mfd.LintConfig",
+							Type:     smd.Object,
+						},
+						{
+							Name: "searchPath",
+							Type: smd.String,
+						},
+						{
+							Name:        "databases",
+							Optional:    true,
+							Description: `named databases of namespaces`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.Database",
+							},
+						},
+						{
+							Name:        "imports",
+							Optional:    true,
+							Description: `namespaces imported from other projects`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.ProjectImport",
+							},
+						},
+						{
+							Name:        "outputs",
+							Optional:    true,
+							Description: `additional files rendered for each entity`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.OutputRule",
+							},
+						},
+						{
+							Name: "namespaces",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.NSMapping",
+							},
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"mfd.CustomTypes": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "dbType",
+									Type: smd.String,
+								},
+								{
+									Name: "goType",
+									Type: smd.String,
+								},
+								{
+									Name: "goImport",
+									Type: smd.String,
+								},
+							},
+						},
+						"mfd.Dictionary": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.Entry": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "XMLName",
+									Ref:  "#/definitions/xml.Name",
+									Type: smd.Object,
+								},
+								{
+									Name: "Value",
+									Type: smd.String,
+								},
+							},
+						},
+						"xml.Name": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+						"mfd.TableMapping": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.LintConfig": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "rules",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.LintRule",
+									},
+								},
+							},
+						},
+						"mfd.LintRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "severity",
+									Type: smd.String,
+								},
+								{
+									Name:        "ignore",
+									Description: `locations like portal.News.Title, trailing * matches any location with the prefix`,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+							},
+						},
+						"mfd.Database": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces stored in database`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "searchPath",
+									Description: `comma separated schemas of database search_path, tables from these schemas are referenced without schema`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.ProjectImport": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "path",
+									Description: `path to imported mfd project, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated imported namespaces`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "package",
+									Description: `go import path of model package generated for imported project`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "alias",
+									Description: `go package alias used for imported model package, mfd file name is used by default`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.OutputRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "generator",
+									Description: `Generator packs entity data for template: model, repo or vt`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "template",
+									Description: `path to template, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "path",
+									Description: `output file path template relative to generator output dir, e.g. cache/{{.Namespace}}/{{.Name}}_cache.go`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces, all namespaces if empty`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.NSMapping": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespace",
+									Type: smd.String,
+								},
+								{
+									Name: "entity",
+									Type: smd.String,
+								},
+							},
+						},
+					},
+				},
+			},
+			"RenameAttribute": {
+				Description: `RenameAttribute renames attribute of entity and updates all references to it, project and translations are saved to disk.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "entity",
+						Description: `entity name`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "attribute",
+						Description: `attribute name`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "newName",
+						Description: `new attribute name`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `Project`,
+					Optional:    true,
+					Type:        smd.Object,
+					TypeName:    "Project",
+					Properties: smd.PropertyList{
+						{
+							Name: "name",
+							Type: smd.String,
+						},
+						{
+							Name: "languages",
+							Type: smd.Array,
+							Items: map[string]string{
+								"type": smd.String,
+							},
+						},
+						{
+							Name: "goPGVer",
+							Type: smd.Integer,
+						},
+						{
+							Name: "customTypes",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.CustomTypes",
+							},
+						},
+						{
+							Name:     "dict",
+							Optional: true,
+							Ref:      "#/definitions/mfd.Dictionary",
+							Type:     smd.Object,
+						},
+						{
+							Name: "tableMapping",
+							Ref:  "#/definitions/mfd.TableMapping",
+							Type: smd.Object,
+						},
+						{
+							Name:     "lint",
+							Optional: true,
+							Ref:      "#/definitions/mfd.LintConfig",
+							Type:     smd.Object,
+						},
+						{
+							Name: "searchPath",
+							Type: smd.String,
+						},
+						{
+							Name:        "databases",
+							Optional:    true,
+							Description: `named databases of namespaces`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.Database",
+							},
+						},
+						{
+							Name:        "imports",
+							Optional:    true,
+							Description: `namespaces imported from other projects`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.ProjectImport",
+							},
+						},
+						{
+							Name:        "outputs",
+							Optional:    true,
+							Description: `additional files rendered for each entity`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.OutputRule",
+							},
+						},
+						{
+							Name: "namespaces",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.NSMapping",
+							},
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"mfd.CustomTypes": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "dbType",
+									Type: smd.String,
+								},
+								{
+									Name: "goType",
+									Type: smd.String,
+								},
+								{
+									Name: "goImport",
+									Type: smd.String,
+								},
+							},
+						},
+						"mfd.Dictionary": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.Entry": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "XMLName",
+									Ref:  "#/definitions/xml.Name",
+									Type: smd.Object,
+								},
+								{
+									Name: "Value",
+									Type: smd.String,
+								},
+							},
+						},
+						"xml.Name": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+						"mfd.TableMapping": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.LintConfig": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "rules",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.LintRule",
+									},
+								},
+							},
+						},
+						"mfd.LintRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "severity",
+									Type: smd.String,
+								},
+								{
+									Name:        "ignore",
+									Description: `locations like portal.News.Title, trailing * matches any location with the prefix`,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+							},
+						},
+						"mfd.Database": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces stored in database`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "searchPath",
+									Description: `comma separated schemas of database search_path, tables from these schemas are referenced without schema`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.ProjectImport": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "path",
+									Description: `path to imported mfd project, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated imported namespaces`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "package",
+									Description: `go import path of model package generated for imported project`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "alias",
+									Description: `go package alias used for imported model package, mfd file name is used by default`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.OutputRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "generator",
+									Description: `Generator packs entity data for template: model, repo or vt`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "template",
+									Description: `path to template, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "path",
+									Description: `output file path template relative to generator output dir, e.g. cache/{{.Namespace}}/{{.Name}}_cache.go`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces, all namespaces if empty`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"mfd.NSMapping": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespace",
+									Type: smd.String,
+								},
+								{
+									Name: "entity",
+									Type: smd.String,
+								},
+							},
+						},
+					},
+				},
+			},
+			"MoveEntity": {
+				Description: `MoveEntity moves entity to another namespace, project and translations are saved to disk.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "entity",
+						Description: `entity name`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "namespace",
+						Description: `target namespace, created if not exists`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Description: `Project`,
+					Optional:    true,
+					Type:        smd.Object,
+					TypeName:    "Project",
+					Properties: smd.PropertyList{
+						{
+							Name: "name",
+							Type: smd.String,
+						},
+						{
+							Name: "languages",
+							Type: smd.Array,
+							Items: map[string]string{
+								"type": smd.String,
+							},
+						},
+						{
+							Name: "goPGVer",
+							Type: smd.Integer,
+						},
+						{
+							Name: "customTypes",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.CustomTypes",
+							},
+						},
+						{
+							Name:     "dict",
+							Optional: true,
+							Ref:      "#/definitions/mfd.Dictionary",
+							Type:     smd.Object,
+						},
+						{
+							Name: "tableMapping",
+							Ref:  "#/definitions/mfd.TableMapping",
+							Type: smd.Object,
+						},
+						{
+							Name:     "lint",
+							Optional: true,
+							Ref:      "#/definitions/mfd.LintConfig",
+							Type:     smd.Object,
+						},
+						{
+							Name: "searchPath",
+							Type: smd.String,
+						},
+						{
+							Name:        "databases",
+							Optional:    true,
+							Description: `named databases of namespaces`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.Database",
+							},
+						},
+						{
+							Name:        "imports",
+							Optional:    true,
+							Description: `namespaces imported from other projects`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.ProjectImport",
+							},
+						},
+						{
+							Name:        "outputs",
+							Optional:    true,
+							Description: `additional files rendered for each entity`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.OutputRule",
+							},
+						},
+						{
+							Name: "namespaces",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/mfd.NSMapping",
+							},
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"mfd.CustomTypes": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "dbType",
+									Type: smd.String,
+								},
+								{
+									Name: "goType",
+									Type: smd.String,
+								},
+								{
+									Name: "goImport",
+									Type: smd.String,
+								},
+							},
+						},
+						"mfd.Dictionary": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.Entry": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "XMLName",
+									Ref:  "#/definitions/xml.Name",
+									Type: smd.Object,
+								},
+								{
+									Name: "Value",
+									Type: smd.String,
+								},
+							},
+						},
+						"xml.Name": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+						"mfd.TableMapping": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "Entries",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.Entry",
+									},
+								},
+							},
+						},
+						"mfd.LintConfig": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "rules",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/mfd.LintRule",
+									},
+								},
+							},
+						},
+						"mfd.LintRule": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "severity",
+									Type: smd.String,
+								},
+								{
+									Name:        "ignore",
+									Description: `locations like portal.News.Title, trailing * matches any location with the prefix`,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+							},
+						},
+						"mfd.Database": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces stored in database`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "searchPath",
+									Description: `comma separated schemas of database search_path, tables from these schemas are referenced without schema`,
+									Type:        smd.String,
+								},
 							},
 						},
-						{
-							Name: "namespaces",
-							Type: smd.Array,
-							Items: map[string]string{
-								"$ref": "#/definitions/mfd.NSMapping",
+						"mfd.ProjectImport": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "path",
+									Description: `path to imported mfd project, relative to project file`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated imported namespaces`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "package",
+									Description: `go import path of model package generated for imported project`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "alias",
+									Description: `go package alias used for imported model package, mfd file name is used by default`,
+									Type:        smd.String,
+								},
 							},
 						},
-					},
-					Definitions: map[string]smd.Definition{
-						"mfd.CustomTypes": {
+						"mfd.OutputRule": {
 							Type: "object",
 							Properties: smd.PropertyList{
 								{
-									Name: "dbType",
-									Type: smd.String,
+									Name:        "generator",
+									Description: `Generator packs entity data for template: model, repo or vt`,
+									Type:        smd.String,
 								},
 								{
-									Name: "goType",
-									Type: smd.String,
+									Name:        "template",
+									Description: `path to template, relative to project file`,
+									Type:        smd.String,
 								},
 								{
-									Name: "goImport",
-									Type: smd.String,
+									Name:        "path",
+									Description: `output file path template relative to generator output dir, e.g. cache/{{.Namespace}}/{{.Name}}_cache.go`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "namespaces",
+									Description: `comma separated namespaces, all namespaces if empty`,
+									Type:        smd.String,
 								},
 							},
 						},
@@ -213,83 +1818,57 @@ func (ProjectService) SMD() smd.ServiceInfo {
 					},
 				},
 			},
-			"Update": {
-				Description: `Update updates project in memory.`,
+			"Diagram": {
+				Description: `Diagram returns ER diagram of project in memory, including unsaved changes.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "project",
-						Description: `Project`,
+						Name:        "namespaces",
+						Description: `namespaces to draw, all namespaces if empty`,
+						Type:        smd.Array,
+						TypeName:    "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
+					},
+					{
+						Name:        "format",
+						Description: `diagram format: mermaid or dot, mermaid if empty`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "options",
+						Optional:    true,
+						Description: `diagram options`,
 						Type:        smd.Object,
-						TypeName:    "MfdProject",
+						TypeName:    "DiagramOptions",
 						Properties: smd.PropertyList{
 							{
-								Name: "name",
-								Type: smd.String,
-							},
-							{
-								Name: "languages",
-								Type: smd.Array,
-								Items: map[string]string{
-									"type": smd.String,
-								},
+								Name:        "fkOnly",
+								Description: `FKOnly shows only primary and foreign key columns`,
+								Type:        smd.Boolean,
 							},
 							{
-								Name: "goPGVer",
-								Type: smd.Integer,
+								Name:        "modes",
+								Description: `Modes shows vt modes of entities`,
+								Type:        smd.Boolean,
 							},
 							{
-								Name: "customTypes",
-								Type: smd.Array,
-								Items: map[string]string{
-									"$ref": "#/definitions/mfd.CustomTypes",
-								},
+								Name:        "entity",
+								Description: `Entity to focus on, diagram shows only entity and its neighbours`,
+								Type:        smd.String,
 							},
 							{
-								Name: "namespaces",
-								Type: smd.Array,
-								Items: map[string]string{
-									"$ref": "#/definitions/mfd.NSMapping",
-								},
-							},
-						},
-						Definitions: map[string]smd.Definition{
-							"mfd.CustomTypes": {
-								Type: "object",
-								Properties: smd.PropertyList{
-									{
-										Name: "dbType",
-										Type: smd.String,
-									},
-									{
-										Name: "goType",
-										Type: smd.String,
-									},
-									{
-										Name: "goImport",
-										Type: smd.String,
-									},
-								},
-							},
-							"mfd.NSMapping": {
-								Type: "object",
-								Properties: smd.PropertyList{
-									{
-										Name: "namespace",
-										Type: smd.String,
-									},
-									{
-										Name: "entity",
-										Type: smd.String,
-									},
-								},
+								Name:        "hops",
+								Description: `Hops is a number of foreign key hops from focused entity, 1 if not set`,
+								Type:        smd.Integer,
 							},
 						},
 					},
 				},
-			},
-			"Save": {
-				Description: `Save saves project from memory to disk.`,
-				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Description: `diagram source`,
+					Type:        smd.String,
+				},
 			},
 			"Tables": {
 				Description: `Tables returns all tables from database.`,
@@ -358,6 +1937,88 @@ func (s ProjectService) Invoke(ctx context.Context, method string, params json.R
 	case RPC.ProjectService.Save:
 		resp.Set(s.Save())
 
+	case RPC.ProjectService.RenameEntity:
+		var args = struct {
+			Entity  string `json:"entity"`
+			NewName string `json:"newName"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"entity", "newName"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.RenameEntity(args.Entity, args.NewName))
+
+	case RPC.ProjectService.RenameAttribute:
+		var args = struct {
+			Entity    string `json:"entity"`
+			Attribute string `json:"attribute"`
+			NewName   string `json:"newName"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"entity", "attribute", "newName"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.RenameAttribute(args.Entity, args.Attribute, args.NewName))
+
+	case RPC.ProjectService.MoveEntity:
+		var args = struct {
+			Entity    string `json:"entity"`
+			Namespace string `json:"namespace"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"entity", "namespace"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.MoveEntity(args.Entity, args.Namespace))
+
+	case RPC.ProjectService.Diagram:
+		var args = struct {
+			Namespaces []string        `json:"namespaces"`
+			Format     string          `json:"format"`
+			Options    *DiagramOptions `json:"options"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"namespaces", "format", "options"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Diagram(args.Namespaces, args.Format, args.Options))
+
 	case RPC.ProjectService.Tables:
 		resp.Set(s.Tables())
 
@@ -519,6 +2180,11 @@ func (XMLService) SMD() smd.ServiceInfo {
 							Name: "table",
 							Type: smd.String,
 						},
+						{
+							Name:        "description",
+							Description: `Description is a table comment, it is used in docs of generated code`,
+							Type:        smd.String,
+						},
 						{
 							Name: "attributes",
 							Type: smd.Array,
@@ -596,6 +2262,15 @@ func (XMLService) SMD() smd.ServiceInfo {
 									Name: "defaultVal",
 									Type: smd.String,
 								},
+								{
+									Name: "hasDefaultVal",
+									Type: smd.Boolean,
+								},
+								{
+									Name:        "description",
+									Description: `Description is a column comment, it is used in docs of generated code`,
+									Type:        smd.String,
+								},
 							},
 						},
 						"mfd.Searches": {
@@ -654,6 +2329,11 @@ func (XMLService) SMD() smd.ServiceInfo {
 							Name: "table",
 							Type: smd.String,
 						},
+						{
+							Name:        "description",
+							Description: `Description is a table comment, it is used in docs of generated code`,
+							Type:        smd.String,
+						},
 						{
 							Name: "attributes",
 							Type: smd.Array,
@@ -731,6 +2411,15 @@ func (XMLService) SMD() smd.ServiceInfo {
 									Name: "defaultVal",
 									Type: smd.String,
 								},
+								{
+									Name: "hasDefaultVal",
+									Type: smd.Boolean,
+								},
+								{
+									Name:        "description",
+									Description: `Description is a column comment, it is used in docs of generated code`,
+									Type:        smd.String,
+								},
 							},
 						},
 						"mfd.Searches": {
@@ -779,6 +2468,11 @@ func (XMLService) SMD() smd.ServiceInfo {
 								Name: "table",
 								Type: smd.String,
 							},
+							{
+								Name:        "description",
+								Description: `Description is a table comment, it is used in docs of generated code`,
+								Type:        smd.String,
+							},
 							{
 								Name: "attributes",
 								Type: smd.Array,
@@ -856,6 +2550,15 @@ func (XMLService) SMD() smd.ServiceInfo {
 										Name: "defaultVal",
 										Type: smd.String,
 									},
+									{
+										Name: "hasDefaultVal",
+										Type: smd.Boolean,
+									},
+									{
+										Name:        "description",
+										Description: `Description is a column comment, it is used in docs of generated code`,
+										Type:        smd.String,
+									},
 								},
 							},
 							"mfd.Searches": {
@@ -904,6 +2607,11 @@ func (XMLService) SMD() smd.ServiceInfo {
 								Name: "table",
 								Type: smd.String,
 							},
+							{
+								Name:        "description",
+								Description: `Description is a table comment, it is used in docs of generated code`,
+								Type:        smd.String,
+							},
 							{
 								Name: "attributes",
 								Type: smd.Array,
@@ -981,6 +2689,15 @@ func (XMLService) SMD() smd.ServiceInfo {
 										Name: "defaultVal",
 										Type: smd.String,
 									},
+									{
+										Name: "hasDefaultVal",
+										Type: smd.Boolean,
+									},
+									{
+										Name:        "description",
+										Description: `Description is a column comment, it is used in docs of generated code`,
+										Type:        smd.String,
+									},
 								},
 							},
 							"mfd.Searches": {
@@ -1032,6 +2749,11 @@ func (XMLService) SMD() smd.ServiceInfo {
 								Name: "table",
 								Type: smd.String,
 							},
+							{
+								Name:        "description",
+								Description: `Description is a table comment, it is used in docs of generated code`,
+								Type:        smd.String,
+							},
 							{
 								Name: "attributes",
 								Type: smd.Array,
@@ -1109,6 +2831,15 @@ func (XMLService) SMD() smd.ServiceInfo {
 										Name: "defaultVal",
 										Type: smd.String,
 									},
+									{
+										Name: "hasDefaultVal",
+										Type: smd.Boolean,
+									},
+									{
+										Name:        "description",
+										Description: `Description is a column comment, it is used in docs of generated code`,
+										Type:        smd.String,
+									},
 								},
 							},
 							"mfd.Searches": {
@@ -1546,6 +3277,21 @@ func (XMLVTService) SMD() smd.ServiceInfo {
 							Name: "mode",
 							Type: smd.String,
 						},
+						{
+							Name:        "importExport",
+							Description: `generate export to and import from csv/xlsx files`,
+							Type:        smd.Boolean,
+						},
+						{
+							Name:        "audit",
+							Description: `write changes made by vt services to audit log`,
+							Type:        smd.Boolean,
+						},
+						{
+							Name:        "roles",
+							Description: `comma separated roles required to access entity in vt services and interface, empty for any user`,
+							Type:        smd.String,
+						},
 					},
 					Definitions: map[string]smd.Definition{
 						"mfd.VTAttributes": {
@@ -1620,6 +3366,11 @@ func (XMLVTService) SMD() smd.ServiceInfo {
 									Description: `input type in search`,
 									Type:        smd.String,
 								},
+								{
+									Name:        "children",
+									Description: `has-many relation edited in form, e.g. City.RegionID`,
+									Type:        smd.String,
+								},
 							},
 						},
 					},
@@ -1671,6 +3422,21 @@ func (XMLVTService) SMD() smd.ServiceInfo {
 							Name: "mode",
 							Type: smd.String,
 						},
+						{
+							Name:        "importExport",
+							Description: `generate export to and import from csv/xlsx files`,
+							Type:        smd.Boolean,
+						},
+						{
+							Name:        "audit",
+							Description: `write changes made by vt services to audit log`,
+							Type:        smd.Boolean,
+						},
+						{
+							Name:        "roles",
+							Description: `comma separated roles required to access entity in vt services and interface, empty for any user`,
+							Type:        smd.String,
+						},
 					},
 					Definitions: map[string]smd.Definition{
 						"mfd.VTAttributes": {
@@ -1745,6 +3511,11 @@ func (XMLVTService) SMD() smd.ServiceInfo {
 									Description: `input type in search`,
 									Type:        smd.String,
 								},
+								{
+									Name:        "children",
+									Description: `has-many relation edited in form, e.g. City.RegionID`,
+									Type:        smd.String,
+								},
 							},
 						},
 					},
@@ -1791,6 +3562,21 @@ func (XMLVTService) SMD() smd.ServiceInfo {
 								Name: "mode",
 								Type: smd.String,
 							},
+							{
+								Name:        "importExport",
+								Description: `generate export to and import from csv/xlsx files`,
+								Type:        smd.Boolean,
+							},
+							{
+								Name:        "audit",
+								Description: `write changes made by vt services to audit log`,
+								Type:        smd.Boolean,
+							},
+							{
+								Name:        "roles",
+								Description: `comma separated roles required to access entity in vt services and interface, empty for any user`,
+								Type:        smd.String,
+							},
 						},
 						Definitions: map[string]smd.Definition{
 							"mfd.VTAttributes": {
@@ -1865,6 +3651,11 @@ func (XMLVTService) SMD() smd.ServiceInfo {
 										Description: `input type in search`,
 										Type:        smd.String,
 									},
+									{
+										Name:        "children",
+										Description: `has-many relation edited in form, e.g. City.RegionID`,
+										Type:        smd.String,
+									},
 								},
 							},
 						},
@@ -31,6 +31,10 @@ func ProjectMiddleware(store *Store) zenrpc.MiddlewareFunc {
 				return handler(ctx, method, params)
 			}
 
+			if namespace == pluginNS && method == RPC.PluginService.List {
+				return handler(ctx, method, params)
+			}
+
 			if store.CurrentProject != nil && store.Genna != nil {
 				return handler(ctx, method, params)
 			}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+	"github.com/vmkteam/mfd-generator/plugin"
+
+	"github.com/vmkteam/zenrpc/v2"
+)
+
+type PluginService struct {
+	*Store
+
+	zenrpc.Service
+}
+
+func NewPluginService(store *Store) *PluginService {
+	return &PluginService{
+		Store: store,
+	}
+}
+
+// List returns plugins found on PATH.
+//
+//zenrpc:return		list of plugins
+func (s PluginService) List() []plugin.Plugin {
+	return plugin.Discover()
+}
+
+// Run runs plugin with current project and writes returned files.
+//
+//zenrpc:name		plugin name, executable mfd-gen-<name> is run
+//zenrpc:output		output dir path
+//zenrpc:namespaces	namespaces to generate, all namespaces if empty
+//zenrpc:params		plugin parameters
+//zenrpc:return		list of written files
+func (s PluginService) Run(ctx context.Context, name, output string, namespaces []string, params map[string]string) ([]string, error) {
+	translations, err := mfd.LoadTranslations(s.CurrentFile, s.CurrentProject.Languages)
+	if err != nil {
+		return nil, err
+	}
+
+	options := plugin.Options{
+		Name:       name,
+		MFDPath:    s.CurrentFile,
+		Output:     output,
+		Namespaces: namespaces,
+		Params:     params,
+	}
+
+	return plugin.NewGenerator(options).Run(ctx, plugin.NewRequest(s.CurrentProject, translations, options))
+}
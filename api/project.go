@@ -5,7 +5,9 @@ import (
 	"log"
 	"strings"
 
+	"github.com/vmkteam/mfd-generator/generators/docs"
 	"github.com/vmkteam/mfd-generator/mfd"
+	"github.com/vmkteam/mfd-generator/refactor"
 
 	genna "github.com/dizzyfool/genna/lib"
 	"github.com/vmkteam/zenrpc/v2"
@@ -84,6 +86,131 @@ func (s ProjectService) Save() error {
 	return nil
 }
 
+// RenameEntity renames entity and updates all references to it, project and translations are saved to disk.
+//
+//zenrpc:entity		entity name
+//zenrpc:newName	new entity name
+//zenrpc:return		Project
+func (s ProjectService) RenameEntity(entity, newName string) (*mfd.Project, error) {
+	return s.refactor(func(project *mfd.Project, translations []*mfd.Translation) error {
+		return project.RenameEntity(entity, newName, translations...)
+	})
+}
+
+// RenameAttribute renames attribute of entity and updates all references to it, project and translations are saved to disk.
+//
+//zenrpc:entity		entity name
+//zenrpc:attribute	attribute name
+//zenrpc:newName	new attribute name
+//zenrpc:return		Project
+func (s ProjectService) RenameAttribute(entity, attribute, newName string) (*mfd.Project, error) {
+	return s.refactor(func(project *mfd.Project, translations []*mfd.Translation) error {
+		return project.RenameAttribute(entity, attribute, newName, translations...)
+	})
+}
+
+// MoveEntity moves entity to another namespace, project and translations are saved to disk.
+//
+//zenrpc:entity		entity name
+//zenrpc:namespace	target namespace, created if not exists
+//zenrpc:return		Project
+func (s ProjectService) MoveEntity(entity, namespace string) (*mfd.Project, error) {
+	return s.refactor(func(project *mfd.Project, translations []*mfd.Translation) error {
+		return project.MoveEntity(entity, namespace, translations...)
+	})
+}
+
+// refactor applies refactoring to current project and its translations and saves them.
+// Refactoring could be applied partially on error, project is reloaded from disk then and its unsaved changes are lost.
+func (s ProjectService) refactor(refactoring refactor.Refactoring) (*mfd.Project, error) {
+	loaded, err := mfd.LoadTranslations(s.CurrentFile, s.CurrentProject.Languages)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]*mfd.Translation, 0, len(loaded))
+	for _, lang := range s.CurrentProject.Languages {
+		translation := loaded[lang]
+		translations = append(translations, &translation)
+	}
+
+	if err := refactoring(s.CurrentProject, translations); err != nil {
+		if project, lerr := mfd.LoadProject(s.CurrentFile, false, DefaultGoPGVer); lerr != nil {
+			log.Printf("reload project after refactoring error: %s", lerr)
+		} else {
+			s.CurrentProject = project
+		}
+		return nil, err
+	}
+
+	if err := refactor.Save(s.CurrentFile, s.CurrentProject, translations); err != nil {
+		return nil, err
+	}
+
+	return s.CurrentProject, nil
+}
+
+// DiagramOptions are options of ER diagram
+type DiagramOptions struct {
+	// FKOnly shows only primary and foreign key columns
+	FKOnly bool `json:"fkOnly"`
+	// Modes shows vt modes of entities
+	Modes bool `json:"modes"`
+	// Entity to focus on, diagram shows only entity and its neighbours
+	Entity string `json:"entity,omitempty"`
+	// Hops is a number of foreign key hops from focused entity, 1 if not set
+	Hops int `json:"hops,omitempty"`
+}
+
+// Diagram returns ER diagram of project in memory, including unsaved changes.
+//
+//zenrpc:namespaces	namespaces to draw, all namespaces if empty
+//zenrpc:format		diagram format: mermaid or dot, mermaid if empty
+//zenrpc:options	diagram options
+//zenrpc:return		diagram source
+func (s ProjectService) Diagram(namespaces []string, format string, options *DiagramOptions) (string, error) {
+	s.CurrentProject.UpdateLinks()
+
+	if len(namespaces) == 0 {
+		namespaces = s.CurrentProject.NamespaceNames
+	}
+	if format == "" {
+		format = docs.FormatMermaid
+	}
+	if options == nil {
+		options = &DiagramOptions{}
+	}
+
+	nss := make([]*mfd.Namespace, 0, len(namespaces))
+	for _, name := range namespaces {
+		ns := s.CurrentProject.Namespace(name)
+		if ns == nil {
+			return "", fmt.Errorf("namespace %s not found", name)
+		}
+		nss = append(nss, ns)
+	}
+
+	diagram := docs.NewDiagram(s.CurrentProject.Name, nss...)
+	diagram.FKOnly = options.FKOnly
+	if options.Modes {
+		diagram = diagram.WithModes(s.CurrentProject)
+	}
+
+	if options.Entity != "" {
+		hops := options.Hops
+		if hops <= 0 {
+			hops = 1
+		}
+
+		var err error
+		if diagram, err = diagram.Focus(options.Entity, hops); err != nil {
+			return "", err
+		}
+	}
+
+	return diagram.Render(format)
+}
+
 // Tables returns all tables from database.
 //
 //zenrpc:url	the connection string to pg database
@@ -138,6 +138,8 @@ func (s PublicService) HTMLTypes() []string {
 		mfd.TypeHTMLSelect,
 		mfd.TypeHTMLFile,
 		mfd.TypeHTMLImage,
+		mfd.TypeHTMLAutocomplete,
+		mfd.TypeHTMLChildren,
 	}
 }
 
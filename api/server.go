@@ -24,6 +24,7 @@ const (
 	xmlNS     = "xml"
 	xmlVtNS   = "xmlvt"
 	xmlLangNS = "xmllang"
+	pluginNS  = "plugin"
 )
 
 type Server struct {
@@ -88,6 +89,7 @@ func (s *Server) Serve() error {
 	rpc.Register(xmlNS, NewXMLService(store))
 	rpc.Register(xmlVtNS, NewXMLVTService(store))
 	// rpc.Register(xmlLangNS, NewXMLLangService(store))
+	rpc.Register(pluginNS, NewPluginService(store))
 
 	rpc.Use(ProjectMiddleware(store))
 
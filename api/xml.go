@@ -39,11 +39,17 @@ func (s XMLService) GenerateEntity(table, namespace string) (*mfd.Entity, error)
 		return nil, err
 	}
 
+	comments, err := xml.ReadComments(s.Genna)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, entity := range entities {
 		exiting := s.CurrentProject.EntityByTable(entity.PGFullName)
 
 		// adding to project
 		entity := xml.PackEntity(namespace, entity, exiting, s.CurrentProject.CustomTypes)
+		comments.Describe(entity)
 
 		return entity, nil
 	}
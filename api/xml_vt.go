@@ -39,7 +39,7 @@ func (s XMLVTService) GenerateEntity(namespace, entity string) (*mfd.VTEntity, e
 	}
 
 	existing := s.CurrentProject.VTEntity(entity)
-	vtEntity := xmlvt.PackVTEntity(base, existing)
+	vtEntity := xmlvt.PackVTEntity(base, existing, ns)
 
 	return vtEntity, nil
 }
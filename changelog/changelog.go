@@ -0,0 +1,295 @@
+package changelog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/dizzyfool/genna/util"
+)
+
+// Kinds of changes
+const (
+	KindEntityAdded      = "entity-added"
+	KindEntityRemoved    = "entity-removed"
+	KindEntityMoved      = "entity-moved"
+	KindAttributeAdded   = "attribute-added"
+	KindAttributeRemoved = "attribute-removed"
+	KindAttributeChanged = "attribute-changed"
+	KindSearchAdded      = "search-added"
+	KindSearchRemoved    = "search-removed"
+	KindModeChanged      = "mode-changed"
+)
+
+// Change is a single semantic change of project model
+type Change struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Entity    string `json:"entity"`
+
+	// Name of changed attribute or search, empty for entity changes
+	Name string `json:"name,omitempty"`
+
+	Message string `json:"message"`
+
+	// Breaks lists generated public api removed or changed by change: repo methods, model and search fields, vt methods
+	Breaks []string `json:"breaks,omitempty"`
+}
+
+// Breaking returns true if change breaks generated public api
+func (c Change) Breaking() bool {
+	return len(c.Breaks) > 0
+}
+
+// Location returns namespace.Entity of change
+func (c Change) Location() string {
+	return c.Namespace + "." + c.Entity
+}
+
+// Compare returns changes of project model from previous to next project, entities of imported projects are not compared
+func Compare(prev, next *mfd.Project) []Change {
+	var changes []Change
+
+	for _, ns := range prev.Namespaces {
+		for _, entity := range ns.Entities {
+			changed := ownEntity(next, entity.Name)
+			if changed == nil {
+				changes = append(changes, Change{
+					Kind:      KindEntityRemoved,
+					Namespace: ns.Name,
+					Entity:    entity.Name,
+					Message:   fmt.Sprintf("entity %s removed", entity.Name),
+					Breaks:    append(entityAPI(ns.Name, entity), vtService(prev, entity.Name)...),
+				})
+				continue
+			}
+
+			if changed.Namespace != entity.Namespace {
+				changes = append(changes, Change{
+					Kind:      KindEntityMoved,
+					Namespace: ns.Name,
+					Entity:    entity.Name,
+					Message:   fmt.Sprintf("entity %s moved from %s to %s", entity.Name, entity.Namespace, changed.Namespace),
+					Breaks:    repoMethods(ns.Name, entity),
+				})
+			}
+
+			changes = append(changes, compareEntity(entity, changed)...)
+			changes = append(changes, compareMode(prev, next, changed)...)
+		}
+	}
+
+	for _, ns := range next.Namespaces {
+		for _, entity := range ns.Entities {
+			if ownEntity(prev, entity.Name) == nil {
+				changes = append(changes, Change{
+					Kind:      KindEntityAdded,
+					Namespace: ns.Name,
+					Entity:    entity.Name,
+					Message:   fmt.Sprintf("entity %s added, table %s", entity.Name, entity.Table),
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+// compareEntity returns changes of attributes and searches of entity
+func compareEntity(prev, next *mfd.Entity) []Change {
+	var changes []Change
+	change := func(kind, name, message string, breaks ...string) {
+		changes = append(changes, Change{
+			Kind:      kind,
+			Namespace: next.Namespace,
+			Entity:    next.Name,
+			Name:      name,
+			Message:   message,
+			Breaks:    breaks,
+		})
+	}
+
+	for _, attr := range prev.Attributes {
+		changed := next.AttributeByName(attr.Name)
+		if changed == nil {
+			change(KindAttributeRemoved, attr.Name, fmt.Sprintf("attribute %s removed", attr.Name), attributeAPI(prev, attr)...)
+			continue
+		}
+
+		if diff := attributeDiff(attr, changed); diff != "" {
+			var breaks []string
+			if attr.GoType != changed.GoType || attr.PrimaryKey != changed.PrimaryKey {
+				breaks = attributeAPI(prev, attr)
+			}
+			change(KindAttributeChanged, attr.Name, fmt.Sprintf("attribute %s changed: %s", attr.Name, diff), breaks...)
+		}
+	}
+
+	for _, attr := range next.Attributes {
+		if prev.AttributeByName(attr.Name) == nil {
+			change(KindAttributeAdded, attr.Name, fmt.Sprintf("attribute %s added, %s %s", attr.Name, attr.DBType, attr.GoType))
+		}
+	}
+
+	// searches are compared as a whole, changed search is reported as removed and added
+	for _, search := range prev.Searches {
+		if !slices.ContainsFunc(next.Searches, func(s *mfd.Search) bool { return sameSearch(s, search) }) {
+			change(KindSearchRemoved, search.Name, fmt.Sprintf("search %s removed, %s by %s", search.Name, search.SearchType, search.AttrName), prev.Name+"Search."+search.Name)
+		}
+	}
+
+	for _, search := range next.Searches {
+		if !slices.ContainsFunc(prev.Searches, func(s *mfd.Search) bool { return sameSearch(s, search) }) {
+			change(KindSearchAdded, search.Name, fmt.Sprintf("search %s added, %s by %s", search.Name, search.SearchType, search.AttrName))
+		}
+	}
+
+	return changes
+}
+
+// compareMode returns change of vt mode of entity, entity without vt entity has mode None
+func compareMode(prev, next *mfd.Project, entity *mfd.Entity) []Change {
+	prevMode, nextMode := vtMode(prev, entity.Name), vtMode(next, entity.Name)
+	if prevMode == nextMode {
+		return nil
+	}
+
+	var breaks []string
+	if nextMode == mfd.ModeNone {
+		breaks = vtService(prev, entity.Name)
+	} else {
+		for _, method := range vtMethods(prevMode) {
+			if !slices.Contains(vtMethods(nextMode), method) {
+				breaks = append(breaks, entity.Name+"Service."+method)
+			}
+		}
+	}
+
+	return []Change{{
+		Kind:      KindModeChanged,
+		Namespace: entity.Namespace,
+		Entity:    entity.Name,
+		Message:   fmt.Sprintf("vt mode changed from %s to %s", prevMode, nextMode),
+		Breaks:    breaks,
+	}}
+}
+
+// attributeDiff describes changes of attribute types, nullability and keys
+func attributeDiff(prev, next *mfd.Attribute) string {
+	var diff []string
+	if prev.DBType != next.DBType || prev.IsArray != next.IsArray {
+		diff = append(diff, fmt.Sprintf("db type %s -> %s", dbType(prev), dbType(next)))
+	}
+	if prev.GoType != next.GoType {
+		diff = append(diff, fmt.Sprintf("go type %s -> %s", prev.GoType, next.GoType))
+	}
+	if prev.Nullable() != next.Nullable() {
+		diff = append(diff, fmt.Sprintf("nullable %s -> %s", prev.Null, next.Null))
+	}
+	if prev.PrimaryKey != next.PrimaryKey {
+		diff = append(diff, fmt.Sprintf("primary key %t -> %t", prev.PrimaryKey, next.PrimaryKey))
+	}
+	if prev.ForeignKey != next.ForeignKey {
+		diff = append(diff, fmt.Sprintf("foreign key %q -> %q", prev.ForeignKey, next.ForeignKey))
+	}
+
+	return strings.Join(diff, ", ")
+}
+
+func dbType(attr *mfd.Attribute) string {
+	if attr.IsArray {
+		return attr.DBType + "[]"
+	}
+
+	return attr.DBType
+}
+
+func sameSearch(a, b *mfd.Search) bool {
+	return a.Name == b.Name && a.AttrName == b.AttrName && a.SearchType == b.SearchType
+}
+
+// ownEntity returns entity of project namespaces, imported entities are skipped
+func ownEntity(project *mfd.Project, name string) *mfd.Entity {
+	for _, ns := range project.Namespaces {
+		if entity := ns.Entity(name); entity != nil {
+			return entity
+		}
+	}
+
+	return nil
+}
+
+func vtMode(project *mfd.Project, entity string) string {
+	if vtEntity := project.VTEntity(entity); vtEntity != nil && vtEntity.Mode != "" {
+		return vtEntity.Mode
+	}
+
+	return mfd.ModeNone
+}
+
+// entityAPI returns model types and repo methods generated for entity
+func entityAPI(namespace string, entity *mfd.Entity) []string {
+	return append([]string{entity.Name, entity.Name + "Search"}, repoMethods(namespace, entity)...)
+}
+
+// repoMethods returns methods of namespace repo generated for entity
+func repoMethods(namespace string, entity *mfd.Entity) []string {
+	repo := util.CamelCased(util.Sanitize(namespace)) + "Repo."
+	plural := mfd.MakePlural(entity.Name)
+
+	methods := []string{repo + "Full" + entity.Name, repo + "Default" + entity.Name + "Sort"}
+	if len(entity.PKs()) > 0 {
+		methods = append(methods, repo+entity.Name+"ByID")
+	}
+	methods = append(methods,
+		repo+"One"+entity.Name,
+		repo+plural+"ByFilters",
+		repo+"Count"+plural,
+		repo+"Add"+entity.Name,
+		repo+"Update"+entity.Name,
+	)
+	if len(entity.PKs()) > 0 {
+		methods = append(methods, repo+"Delete"+entity.Name)
+	}
+
+	return methods
+}
+
+// attributeAPI returns model and search fields generated for attribute, methods with primary key arguments are added for primary keys
+func attributeAPI(entity *mfd.Entity, attr *mfd.Attribute) []string {
+	api := []string{entity.Name + "." + attr.Name, entity.Name + "Search." + attr.Name}
+	if attr.PrimaryKey {
+		repo := util.CamelCased(util.Sanitize(entity.Namespace)) + "Repo."
+		api = append(api,
+			repo+entity.Name+"ByID",
+			repo+"Delete"+entity.Name,
+			entity.Name+"Service.GetByID",
+			entity.Name+"Service.Delete",
+		)
+	}
+
+	return api
+}
+
+// vtService returns vt service of entity if it is generated
+func vtService(project *mfd.Project, entity string) []string {
+	if vtMode(project, entity) == mfd.ModeNone {
+		return nil
+	}
+
+	return []string{entity + "Service"}
+}
+
+// vtMethods returns public methods of vt service generated in mode
+func vtMethods(mode string) []string {
+	switch mode {
+	case mfd.ModeFull:
+		return []string{"Count", "Get", "GetByID", "Add", "Update", "Delete", "Validate"}
+	case mfd.ModeReadOnly, mfd.ModeReadOnlyWithTemplates:
+		return []string{"Count", "Get", "GetByID"}
+	}
+
+	return nil
+}
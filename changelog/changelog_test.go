@@ -0,0 +1,142 @@
+package changelog
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+var testMFD = filepath.Join("..", "generators", "testdata", "expected", "newsportal.mfd")
+
+func loadProject(t *testing.T) *mfd.Project {
+	t.Helper()
+
+	project, err := mfd.LoadProject(testMFD, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return project
+}
+
+func changeByKind(changes []Change, kind, entity, name string) *Change {
+	i := slices.IndexFunc(changes, func(c Change) bool { return c.Kind == kind && c.Entity == entity && c.Name == name })
+	if i == -1 {
+		return nil
+	}
+
+	return &changes[i]
+}
+
+func TestCompare(t *testing.T) {
+	prev, next := loadProject(t), loadProject(t)
+
+	if changes := Compare(prev, next); len(changes) != 0 {
+		t.Fatalf("same projects have changes: %v", changes)
+	}
+
+	portal := next.Namespace("portal")
+	portal.Entities = slices.DeleteFunc(portal.Entities, func(e *mfd.Entity) bool { return e.Name == "Tag" })
+	if err := next.MoveEntity("Category", "catalog"); err != nil {
+		t.Fatal(err)
+	}
+
+	news := next.Entity("News")
+	news.Attributes = slices.DeleteFunc(news.Attributes, func(a *mfd.Attribute) bool { return a.Name == "Preview" })
+	news.AttributeByName("Title").GoType = "*string"
+	news.AttributeByName("Title").Null = mfd.NullableYes
+	news.AttributeByName("Content").DBType = "varchar"
+	news.Attributes = append(news.Attributes, &mfd.Attribute{Name: "Views", DBName: "views", DBType: "int4", GoType: "int"})
+	news.Searches = slices.DeleteFunc(news.Searches, func(s *mfd.Search) bool { return s.Name == "ContentILike" })
+	news.Searches = append(news.Searches, &mfd.Search{Name: "ViewsFrom", AttrName: "Views", SearchType: mfd.SearchGE})
+	next.VTEntity("City").Mode = mfd.ModeReadOnly
+	next.VTEntity("Region").Mode = mfd.ModeNone
+
+	changes := Compare(prev, next)
+
+	tc := []struct {
+		kind, entity, name string
+		breaks             []string
+	}{
+		{kind: KindEntityRemoved, entity: "Tag", breaks: []string{"Tag", "TagSearch", "PortalRepo.FullTag", "PortalRepo.DefaultTagSort", "PortalRepo.TagByID", "PortalRepo.OneTag", "PortalRepo.TagsByFilters", "PortalRepo.CountTags", "PortalRepo.AddTag", "PortalRepo.UpdateTag", "PortalRepo.DeleteTag", "TagService"}},
+		{kind: KindEntityMoved, entity: "Category", breaks: []string{"PortalRepo.FullCategory", "PortalRepo.DefaultCategorySort", "PortalRepo.CategoryByID", "PortalRepo.OneCategory", "PortalRepo.CategoriesByFilters", "PortalRepo.CountCategories", "PortalRepo.AddCategory", "PortalRepo.UpdateCategory", "PortalRepo.DeleteCategory"}},
+		{kind: KindAttributeRemoved, entity: "News", name: "Preview", breaks: []string{"News.Preview", "NewsSearch.Preview"}},
+		{kind: KindAttributeChanged, entity: "News", name: "Title", breaks: []string{"News.Title", "NewsSearch.Title"}},
+		{kind: KindAttributeChanged, entity: "News", name: "Content"},
+		{kind: KindAttributeAdded, entity: "News", name: "Views"},
+		{kind: KindSearchRemoved, entity: "News", name: "ContentILike", breaks: []string{"NewsSearch.ContentILike"}},
+		{kind: KindSearchAdded, entity: "News", name: "ViewsFrom"},
+		{kind: KindModeChanged, entity: "City", breaks: []string{"CityService.Add", "CityService.Update", "CityService.Delete", "CityService.Validate"}},
+		{kind: KindModeChanged, entity: "Region", breaks: []string{"RegionService"}},
+	}
+
+	for _, c := range tc {
+		t.Run(c.kind+" "+c.entity+" "+c.name, func(t *testing.T) {
+			change := changeByKind(changes, c.kind, c.entity, c.name)
+			if change == nil {
+				t.Fatalf("change not found in %v", changes)
+			}
+			if !slices.Equal(change.Breaks, c.breaks) {
+				t.Errorf("got breaks %v, want %v", change.Breaks, c.breaks)
+			}
+		})
+	}
+
+	if len(changes) != len(tc) {
+		t.Errorf("got %d changes, want %d: %v", len(changes), len(tc), changes)
+	}
+
+	if change := changeByKind(changes, KindAttributeChanged, "News", "Title"); change.Message != "attribute Title changed: go type string -> *string, nullable No -> Yes" {
+		t.Errorf("got message %q", change.Message)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	changes := []Change{
+		{Kind: KindSearchRemoved, Namespace: "portal", Entity: "News", Name: "IDs", Message: "search IDs removed, SEARCHTYPE_ARRAY by ID", Breaks: []string{"NewsSearch.IDs"}},
+		{Kind: KindAttributeAdded, Namespace: "portal", Entity: "News", Name: "Views", Message: "attribute Views added, int4 int"},
+	}
+
+	tc := []struct {
+		format, want string
+	}{
+		{format: FormatText, want: "portal.News: search IDs removed, SEARCHTYPE_ARRAY by ID\n    breaks: NewsSearch.IDs\nportal.News: attribute Views added, int4 int\n2 changes, 1 breaking\n"},
+		{format: FormatMarkdown, want: "### Breaking changes\n\n- **portal.News**: search IDs removed, SEARCHTYPE_ARRAY by ID, breaks `NewsSearch.IDs`\n\n### Changes\n\n- **portal.News**: attribute Views added, int4 int\n"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, changes, c.format); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != c.want {
+				t.Errorf("got %q, want %q", buf.String(), c.want)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, nil, FormatJSON); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("got %q, err=%v", buf.String(), err)
+	}
+}
+
+func TestLoadRevision(t *testing.T) {
+	project, err := LoadRevision(testMFD, "HEAD")
+	if err != nil {
+		t.Skipf("git revision is not available: %s", err)
+	}
+
+	if project.Entity("News") == nil {
+		t.Error("entity News not loaded from HEAD")
+	}
+
+	if _, err := LoadRevision(testMFD, "unknown-revision"); err == nil {
+		t.Error("expected error for unknown revision")
+	}
+}
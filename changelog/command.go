@@ -0,0 +1,232 @@
+package changelog
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	mfdFlag    = "mfd"
+	fromFlag   = "from"
+	toFlag     = "to"
+	formatFlag = "format"
+	outputFlag = "output"
+)
+
+// Options stores changelog command options
+type Options struct {
+	// From is an old mfd file or git revision if MFDPath is set
+	From string
+
+	// To is a new mfd file or git revision if MFDPath is set, working tree is used for empty revision
+	To string
+
+	// MFDPath stores path for mfd project in git repository, From and To are git revisions if it is set
+	MFDPath string
+
+	// Format of changelog: text, markdown or json
+	Format string
+
+	// Output file, stdout if empty
+	Output string
+}
+
+// Changelog compares two revisions of project and writes changes
+type Changelog struct {
+	options Options
+}
+
+// New creates changelog
+func New() *Changelog {
+	return &Changelog{}
+}
+
+// CreateCommand creates changelog command
+func CreateCommand() *cobra.Command {
+	changelog := New()
+
+	command := &cobra.Command{
+		Use:   "changelog",
+		Short: "Print changes of model and broken generated api between two project revisions",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := changelog.ReadFlags(command); err != nil {
+				log.Printf("read flags error: %s", err)
+				os.Exit(1)
+			}
+
+			if err := changelog.Write(); err != nil {
+				log.Printf("changelog error: %s", err)
+				os.Exit(1)
+			}
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+	}
+
+	changelog.AddFlags(command)
+
+	return command
+}
+
+// AddFlags adds flags to command
+func (c *Changelog) AddFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.String(fromFlag, "", "old mfd file path, or git revision if mfd is set")
+	flags.String(toFlag, "", "new mfd file path, or git revision if mfd is set, working tree if empty")
+	flags.StringP(mfdFlag, "m", "", "mfd file path in git repository to compare revisions")
+	flags.StringP(formatFlag, "f", FormatText, "changelog format: text, markdown or json")
+	flags.StringP(outputFlag, "o", "", "changelog file path, stdout if empty")
+}
+
+// ReadFlags reads flags from command
+func (c *Changelog) ReadFlags(command *cobra.Command) error {
+	var err error
+
+	flags := command.Flags()
+
+	if c.options.From, err = flags.GetString(fromFlag); err != nil {
+		return err
+	}
+
+	if c.options.To, err = flags.GetString(toFlag); err != nil {
+		return err
+	}
+
+	if c.options.MFDPath, err = flags.GetString(mfdFlag); err != nil {
+		return err
+	}
+
+	if c.options.Format, err = flags.GetString(formatFlag); err != nil {
+		return err
+	}
+
+	if c.options.Output, err = flags.GetString(outputFlag); err != nil {
+		return err
+	}
+
+	if c.options.From == "" {
+		return fmt.Errorf("required flag \"%s\" not set", fromFlag)
+	}
+	if c.options.MFDPath == "" && c.options.To == "" {
+		return fmt.Errorf("required flag \"%s\" not set", toFlag)
+	}
+
+	return nil
+}
+
+// Write loads both revisions of project, compares them and writes changelog
+func (c *Changelog) Write() error {
+	prev, err := c.load(c.options.From)
+	if err != nil {
+		return fmt.Errorf("load %s, err=%w", c.options.From, err)
+	}
+
+	next, err := c.load(c.options.To)
+	if err != nil {
+		return fmt.Errorf("load %s, err=%w", c.options.To, err)
+	}
+
+	var w io.Writer = os.Stdout
+	if c.options.Output != "" {
+		f, err := os.Create(c.options.Output)
+		if err != nil {
+			return fmt.Errorf("create changelog, err=%w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := Write(w, Compare(prev, next), c.options.Format); err != nil {
+		return fmt.Errorf("write changelog, err=%w", err)
+	}
+
+	return nil
+}
+
+// load loads project from file or from git revision if mfd path is set
+func (c *Changelog) load(source string) (*mfd.Project, error) {
+	if c.options.MFDPath == "" {
+		return mfd.LoadProject(source, false, 0)
+	}
+
+	if source == "" {
+		return mfd.LoadProject(c.options.MFDPath, false, 0)
+	}
+
+	return LoadRevision(c.options.MFDPath, source)
+}
+
+// LoadRevision loads project from git revision, directory of mfd file is extracted to temp dir,
+// so imported projects should be placed in the same directory.
+func LoadRevision(mfdPath, revision string) (*mfd.Project, error) {
+	cmd := exec.Command("git", "archive", "--format=tar", revision, ".")
+	cmd.Dir = filepath.Dir(mfdPath)
+
+	archive, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git archive, err=%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git archive, err=%w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "mfd-changelog-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extract(bytes.NewReader(archive), dir); err != nil {
+		return nil, fmt.Errorf("extract revision, err=%w", err)
+	}
+
+	return mfd.LoadProject(filepath.Join(dir, filepath.Base(mfdPath)), false, 0)
+}
+
+// extract writes regular files of tar archive to dir
+func extract(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg || !filepath.IsLocal(header.Name) {
+			continue
+		}
+
+		filename := filepath.Join(dir, header.Name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, content, 0644); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+)
+
+// Write writes changes to w in text, markdown or json format
+func Write(w io.Writer, changes []Change, format string) error {
+	switch format {
+	case FormatText, "":
+		return WriteText(w, changes)
+	case FormatMarkdown:
+		return WriteMarkdown(w, changes)
+	case FormatJSON:
+		return WriteJSON(w, changes)
+	}
+
+	return fmt.Errorf("unsupported format %s", format)
+}
+
+// WriteText writes changes one per line as namespace.Entity: message, broken api is listed below change
+func WriteText(w io.Writer, changes []Change) error {
+	for _, c := range changes {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", c.Location(), c.Message); err != nil {
+			return err
+		}
+
+		if c.Breaking() {
+			if _, err := fmt.Fprintf(w, "    breaks: %s\n", strings.Join(c.Breaks, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d changes, %d breaking\n", len(changes), Breaking(changes))
+	return err
+}
+
+// WriteMarkdown writes breaking and other changes as markdown lists for release notes
+func WriteMarkdown(w io.Writer, changes []Change) error {
+	var breaking, other []Change
+	for _, c := range changes {
+		if c.Breaking() {
+			breaking = append(breaking, c)
+		} else {
+			other = append(other, c)
+		}
+	}
+
+	var sb strings.Builder
+	if len(breaking) > 0 {
+		sb.WriteString("### Breaking changes\n\n")
+		for _, c := range breaking {
+			fmt.Fprintf(&sb, "- **%s**: %s, breaks `%s`\n", c.Location(), c.Message, strings.Join(c.Breaks, "`, `"))
+		}
+	}
+
+	if len(other) > 0 {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("### Changes\n\n")
+		for _, c := range other {
+			fmt.Fprintf(&sb, "- **%s**: %s\n", c.Location(), c.Message)
+		}
+	}
+
+	if sb.Len() == 0 {
+		sb.WriteString("No changes\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteJSON writes changes as json array
+func WriteJSON(w io.Writer, changes []Change) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(changes)
+}
+
+// Breaking returns count of breaking changes
+func Breaking(changes []Change) int {
+	count := 0
+	for _, c := range changes {
+		if c.Breaking() {
+			count++
+		}
+	}
+
+	return count
+}
@@ -5,7 +5,10 @@ import (
 	"os"
 
 	"github.com/vmkteam/mfd-generator/api"
+	"github.com/vmkteam/mfd-generator/changelog"
+	"github.com/vmkteam/mfd-generator/convert"
 	"github.com/vmkteam/mfd-generator/generators/dbtest"
+	"github.com/vmkteam/mfd-generator/generators/docs"
 	"github.com/vmkteam/mfd-generator/generators/model"
 	"github.com/vmkteam/mfd-generator/generators/repo"
 	"github.com/vmkteam/mfd-generator/generators/vt"
@@ -13,7 +16,13 @@ import (
 	"github.com/vmkteam/mfd-generator/generators/xml"
 	xmllang "github.com/vmkteam/mfd-generator/generators/xml-lang"
 	xmlvt "github.com/vmkteam/mfd-generator/generators/xml-vt"
+	"github.com/vmkteam/mfd-generator/lint"
 	"github.com/vmkteam/mfd-generator/mfd"
+	"github.com/vmkteam/mfd-generator/plugin"
+	"github.com/vmkteam/mfd-generator/refactor"
+	"github.com/vmkteam/mfd-generator/schema"
+	"github.com/vmkteam/mfd-generator/templates"
+	"github.com/vmkteam/mfd-generator/watch"
 
 	"github.com/spf13/cobra"
 )
@@ -44,6 +53,7 @@ var versionCmd = &cobra.Command{
 }
 
 func init() {
+	mfd.WithDryRun(root)
 	root.AddCommand(
 		xml.CreateCommand(),
 		xmlvt.CreateCommand(),
@@ -53,7 +63,16 @@ func init() {
 		repo.CreateCommand(),
 		vt.CreateCommand(),
 		vttmpl.CreateCommand(),
+		docs.CreateCommand(),
 		api.CreateCommand(),
+		lint.CreateCommand(),
+		convert.CreateCommand(),
+		schema.CreateCommand(),
+		refactor.CreateCommand(),
+		changelog.CreateCommand(),
+		watch.CreateCommand(),
+		plugin.CreateCommand(),
+		templates.CreateCommand(),
 		versionCmd,
 	)
 }
@@ -0,0 +1,203 @@
+package convert
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	mfdFlag    = "mfd"
+	formatFlag = "format"
+	outputFlag = "output"
+)
+
+// Options stores convert command options
+type Options struct {
+	// MFDPath stores path for source mfd project
+	MFDPath string
+
+	// Format of target project: xml, yaml or json, taken from output extension if empty
+	Format string
+
+	// Output is a path of target project file
+	Output string
+}
+
+// Converter converts mfd project files between xml, yaml and json
+type Converter struct {
+	options Options
+}
+
+// New creates converter
+func New() *Converter {
+	return &Converter{}
+}
+
+// CreateCommand creates convert command
+func CreateCommand() *cobra.Command {
+	converter := New()
+
+	command := &cobra.Command{
+		Use:   "convert",
+		Short: "Convert mfd project between xml, yaml and json",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := converter.ReadFlags(command); err != nil {
+				log.Printf("read flags error: %s", err)
+				os.Exit(1)
+			}
+
+			files, err := Convert(converter.options.MFDPath, converter.options.Output)
+			if err != nil {
+				log.Printf("convert error: %s", err)
+				os.Exit(1)
+			}
+
+			for _, file := range files {
+				log.Printf("written %s", file)
+			}
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+	}
+
+	converter.AddFlags(command)
+
+	return command
+}
+
+// AddFlags adds flags to command
+func (c *Converter) AddFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringP(mfdFlag, "m", "", "mfd file path")
+	flags.StringP(formatFlag, "f", "", "target format: xml, yaml or json")
+	flags.StringP(outputFlag, "o", "", "target project file path, format is chosen by its extension, e.g. newsportal.yaml")
+}
+
+// ReadFlags reads flags from command
+func (c *Converter) ReadFlags(command *cobra.Command) error {
+	var err error
+
+	flags := command.Flags()
+
+	if c.options.MFDPath, err = flags.GetString(mfdFlag); err != nil {
+		return err
+	}
+
+	if c.options.Format, err = flags.GetString(formatFlag); err != nil {
+		return err
+	}
+
+	if c.options.Output, err = flags.GetString(outputFlag); err != nil {
+		return err
+	}
+
+	if c.options.MFDPath == "" {
+		return fmt.Errorf("required flag \"%s\" not set", mfdFlag)
+	}
+
+	if c.options.Output == "" {
+		if c.options.Format == "" {
+			return fmt.Errorf("one of flags \"%s\" or \"%s\" should be set", formatFlag, outputFlag)
+		}
+		c.options.Output = OutputPath(c.options.MFDPath, c.options.Format)
+	} else if c.options.Format != "" && mfd.Format(c.options.Output) != c.options.Format {
+		return fmt.Errorf("output %s does not match format %s", c.options.Output, c.options.Format)
+	}
+
+	return nil
+}
+
+// OutputPath returns path of converted project placed next to source one, e.g. newsportal.mfd -> newsportal.yaml
+func OutputPath(mfdPath, format string) string {
+	name := strings.TrimSuffix(mfdPath, filepath.Ext(mfdPath))
+	if format == mfd.FormatXML {
+		return name + ".mfd"
+	}
+
+	return name + "." + format
+}
+
+// Convert copies project with namespaces, vt namespaces and translations from one format to another,
+// files are converted as is without loading links, so project is converted losslessly even if it is not consistent.
+// Returns list of written files.
+func Convert(from, to string) ([]string, error) {
+	source, target := mfd.StorageByFile(from), mfd.StorageByFile(to)
+
+	project := &mfd.Project{}
+	if err := convertFile(source, target, from, to, project); err != nil {
+		return nil, fmt.Errorf("convert project, err=%w", err)
+	}
+	written := []string{to}
+
+	type pair struct {
+		from, to string
+		v        interface{}
+	}
+
+	var files []pair
+	for _, namespace := range project.NamespaceNames {
+		files = append(files,
+			pair{mfd.NamespaceFile(from, namespace), mfd.NamespaceFile(to, namespace), &mfd.Namespace{}},
+			pair{mfd.VTNamespaceFile(from, namespace), mfd.VTNamespaceFile(to, namespace), &mfd.VTNamespace{}},
+		)
+	}
+
+	languages := project.Languages
+	// backward compatibility
+	if len(languages) == 0 {
+		languages = []string{mfd.EnLang}
+	}
+	for _, lang := range languages {
+		files = append(files, pair{mfd.TranslationFile(from, lang), mfd.TranslationFile(to, lang), &mfd.Translation{}})
+	}
+
+	for _, file := range files {
+		// vt namespaces and translations are optional
+		if _, err := os.Stat(file.from); os.IsNotExist(err) {
+			continue
+		}
+
+		if err := convertFile(source, target, file.from, file.to, file.v); err != nil {
+			return written, fmt.Errorf("convert %s, err=%w", file.from, err)
+		}
+		written = append(written, file.to)
+	}
+
+	return written, nil
+}
+
+func convertFile(source, target mfd.Storage, from, to string, v interface{}) error {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return fmt.Errorf("source and target files are the same")
+	}
+
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return fmt.Errorf("read file, err=%w", err)
+	}
+
+	if err := source.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unmarshal, err=%w", err)
+	}
+
+	if data, err = target.Marshal(v); err != nil {
+		return fmt.Errorf("marshal, err=%w", err)
+	}
+
+	if _, err := mfd.Save(data, to); err != nil {
+		return fmt.Errorf("write file, err=%w", err)
+	}
+
+	return nil
+}
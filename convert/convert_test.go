@@ -0,0 +1,64 @@
+package convert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+const testProject = "../generators/testdata/expected/newsportal.mfd"
+
+func TestConvert(t *testing.T) {
+	for _, format := range []string{mfd.FormatYAML, mfd.FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+
+			converted := filepath.Join(dir, format, OutputPath(filepath.Base(testProject), format))
+			files, err := Convert(testProject, converted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// project, 3 namespaces, 3 vt namespaces and en translation
+			if len(files) != 8 {
+				t.Errorf("Convert() written %d files: %v", len(files), files)
+			}
+
+			// converted project is loaded by generators
+			project, err := mfd.LoadProject(converted, false, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(project.Namespaces) != 3 || len(project.VTNamespaces) != 3 {
+				t.Errorf("unexpected project: %d namespaces, %d vt namespaces", len(project.Namespaces), len(project.VTNamespaces))
+			}
+
+			// converting back gives the same xml
+			back := filepath.Join(dir, "xml", filepath.Base(testProject))
+			if files, err = Convert(converted, back); err != nil {
+				t.Fatal(err)
+			}
+			for _, file := range files {
+				expected, err := os.ReadFile(filepath.Join(filepath.Dir(testProject), filepath.Base(file)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				actual, err := os.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(expected, actual) {
+					t.Errorf("%s differs after conversion:\n%s", filepath.Base(file), actual)
+				}
+			}
+		})
+	}
+}
+
+func TestConvert_SameFile(t *testing.T) {
+	if _, err := Convert(testProject, testProject); err == nil {
+		t.Error("expected error for the same source and target")
+	}
+}
@@ -20,6 +20,8 @@ CREATE TABLE "news" (
 	PRIMARY KEY("newsId")
 );
 
+COMMENT ON TABLE "news" IS 'Новости портала';
+
 COMMENT ON COLUMN "news"."newsId" IS 'id новости';
 
 COMMENT ON COLUMN "news"."title" IS 'Заголовок новости';
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/vmkteam/mfd-generator/mfd"
@@ -18,16 +21,26 @@ const (
 	defaultSentenceLen = 100
 )
 
+// FakeFiller generates fake values of columns and collects imports used by them, it is safe for concurrent use
 type FakeFiller struct {
+	mu      sync.Mutex
 	imports map[string]struct{}
 }
 
-func NewFakeFiller() FakeFiller { return FakeFiller{imports: make(map[string]struct{})} }
+func NewFakeFiller() *FakeFiller { return &FakeFiller{imports: make(map[string]struct{})} }
+
+// addImport remembers import used by generated value
+func (ff *FakeFiller) addImport(pkg string) {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+
+	ff.imports[pkg] = struct{}{}
+}
 
 // ByNameAndType Checks column name if it is a known name with a special fake func substitution
 //
 //nolint:funlen
-func (ff FakeFiller) ByNameAndType(columnName, gotype string, maxFiledLen int) (res template.HTML, found bool) {
+func (ff *FakeFiller) ByNameAndType(columnName, gotype string, maxFiledLen int) (res template.HTML, found bool) {
 	switch columnName {
 	case "StatusID":
 		//nolint:gocritic
@@ -40,7 +53,7 @@ func (ff FakeFiller) ByNameAndType(columnName, gotype string, maxFiledLen int) (
 	case "Phone":
 		switch gotype {
 		case model.TypeInt, model.TypeInt32, model.TypeInt64, model.TypeFloat32, model.TypeFloat64:
-			ff.imports["strconv"] = struct{}{}
+			ff.addImport("strconv")
 			return template.HTML(fmt.Sprintf("in.Phone, _ = strconv.Atoi(%s)", fakePhone.cutString(maxFiledLen))), true
 		case model.TypeString:
 			return fakePhone.cutString(maxFiledLen).assign(columnName).Tmpl(), true
@@ -51,7 +64,7 @@ func (ff FakeFiller) ByNameAndType(columnName, gotype string, maxFiledLen int) (
 		//nolint:gocritic
 		switch gotype {
 		case model.TypeString:
-			ff.imports["strings"] = struct{}{}
+			ff.addImport("strings")
 			switch {
 			case maxFiledLen == 0:
 				return fakeEmpty.sentence(defaultSentenceLen).cutString(maxFiledLen).replaceAll(" ", "-").assign(columnName).Tmpl(), true
@@ -91,10 +104,10 @@ func (ff FakeFiller) ByNameAndType(columnName, gotype string, maxFiledLen int) (
 	case "CreatedAt":
 		switch gotype {
 		case model.TypeTime:
-			ff.imports["time"] = struct{}{}
+			ff.addImport("time")
 			return fakeNow.assign(columnName).Tmpl(), true
 		case model.TypeString:
-			ff.imports["time"] = struct{}{}
+			ff.addImport("time")
 			return fakeNow.formatRFC3339().assign(columnName).Tmpl(), true
 		}
 
@@ -106,10 +119,10 @@ func (ff FakeFiller) ByNameAndType(columnName, gotype string, maxFiledLen int) (
 		"PublishedAt", "PublishedDate", "PublishDate", "PublishAt":
 		switch gotype {
 		case model.TypeTime:
-			ff.imports["time"] = struct{}{}
+			ff.addImport("time")
 			return fakeRangeDateFuture.assign(columnName).Tmpl(), true
 		case model.TypeString:
-			ff.imports["time"] = struct{}{}
+			ff.addImport("time")
 			return fakeRangeDateFuture.formatRFC3339().assign(columnName).Tmpl(), true
 		}
 
@@ -119,10 +132,10 @@ func (ff FakeFiller) ByNameAndType(columnName, gotype string, maxFiledLen int) (
 	return "", false
 }
 
-func (ff FakeFiller) ByType(colName, goType, dbType string, isArray bool, maxFiledLen int) (res template.HTML, found bool) {
+func (ff *FakeFiller) ByType(colName, goType, dbType string, isArray bool, maxFiledLen int) (res template.HTML, found bool) {
 	switch dbType {
 	case model.TypePGPoint:
-		ff.imports["fmt"] = struct{}{}
+		ff.addImport("fmt")
 		return FakeIt(fmt.Sprintf(`"("+%s+","+%s+")"`, fakeLat, fakeLon)).assign(colName).Tmpl(), true
 	case model.TypePGUuid:
 		return fakeUUID.assign(colName).Tmpl(), true
@@ -158,7 +171,7 @@ func (ff FakeFiller) ByType(colName, goType, dbType string, isArray bool, maxFil
 	case model.TypeBool:
 		return fakeBool.assign(colName).Tmpl(), true
 	case model.TypeTime:
-		ff.imports["time"] = struct{}{}
+		ff.addImport("time")
 		return fakeRangeDateFuture.assign(colName).Tmpl(), true
 	case model.TypeDuration:
 		return FakeIt(fmt.Sprintf("gofakeit.IntRange(%d, %d)", time.Second.Nanoseconds(), (24 * time.Hour).Nanoseconds())).assign(colName).Tmpl(), true
@@ -167,10 +180,10 @@ func (ff FakeFiller) ByType(colName, goType, dbType string, isArray bool, maxFil
 	case model.TypeMapString:
 		return FakeIt("map[string]string{gofakeit.InputName(): gofakeit.Word()}").assign(colName).Tmpl(), true
 	case model.TypeIP:
-		ff.imports["net"] = struct{}{}
+		ff.addImport("net")
 		return fakeEmpty.ipv4().assign(colName).Tmpl(), true
 	case model.TypeIPNet:
-		ff.imports["net"] = struct{}{}
+		ff.addImport("net")
 		return fakeEmpty.ipv4Net().assign(colName).Tmpl(), true
 	case model.TypeInterface:
 		return fakeWord.cutString(maxFiledLen).assign(colName).Tmpl(), true
@@ -181,13 +194,12 @@ func (ff FakeFiller) ByType(colName, goType, dbType string, isArray bool, maxFil
 	return "", false
 }
 
-func (ff FakeFiller) Imports() []string {
-	res := make([]string, 0, len(ff.imports))
-	for i := range ff.imports {
-		res = append(res, i)
-	}
+// Imports returns sorted imports used by generated values
+func (ff *FakeFiller) Imports() []string {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
 
-	return res
+	return slices.Sorted(maps.Keys(ff.imports))
 }
 
 const (
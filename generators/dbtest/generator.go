@@ -2,11 +2,11 @@ package dbtest
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"os"
 	"path"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/vmkteam/mfd-generator/mfd"
@@ -22,27 +22,71 @@ const (
 	nssFlag      = "namespaces"
 	entitiesFlag = "entities"
 	forceFlag    = "force"
+	jobsFlag     = "jobs"
 
-	FuncPattern = `^func (\w+)`
-)
+	templatesDirFlag = "templates-dir"
 
-var (
-	funcRe = regexp.MustCompile(FuncPattern)
+	// FuncPattern matched functions replaced in existing files.
+	//
+	// Deprecated: declarations are found with go/ast, pattern is not used by generator.
+	FuncPattern = `^func (\w+)`
 )
 
 // CreateCommand creates generator command
 func CreateCommand() *cobra.Command {
-	return base.CreateCommand("dbtest", "Create or update functions from xml for inserting testdata into tables", New())
+	command := base.CreateCommand("dbtest", "Create or update functions from xml for inserting testdata into tables", New())
+	command.Long = command.Short + "\n\n" + mfd.KeepHelp
+
+	return command
+}
+
+// Templates returns built-in templates which could be exported to templates dir and customized
+func Templates() []mfd.BuiltinTemplate {
+	return []mfd.BuiltinTemplate{
+		{Name: "setup", Source: baseFileTemplate},
+		{Name: "funcs", Source: funcFileTemplate},
+		{Name: "optype", Source: opFuncTypeTemplate},
+		{Name: "func", Source: funcTemplate},
+		{Name: "op-with-relations", Source: funcOpWithRelTemplate},
+		{Name: "op-with-fake", Source: funcOpWithFakeTemplate},
+	}
 }
 
 // Generator represents mfd generator
 type Generator struct {
 	options Options
+	files   *mfd.Files
+
+	// templates are custom templates from templates dir
+	templates *mfd.Templates
 }
 
 // New creates generator
 func New() *Generator {
-	return &Generator{}
+	return &Generator{files: mfd.NewFiles(nil)}
+}
+
+// Runner runs dbtest generator without cobra command
+type Runner = mfd.Runner[Options]
+
+// NewGenerator creates dbtest generator for usage as a library, default options are filled as in command
+func NewGenerator(options Options) *Runner {
+	return mfd.NewRunner(options, func(options *Options) {
+		if options.Package == "" {
+			options.Package = path.Base(options.Output)
+		}
+		options.Def()
+	}, run)
+}
+
+// run runs generator with files if required options are set
+func run(ctx context.Context, options Options, files *mfd.Files) error {
+	if options.MFDPath == "" || options.Output == "" {
+		return errors.New("mfd file and output dir are required")
+	}
+
+	g := &Generator{options: options, files: files}
+	return g.generate(ctx)
 }
 
 // AddFlags adds flags to command
@@ -71,6 +115,9 @@ func (g *Generator) AddFlags(command *cobra.Command) {
 	flags.StringSliceP(entitiesFlag, "e", []string{}, "entities to generate. Separate by comma\n")
 
 	flags.BoolP(forceFlag, "f", false, "force generate if functions already exist. Deletes old and generates new functions")
+	flags.IntP(jobsFlag, "j", 0, "count of namespaces generated concurrently. If not set - count of CPUs will be used\n")
+
+	flags.String(templatesDirFlag, "", "path to custom templates dir, see templates export command\n")
 }
 
 // ReadFlags reads basic flags from command
@@ -109,15 +156,27 @@ func (g *Generator) ReadFlags(command *cobra.Command) (err error) {
 		return err
 	}
 
+	if g.options.Jobs, err = flags.GetInt(jobsFlag); err != nil {
+		return err
+	}
+
+	if g.options.TemplatesDir, err = flags.GetString(templatesDirFlag); err != nil {
+		return err
+	}
+
 	g.options.Def()
 
 	return
 }
 
 // Generate runs generator
-func (g *Generator) Generate() (err error) {
-	// loading project from file
-	project, err := mfd.LoadProject(g.options.MFDPath, false, 0)
+func (g *Generator) Generate() error {
+	return g.generate(context.Background())
+}
+
+func (g *Generator) generate(ctx context.Context) error {
+	// loading project from file, only generated namespaces and their dependencies are read
+	project, err := mfd.LoadProjectNamespaces(g.options.MFDPath, 0, g.options.Namespaces)
 	if err != nil {
 		return err
 	}
@@ -130,6 +189,10 @@ func (g *Generator) Generate() (err error) {
 		return err
 	}
 
+	if g.templates, err = mfd.LoadTemplates(g.options.TemplatesDir, "dbtest"); err != nil {
+		return fmt.Errorf("load templates, err=%w", err)
+	}
+
 	if _, err := g.SaveSetupFile(); err != nil {
 		return fmt.Errorf("generate setup file, err=%w", err)
 	}
@@ -138,46 +201,67 @@ func (g *Generator) Generate() (err error) {
 		g.options.Namespaces = project.NamespaceNames
 	}
 
+	var tasks []mfd.Task
 	for _, namespace := range g.options.Namespaces {
 		// Walk through each namespace and check if they have already had file and extract function names from them
 		// Note: consider that the func names are distinct across all namespaces (because they have the same pkg)
 		if ns := project.Namespace(namespace); ns != nil {
-			// Generate test helpers
-			err = g.generateFuncsByNS(ns)
-			if err != nil {
-				return fmt.Errorf("failed to generate test helpers: %w", err)
-			}
+			tasks = append(tasks, func(files *mfd.Files) error {
+				// Generate test helpers
+				if err := g.withFiles(files).generateFuncsByNS(ns); err != nil {
+					return fmt.Errorf("failed to generate test helpers: %w", err)
+				}
+				return nil
+			})
 		}
 	}
 
-	return nil
+	return g.files.Parallel(ctx, g.options.Jobs, tasks)
+}
+
+// withFiles returns copy of generator which writes files to files
+func (g *Generator) withFiles(files *mfd.Files) *Generator {
+	clone := *g
+	clone.files = files
+
+	return &clone
 }
 
 func (g *Generator) SaveSetupFile() (bool, error) {
 	output := path.Join(g.options.Output, "test.go")
 	isForce := g.options.Force && (len(g.options.Namespaces) == 0 || len(g.options.Entities) == 0)
-	if !isForce && fileExists(output) {
+	if !isForce && g.files.Exists(output) {
 		return false, nil
 	}
 
+	tmpl, err := g.templates.Template("setup", "", baseFileTemplate)
+	if err != nil {
+		return false, fmt.Errorf("load setup file template, err=%w", err)
+	}
+
 	buffer := new(bytes.Buffer)
-	if err := mfd.RenderText(buffer, baseFileTemplate, PackFuncRenderData(g.options)); err != nil {
+	if err := mfd.RenderText(buffer, tmpl, PackFuncRenderData(g.options), g.templates.Partials()...); err != nil {
 		return false, fmt.Errorf("processing setup file template, err=%w", err)
 	}
 
-	return mfd.Save(buffer.Bytes(), output)
+	return g.files.Save(buffer.Bytes(), output)
 }
 
 func (g *Generator) CreateFuncFile(ns NamespaceData) (bool, error) {
 	// Getting file name without dots
 	output := filepath.Join(g.options.Output, mfd.GoFileName(ns.Name)+".go")
 
-	buffer := new(bytes.Buffer)
-	if err := mfd.Render(buffer, funcFileTemplate, ns); err != nil {
+	tmpl, err := g.templates.Template("funcs", "", funcFileTemplate)
+	if err != nil {
+		return false, fmt.Errorf("load func file template, err=%w", err)
+	}
+
+	// declarations marked with mfd:keep are kept in existing file
+	if _, err := g.files.FormatAndSave(ns, output, tmpl, true, g.templates.Partials()...); err != nil {
 		return false, fmt.Errorf("processing func file template, err=%w", err)
 	}
 
-	return mfd.Save(buffer.Bytes(), output)
+	return true, nil
 }
 
 // generateFuncsByNS generates the test helper functions
@@ -187,7 +271,7 @@ func (g *Generator) generateFuncsByNS(ns *mfd.Namespace) error {
 	nsData := PackNamespace(ns, g.options)
 	checkEntities := len(g.options.Entities) > 0 && !nsData.HasAllOfProvidedEntities(g.options.Entities)
 
-	if !fileExists(output) || (len(g.options.Entities) == 0 && g.options.Force) {
+	if !g.files.Exists(output) || (len(g.options.Entities) == 0 && g.options.Force) {
 		if _, err := g.CreateFuncFile(nsData); err != nil {
 			return fmt.Errorf("create file for functions, ns=%s, err=%w", ns.Name, err)
 		}
@@ -198,6 +282,24 @@ func (g *Generator) generateFuncsByNS(ns *mfd.Namespace) error {
 		entities[g.options.Entities[i]] = struct{}{}
 	}
 
+	// Loading declaration templates
+	opFuncType, err := g.layout("optype", opFuncTypeTemplate)
+	if err != nil {
+		return err
+	}
+	mainFunc, err := g.layout("func", funcTemplate)
+	if err != nil {
+		return err
+	}
+	opFuncWithRelations, err := g.layout("op-with-relations", funcOpWithRelTemplate)
+	if err != nil {
+		return err
+	}
+	opFuncWithFake, err := g.layout("op-with-fake", funcOpWithFakeTemplate)
+	if err != nil {
+		return err
+	}
+
 	// Render funcs for each entity
 	for _, entity := range nsData.Entities {
 		if _, ok := entities[entity.VarName]; !ok && checkEntities {
@@ -205,24 +307,22 @@ func (g *Generator) generateFuncsByNS(ns *mfd.Namespace) error {
 		}
 
 		// Render opFunc type struct
-		// Make a regexp with entity name to prevent removing OpFunc types despite the entity
-		typeOpFuncRe := regexp.MustCompile(fmt.Sprintf(`^type %[1]sOpFunc func\(t \*testing\.T, dbo orm\.DB, in \*db\.%[1]s\) Cleaner`, entity.Name))
-		if err := g.replaceTargetFromFile(OpFuncType{}, typeOpFuncRe, entity, output, "", ""); err != nil {
+		if err := g.replaceTargetFromFile(opFuncType, entity, output); err != nil {
 			return fmt.Errorf("replace the main func, entity=%s, err=%w", entity.Name, err)
 		}
 
 		// Render the main func
-		if err := g.replaceTargetFromFile(MainFunc{}, funcRe, entity, output, "{", "}"); err != nil {
+		if err := g.replaceTargetFromFile(mainFunc, entity, output); err != nil {
 			return fmt.Errorf("replace the main func, entity=%s, err=%w", entity.Name, err)
 		}
 
 		// Render WithRelations opFunc
-		if err := g.replaceTargetFromFile(OpFuncWithRelations{}, funcRe, entity, output, "{", "}"); err != nil {
+		if err := g.replaceTargetFromFile(opFuncWithRelations, entity, output); err != nil {
 			return fmt.Errorf("replace the main func, entity=%s, err=%w", entity.Name, err)
 		}
 
 		// Render WithFake opFunc
-		if err := g.replaceTargetFromFile(OpFuncWithFake{}, funcRe, entity, output, "{", "}"); err != nil {
+		if err := g.replaceTargetFromFile(opFuncWithFake, entity, output); err != nil {
 			return fmt.Errorf("replace the main func, entity=%s, err=%w", entity.Name, err)
 		}
 	}
@@ -230,9 +330,19 @@ func (g *Generator) generateFuncsByNS(ns *mfd.Namespace) error {
 	return nil
 }
 
-// replaceTargetFromFile removes specified functions from a Go file
+// layout returns renderer of declaration with custom or built-in template
+func (g *Generator) layout(name, def string) (FuncLayoutRenderer, error) {
+	tmpl, err := g.templates.Template(name, "", def)
+	if err != nil {
+		return nil, fmt.Errorf("load %s template, err=%w", name, err)
+	}
+
+	return templateLayout{tmpl: tmpl, partials: g.templates.Partials()}, nil
+}
+
+// replaceTargetFromFile renders declaration of entity and adds or replaces it in a Go file
 // The target could be func, struct or type OpFunc
-func (g *Generator) replaceTargetFromFile(b FuncLayoutRenderer, targetReExpression *regexp.Regexp, entity EntityData, filePath, openingToken, closeningToken string) error {
+func (g *Generator) replaceTargetFromFile(b FuncLayoutRenderer, entity EntityData, filePath string) error {
 	buf := new(bytes.Buffer)
 	if err := b.Render(buf, entity); err != nil {
 		return fmt.Errorf("render the main func, entity=%s, err=%w", entity.Name, err)
@@ -243,14 +353,9 @@ func (g *Generator) replaceTargetFromFile(b FuncLayoutRenderer, targetReExpressi
 		return nil
 	}
 
-	if _, err := mfd.UpdateFile(buf, filePath, openingToken, closeningToken, targetReExpression, g.options.Force); err != nil {
+	if _, err := g.files.UpdateFile(buf, filePath, g.options.Force); err != nil {
 		return fmt.Errorf("update file, err=%w", err)
 	}
 
 	return nil
 }
-
-func fileExists(path string) bool {
-	_, err := os.Stat(path)
-	return err == nil || !os.IsNotExist(err)
-}
@@ -282,7 +282,8 @@ func PackEntity(entity mfd.Entity, parentEntity *model.EntityData, namespace str
 	sameRelNamesMap := make(map[string]RelationData, len(te.Relations))
 	relNamesWhichHasRels := make(map[string]struct{}, len(te.Relations))
 	for i := range te.Relations {
-		if te.Relations[i].Entity.Name == te.Name {
+		// imported entities are created by tests of imported project
+		if te.Relations[i].Entity.Name == te.Name || te.Relations[i].Entity.IsImported() {
 			continue
 		}
 
@@ -604,6 +605,16 @@ func (op OpFuncWithFake) Render(w io.Writer, data any) error {
 	return loadAndParseTemplate(w, funcOpWithFakeTemplate, data)
 }
 
+// templateLayout renders declaration with custom template and partials
+type templateLayout struct {
+	tmpl     string
+	partials []string
+}
+
+func (l templateLayout) Render(w io.Writer, data any) error {
+	return mfd.Render(w, l.tmpl, data, l.partials...)
+}
+
 func loadAndParseTemplate(w io.Writer, tmpl string, data any) error {
 	return mfd.Render(w, tmpl, data)
 }
@@ -37,6 +37,12 @@ type Options struct {
 	// Force Replaces existing functions
 	Force bool
 
+	// Jobs is a count of namespaces generated concurrently, count of CPUs is used if not set
+	Jobs int
+
+	// TemplatesDir stores custom templates and partials in dbtest subdir
+	TemplatesDir string
+
 	// custom types
 	CustomTypes mfd.CustomTypes
 }
@@ -0,0 +1,272 @@
+package docs
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+// Diagram formats
+const (
+	FormatMermaid = "mermaid"
+	FormatDOT     = "dot"
+)
+
+// DiagramFormats are supported formats of ER diagram
+var DiagramFormats = []string{FormatMermaid, FormatDOT}
+
+// Diagram is an ER diagram of entities built from foreign keys of attributes,
+// entities referenced from diagram but not included in it are drawn without attributes.
+type Diagram struct {
+	// Name of diagram, used as graph name in DOT
+	Name string
+
+	// Entities to draw in order
+	Entities []*mfd.Entity
+
+	// FKOnly draws only primary and foreign key attributes
+	FKOnly bool
+
+	// Modes stores vt modes by entity name, they are shown in entity titles
+	Modes map[string]string
+
+	// closed diagram draws only links between its entities
+	closed bool
+}
+
+// NewDiagram creates diagram of namespaces entities
+func NewDiagram(name string, namespaces ...*mfd.Namespace) Diagram {
+	diagram := Diagram{Name: name}
+	for _, ns := range namespaces {
+		diagram.Entities = append(diagram.Entities, ns.Entities...)
+	}
+
+	return diagram
+}
+
+// WithModes sets vt modes of diagram entities from project
+func (d Diagram) WithModes(project *mfd.Project) Diagram {
+	d.Modes = map[string]string{}
+	for _, entity := range d.Entities {
+		if vtEntity := project.VTEntity(entity.Name); vtEntity != nil {
+			d.Modes[entity.Name] = vtEntity.Mode
+		}
+	}
+
+	return d
+}
+
+// Focus leaves entity and its neighbours in given number of foreign key hops in both directions
+func (d Diagram) Focus(entity string, hops int) (Diagram, error) {
+	index := slices.IndexFunc(d.Entities, func(e *mfd.Entity) bool { return e.Name == entity })
+	if index == -1 {
+		return d, fmt.Errorf("entity %s not found in diagram", entity)
+	}
+
+	// foreign key links in both directions
+	links := map[string][]string{}
+	for _, e := range d.Entities {
+		for _, attr := range e.Attributes {
+			if attr.ForeignEntity != nil {
+				links[e.Name] = append(links[e.Name], attr.ForeignEntity.Name)
+				links[attr.ForeignEntity.Name] = append(links[attr.ForeignEntity.Name], e.Name)
+			}
+		}
+	}
+
+	focused := map[string]struct{}{entity: {}}
+	current := []string{entity}
+	for range hops {
+		var next []string
+		for _, name := range current {
+			for _, linked := range links[name] {
+				if _, ok := focused[linked]; !ok {
+					focused[linked] = struct{}{}
+					next = append(next, linked)
+				}
+			}
+		}
+		current = next
+	}
+
+	d.Entities = slices.DeleteFunc(slices.Clone(d.Entities), func(e *mfd.Entity) bool {
+		_, ok := focused[e.Name]
+		return !ok
+	})
+	d.closed = true
+
+	return d, nil
+}
+
+// Render renders diagram in mermaid or dot format
+func (d Diagram) Render(format string) (string, error) {
+	switch format {
+	case FormatMermaid:
+		return d.Mermaid(), nil
+	case FormatDOT:
+		return d.DOT(), nil
+	}
+
+	return "", fmt.Errorf("unknown diagram format %s, use one of: %s", format, strings.Join(DiagramFormats, ", "))
+}
+
+// Mermaid renders diagram as mermaid erDiagram
+func (d Diagram) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+
+	for _, entity := range d.Entities {
+		if title := d.title(entity); title != entity.Name {
+			fmt.Fprintf(&b, "    %s[%q] {\n", entity.Name, title)
+		} else {
+			fmt.Fprintf(&b, "    %s {\n", entity.Name)
+		}
+		for _, attr := range d.attributes(entity) {
+			fmt.Fprintf(&b, "        %s %s", diagramType(attr), attr.Name)
+			if keys := attributeKeys(attr); keys != "" {
+				b.WriteString(" " + keys)
+			}
+			if attr.Description != "" {
+				fmt.Fprintf(&b, " %q", mermaidText(attr.Description))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, entity := range d.Entities {
+		for _, attr := range d.links(entity) {
+			fmt.Fprintf(&b, "    %s %s %s : %q\n", entity.Name, mermaidCardinality(attr), attr.ForeignEntity.Name, attr.Name)
+		}
+	}
+
+	return b.String()
+}
+
+// DOT renders diagram as graphviz digraph with html table nodes
+func (d Diagram) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", d.Name)
+	b.WriteString("    graph [rankdir=LR];\n")
+	b.WriteString("    node [shape=plain];\n")
+	b.WriteString("    edge [arrowhead=none, arrowtail=crow, dir=both];\n")
+
+	drawn := map[string]struct{}{}
+	for _, entity := range d.Entities {
+		drawn[entity.Name] = struct{}{}
+
+		fmt.Fprintf(&b, `    %q [label=<<table border="0" cellborder="1" cellspacing="0" cellpadding="4">`, entity.Name)
+		fmt.Fprintf(&b, `<tr><td bgcolor="lightgrey"><b>%s</b></td></tr>`, html.EscapeString(d.title(entity)))
+		for _, attr := range d.attributes(entity) {
+			label := attr.Name + ": " + diagramType(attr)
+			if keys := attributeKeys(attr); keys != "" {
+				label += " " + keys
+			}
+			fmt.Fprintf(&b, `<tr><td port=%q align="left">%s</td></tr>`, attr.Name, html.EscapeString(label))
+		}
+		b.WriteString("</table>>];\n")
+	}
+
+	for _, entity := range d.Entities {
+		for _, attr := range d.links(entity) {
+			target := fmt.Sprintf("%q", attr.ForeignEntity.Name)
+			if pks := attr.ForeignEntity.PKs(); len(pks) == 1 {
+				if _, ok := drawn[attr.ForeignEntity.Name]; ok {
+					target += fmt.Sprintf(":%q", pks[0].Name)
+				}
+			}
+
+			style := ""
+			if attr.Nullable() {
+				style = ", style=dashed"
+			}
+			fmt.Fprintf(&b, "    %q:%q -> %s [label=%q%s];\n", entity.Name, attr.Name, target, attr.Name, style)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// title returns entity name with vt mode if modes are set
+func (d Diagram) title(entity *mfd.Entity) string {
+	if mode := d.Modes[entity.Name]; mode != "" {
+		return entity.Name + " (" + mode + ")"
+	}
+
+	return entity.Name
+}
+
+// attributes returns attributes of entity to draw
+func (d Diagram) attributes(entity *mfd.Entity) mfd.Attributes {
+	if !d.FKOnly {
+		return entity.Attributes
+	}
+
+	return slices.DeleteFunc(slices.Clone(entity.Attributes), func(attr *mfd.Attribute) bool {
+		return !attr.PrimaryKey && attr.ForeignKey == ""
+	})
+}
+
+// links returns foreign key attributes of entity to draw as relations
+func (d Diagram) links(entity *mfd.Entity) mfd.Attributes {
+	var links mfd.Attributes
+	for _, attr := range entity.Attributes {
+		if attr.ForeignEntity == nil {
+			continue
+		}
+		if d.closed && !slices.ContainsFunc(d.Entities, func(e *mfd.Entity) bool { return e.Name == attr.ForeignEntity.Name }) {
+			continue
+		}
+		links = append(links, attr)
+	}
+
+	return links
+}
+
+// diagramType returns db type of attribute without spaces
+func diagramType(attr *mfd.Attribute) string {
+	dbType := strings.ReplaceAll(attr.DBType, " ", "_")
+	if dbType == "" {
+		dbType = "unknown"
+	}
+	if attr.IsArray {
+		dbType += "[]"
+	}
+
+	return dbType
+}
+
+// attributeKeys returns PK and FK markers of attribute
+func attributeKeys(attr *mfd.Attribute) string {
+	var keys []string
+	if attr.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if attr.ForeignKey != "" {
+		keys = append(keys, "FK")
+	}
+
+	return strings.Join(keys, ", ")
+}
+
+// mermaidCardinality returns relation from entity to foreign entity: many to one, optional for nullable and array keys
+func mermaidCardinality(attr *mfd.Attribute) string {
+	switch {
+	case attr.IsArray:
+		return "}o--o{"
+	case attr.Nullable():
+		return "}o--o|"
+	default:
+		return "}o--||"
+	}
+}
+
+// mermaidText makes single line comment without double quotes
+func mermaidText(text string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(text, `"`, "'")), " ")
+}
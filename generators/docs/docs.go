@@ -0,0 +1,180 @@
+package docs
+
+import (
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+// IndexName is a file name of project page and diagram without extension
+const IndexName = "index"
+
+// ProjectData stores project info for index page
+type ProjectData struct {
+	Name string
+
+	Namespaces []NamespaceData
+
+	Mermaid string
+}
+
+// PackProject packs project namespaces to template data
+func PackProject(project *mfd.Project, namespaces []*mfd.Namespace) ProjectData {
+	files := map[string]string{}
+	for _, ns := range namespaces {
+		for _, entity := range ns.Entities {
+			files[entity.Name] = NamespaceFile(ns.Name)
+		}
+	}
+
+	data := ProjectData{
+		Name:    project.Name,
+		Mermaid: NewDiagram(project.Name, namespaces...).Mermaid(),
+	}
+
+	for _, ns := range namespaces {
+		data.Namespaces = append(data.Namespaces, PackNamespace(project, ns, files))
+	}
+
+	return data
+}
+
+// NamespaceFile returns file name of namespace page and diagrams without extension
+func NamespaceFile(namespace string) string {
+	return mfd.GoFileName(namespace)
+}
+
+// NamespaceData stores namespace info for namespace page
+type NamespaceData struct {
+	Name    string
+	Project string
+	File    string
+
+	Entities []EntityData
+
+	Mermaid string
+}
+
+// PackNamespace packs namespace to template data, files are pages of entities used for links
+func PackNamespace(project *mfd.Project, namespace *mfd.Namespace, files map[string]string) NamespaceData {
+	data := NamespaceData{
+		Name:    namespace.Name,
+		Project: project.Name,
+		File:    NamespaceFile(namespace.Name),
+		Mermaid: NewDiagram(namespace.Name, namespace).Mermaid(),
+	}
+
+	for _, entity := range namespace.Entities {
+		data.Entities = append(data.Entities, PackEntity(project, entity, files))
+	}
+
+	return data
+}
+
+// EntityData stores entity info
+type EntityData struct {
+	Name        string
+	Table       string
+	Description string
+	Anchor      string
+
+	// Mode is a mode of vt entity, empty if entity has no vt entity
+	Mode string
+
+	Attributes []AttributeData
+	Searches   []SearchData
+}
+
+// PackEntity packs entity to template data
+func PackEntity(project *mfd.Project, entity *mfd.Entity, files map[string]string) EntityData {
+	data := EntityData{
+		Name:        entity.Name,
+		Table:       entity.Table,
+		Description: entity.Description,
+		Anchor:      anchor(entity.Name),
+	}
+
+	if vtEntity := project.VTEntity(entity.Name); vtEntity != nil {
+		data.Mode = vtEntity.Mode
+	}
+
+	for _, attr := range entity.Attributes {
+		data.Attributes = append(data.Attributes, PackAttribute(attr, files))
+	}
+
+	for _, search := range entity.Searches {
+		data.Searches = append(data.Searches, SearchData{
+			Name:       search.Name,
+			AttrName:   search.AttrName,
+			SearchType: string(search.SearchType),
+		})
+	}
+
+	return data
+}
+
+// AttributeData stores attribute info
+type AttributeData struct {
+	Name        string
+	DBName      string
+	DBType      string
+	GoType      string
+	Nullable    string
+	Keys        string
+	Description string
+
+	// ForeignKey is a name of foreign entity
+	ForeignKey string
+	// ForeignFile is a page of foreign entity without extension, empty if entity is not documented
+	ForeignFile   string
+	ForeignAnchor string
+}
+
+// PackAttribute packs attribute to template data
+func PackAttribute(attr *mfd.Attribute, files map[string]string) AttributeData {
+	data := AttributeData{
+		Name:        attr.Name,
+		DBName:      attr.DBName,
+		DBType:      diagramType(attr),
+		GoType:      attr.GoType,
+		Nullable:    attr.Null,
+		Keys:        attributeKeys(attr),
+		Description: attr.Description,
+		ForeignKey:  attr.ForeignKey,
+	}
+
+	if attr.ForeignEntity != nil {
+		data.ForeignKey = attr.ForeignEntity.Name
+		data.ForeignFile = files[attr.ForeignEntity.Name]
+		data.ForeignAnchor = anchor(attr.ForeignEntity.Name)
+	}
+
+	return data
+}
+
+// MarkdownDescription returns description for markdown table cell
+func (a AttributeData) MarkdownDescription() string {
+	return markdownCell(a.Description)
+}
+
+// SearchData stores search info
+type SearchData struct {
+	Name       string
+	AttrName   string
+	SearchType string
+}
+
+// anchor returns id of entity section, it is the same as markdown heading anchor
+func anchor(name string) string {
+	return strings.ToLower(name)
+}
+
+// markdownCell escapes pipes and replaces line breaks in markdown table cell
+func markdownCell(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(strings.TrimSpace(line), "|", `\|`)
+	}
+
+	return strings.Join(lines, "<br>")
+}
@@ -0,0 +1,198 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/dizzyfool/genna/generators/base"
+	"github.com/spf13/cobra"
+)
+
+const (
+	mfdFlag     = "mfd"
+	nsFlag      = "namespaces"
+	formatsFlag = "formats"
+)
+
+// Formats of generated files
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// Formats are all supported formats of generated files
+var Formats = []string{FormatMarkdown, FormatHTML, FormatMermaid, FormatDOT}
+
+// extensions of generated files by format
+var extensions = map[string]string{
+	FormatMarkdown: ".md",
+	FormatHTML:     ".html",
+	FormatMermaid:  ".mmd",
+	FormatDOT:      ".dot",
+}
+
+// CreateCommand creates generator command
+func CreateCommand() *cobra.Command {
+	return base.CreateCommand("docs", "Create data dictionary and ER diagrams of project in markdown, html, mermaid and dot", New())
+}
+
+// Generator represents docs generator
+type Generator struct {
+	options Options
+	files   *mfd.Files
+}
+
+// New creates docs generator
+func New() *Generator {
+	return &Generator{files: mfd.NewFiles(nil)}
+}
+
+// Runner runs docs generator without cobra command
+type Runner = mfd.Runner[Options]
+
+// NewGenerator creates docs generator for usage as a library, default options are filled as in command
+func NewGenerator(options Options) *Runner {
+	return mfd.NewRunner(options, (*Options).Def, run)
+}
+
+// run runs generator with files if required options are set
+func run(ctx context.Context, options Options, files *mfd.Files) error {
+	if options.MFDPath == "" || options.Output == "" {
+		return errors.New("mfd file and output dir are required")
+	}
+
+	g := &Generator{options: options, files: files}
+	return g.generate(ctx)
+}
+
+// AddFlags adds flags to command
+func (g *Generator) AddFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringP(base.Output, "o", "", "output dir path")
+	if err := command.MarkFlagRequired(base.Output); err != nil {
+		panic(err)
+	}
+
+	flags.StringP(mfdFlag, "m", "", "mfd file path")
+	if err := command.MarkFlagRequired(mfdFlag); err != nil {
+		panic(err)
+	}
+
+	flags.StringSliceP(nsFlag, "n", []string{}, "namespaces to generate. separate by comma")
+	flags.StringSliceP(formatsFlag, "f", Formats, "formats of generated files. separate by comma\n")
+}
+
+// ReadFlags read flags from command
+func (g *Generator) ReadFlags(command *cobra.Command) error {
+	var err error
+
+	flags := command.Flags()
+
+	if g.options.Output, err = flags.GetString(base.Output); err != nil {
+		return err
+	}
+
+	if g.options.MFDPath, err = flags.GetString(mfdFlag); err != nil {
+		return err
+	}
+
+	if g.options.Namespaces, err = flags.GetStringSlice(nsFlag); err != nil {
+		return err
+	}
+
+	if g.options.Formats, err = flags.GetStringSlice(formatsFlag); err != nil {
+		return err
+	}
+
+	g.options.Def()
+
+	return nil
+}
+
+// Generate runs generator
+func (g *Generator) Generate() error {
+	return g.generate(context.Background())
+}
+
+func (g *Generator) generate(ctx context.Context) error {
+	for _, format := range g.options.Formats {
+		if !slices.Contains(Formats, format) {
+			return fmt.Errorf("unknown format %s, use one of: %s", format, strings.Join(Formats, ", "))
+		}
+	}
+
+	// loading project from file, only generated namespaces and their dependencies are read
+	project, err := mfd.LoadProjectNamespaces(g.options.MFDPath, 0, g.options.Namespaces)
+	if err != nil {
+		return err
+	}
+
+	if len(g.options.Namespaces) == 0 {
+		g.options.Namespaces = project.NamespaceNames
+	}
+
+	var namespaces []*mfd.Namespace
+	for _, name := range g.options.Namespaces {
+		ns := project.Namespace(name)
+		if ns == nil {
+			return fmt.Errorf("namespace %s not found", name)
+		}
+		namespaces = append(namespaces, ns)
+	}
+
+	data := PackProject(project, namespaces)
+	if err := g.save(IndexName, NewDiagram(project.Name, namespaces...), data, markdownIndexTemplate, htmlIndexTemplate); err != nil {
+		return fmt.Errorf("generate project docs, err=%w", err)
+	}
+
+	for i, ns := range namespaces {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		nsData := data.Namespaces[i]
+		if err := g.save(nsData.File, NewDiagram(ns.Name, ns), nsData, markdownNamespaceTemplate, htmlNamespaceTemplate); err != nil {
+			return fmt.Errorf("generate docs %s, err=%w", ns.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// save writes page and diagrams of project or namespace in selected formats
+func (g *Generator) save(name string, diagram Diagram, data any, markdownTemplate, htmlTemplate string) error {
+	for _, format := range g.options.Formats {
+		output := filepath.Join(g.options.Output, name+extensions[format])
+
+		var err error
+		switch format {
+		case FormatMarkdown:
+			var buf bytes.Buffer
+			if err = mfd.RenderText(&buf, markdownTemplate, data); err == nil {
+				_, err = g.files.Save(buf.Bytes(), output)
+			}
+		case FormatHTML:
+			_, err = g.files.FormatAndSave(data, output, htmlTemplate, false, htmlPartialsTemplate)
+		default:
+			var content string
+			if content, err = diagram.Render(format); err == nil {
+				_, err = g.files.Save([]byte(content), output)
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("save %s, err=%w", output, err)
+		}
+	}
+
+	return nil
+}
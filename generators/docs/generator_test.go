@@ -0,0 +1,120 @@
+package docs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/generators/testdata"
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	Convey("TestGenerator_Generate", t, func() {
+		Convey("Check correct generate", func() {
+			generator := New()
+
+			generator.options.Def()
+			generator.options.Output = testdata.PathActualDocs
+			generator.options.MFDPath = testdata.PathExpectedMFD
+			generator.options.Namespaces = []string{"portal", "geo"}
+
+			t.Log("Generate docs")
+			So(generator.Generate(), ShouldBeNil)
+		})
+
+		Convey("Check generated files", func() {
+			var expectedFilenames []string
+			for _, name := range []string{IndexName, "portal", "geo"} {
+				for _, ext := range []string{".md", ".html", ".mmd", ".dot"} {
+					expectedFilenames = append(expectedFilenames, name+ext)
+				}
+			}
+
+			for _, f := range expectedFilenames {
+				t.Logf("Check %s file", f)
+				content, err := os.ReadFile(filepath.Join(testdata.PathActualDocs, f))
+				if err != nil {
+					t.Fatal(err)
+				}
+				expectedContent, err := os.ReadFile(filepath.Join(testdata.PathExpectedDocs, f))
+				if err != nil {
+					t.Fatal(err)
+				}
+				So(string(content), ShouldResemble, string(expectedContent))
+			}
+		})
+	})
+}
+
+func TestRunner_Formats(t *testing.T) {
+	Convey("TestRunner_Formats", t, func() {
+		out := mfd.NewMemoryOutput()
+		files, err := NewGenerator(Options{
+			Output:     "docs",
+			MFDPath:    testdata.PathExpectedMFD,
+			Namespaces: []string{"geo"},
+			Formats:    []string{FormatMermaid},
+		}).WithOutput(out).Generate(context.Background())
+		So(err, ShouldBeNil)
+		So(files, ShouldResemble, []string{filepath.Join("docs", "index.mmd"), filepath.Join("docs", "geo.mmd")})
+
+		content, err := out.ReadFile(filepath.Join("docs", "geo.mmd"))
+		So(err, ShouldBeNil)
+		So(string(content), ShouldContainSubstring, `City }o--|| Region : "RegionID"`)
+
+		_, err = NewGenerator(Options{Output: "docs", MFDPath: testdata.PathExpectedMFD, Formats: []string{"pdf"}}).WithOutput(out).Generate(context.Background())
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestDiagram_Options(t *testing.T) {
+	Convey("TestDiagram_Options", t, func() {
+		project, err := mfd.LoadProject(testdata.PathExpectedMFD, false, 0)
+		So(err, ShouldBeNil)
+
+		diagram := NewDiagram(project.Name, project.Namespace("portal"), project.Namespace("geo"))
+
+		Convey("Check focus", func() {
+			focused, err := diagram.Focus("Category", 1)
+			So(err, ShouldBeNil)
+			So(entityNames(focused), ShouldResemble, []string{"Category", "News"})
+
+			content := focused.Mermaid()
+			So(content, ShouldContainSubstring, `News }o--|| Category : "CategoryID"`)
+			So(content, ShouldNotContainSubstring, `News }o--o| City : "CityID"`)
+
+			focused, err = diagram.Focus("Category", 2)
+			So(err, ShouldBeNil)
+			So(entityNames(focused), ShouldResemble, []string{"Category", "News", "Tag", "City", "Country", "Region"})
+
+			_, err = diagram.Focus("VfsFile", 1)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Check fk only", func() {
+			diagram.FKOnly = true
+			content := diagram.Mermaid()
+			So(content, ShouldContainSubstring, "int4 CategoryID FK")
+			So(content, ShouldNotContainSubstring, " Title")
+		})
+
+		Convey("Check modes", func() {
+			content := diagram.WithModes(project).Mermaid()
+			So(content, ShouldContainSubstring, `News["News (Full)"] {`)
+			So(diagram.WithModes(project).DOT(), ShouldContainSubstring, `<b>News (Full)</b>`)
+		})
+	})
+}
+
+func entityNames(diagram Diagram) []string {
+	names := make([]string, 0, len(diagram.Entities))
+	for _, entity := range diagram.Entities {
+		names = append(names, entity.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,23 @@
+package docs
+
+// Options stores generator options
+type Options struct {
+	// Output dir path
+	Output string
+
+	// MFDPath stores path for mfd project
+	MFDPath string
+
+	// Namespaces to document, all project namespaces if empty
+	Namespaces []string
+
+	// Formats of generated files: md, html, mermaid, dot
+	Formats []string
+}
+
+// Def fills default values of an options
+func (o *Options) Def() {
+	if len(o.Formats) == 0 {
+		o.Formats = Formats
+	}
+}
@@ -0,0 +1,128 @@
+package docs
+
+const markdownIndexTemplate = `# {{.Name}}
+
+Data dictionary of {{.Name}} project.
+
+## Namespaces
+
+| Namespace | Entities |
+| --- | --- |
+{{range .Namespaces}}{{$ns := .}}| [{{.Name}}]({{.File}}.md) | {{range $i, $e := .Entities}}{{if $i}}, {{end}}[{{.Name}}]({{$ns.File}}.md#{{.Anchor}}){{end}} |
+{{end}}
+## Diagram
+
+` + "```mermaid" + `
+{{.Mermaid}}` + "```" + `
+`
+
+const markdownNamespaceTemplate = `# {{.Name}}
+
+[{{.Project}}](index.md) / {{.Name}}
+
+## Diagram
+
+` + "```mermaid" + `
+{{.Mermaid}}` + "```" + `
+
+## Entities
+{{range .Entities}}
+### {{.Name}}
+
+Table ` + "`{{.Table}}`" + `{{if .Mode}}, vt mode ` + "`{{.Mode}}`" + `{{end}}.
+{{with .Description}}
+{{.}}
+{{end}}
+| Attribute | Column | DB type | Go type | Nullable | Keys | Description |
+| --- | --- | --- | --- | --- | --- | --- |
+{{range .Attributes}}| {{.Name}} | {{.DBName}} | ` + "`{{.DBType}}`" + ` | ` + "`{{.GoType}}`" + ` | {{.Nullable}} | {{.Keys}}{{if .ForeignKey}} → {{if .ForeignFile}}[{{.ForeignKey}}]({{.ForeignFile}}.md#{{.ForeignAnchor}}){{else}}{{.ForeignKey}}{{end}}{{end}} | {{.MarkdownDescription}} |
+{{end}}{{if .Searches}}
+| Search | Attribute | Type |
+| --- | --- | --- |
+{{range .Searches}}| {{.Name}} | {{.AttrName}} | {{.SearchType}} |
+{{end}}{{end}}{{end}}`
+
+const htmlIndexTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <title>{{.Name}}</title>
+    {{template "docs.style"}}
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<p>Data dictionary of {{.Name}} project.</p>
+<h2>Namespaces</h2>
+<table>
+    <thead><tr><th>Namespace</th><th>Entities</th></tr></thead>
+    <tbody>
+    {{- range .Namespaces}}{{$ns := .}}
+    <tr><td><a href="{{.File}}.html">{{.Name}}</a></td><td>{{range $i, $e := .Entities}}{{if $i}}, {{end}}<a href="{{$ns.File}}.html#{{.Anchor}}">{{.Name}}</a>{{end}}</td></tr>
+    {{- end}}
+    </tbody>
+</table>
+<h2>Diagram</h2>
+<pre class="mermaid">
+{{.Mermaid}}</pre>
+{{template "docs.script"}}
+</body>
+</html>
+`
+
+const htmlNamespaceTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <title>{{.Name}} - {{.Project}}</title>
+    {{template "docs.style"}}
+</head>
+<body>
+<nav><a href="index.html">{{.Project}}</a> / {{.Name}}</nav>
+<h1>{{.Name}}</h1>
+<h2>Diagram</h2>
+<pre class="mermaid">
+{{.Mermaid}}</pre>
+<h2>Entities</h2>
+{{- range .Entities}}
+<section id="{{.Anchor}}">
+    <h3>{{.Name}}</h3>
+    <p>Table <code>{{.Table}}</code>{{if .Mode}}, vt mode <code>{{.Mode}}</code>{{end}}.</p>
+    {{- with .Description}}
+    <p>{{.}}</p>
+    {{- end}}
+    <table>
+        <thead><tr><th>Attribute</th><th>Column</th><th>DB type</th><th>Go type</th><th>Nullable</th><th>Keys</th><th>Description</th></tr></thead>
+        <tbody>
+        {{- range .Attributes}}
+        <tr><td>{{.Name}}</td><td>{{.DBName}}</td><td><code>{{.DBType}}</code></td><td><code>{{.GoType}}</code></td><td>{{.Nullable}}</td><td>{{.Keys}}{{if .ForeignKey}} → {{if .ForeignFile}}<a href="{{.ForeignFile}}.html#{{.ForeignAnchor}}">{{.ForeignKey}}</a>{{else}}{{.ForeignKey}}{{end}}{{end}}</td><td>{{.Description}}</td></tr>
+        {{- end}}
+        </tbody>
+    </table>
+    {{- if .Searches}}
+    <table>
+        <thead><tr><th>Search</th><th>Attribute</th><th>Type</th></tr></thead>
+        <tbody>
+        {{- range .Searches}}
+        <tr><td>{{.Name}}</td><td>{{.AttrName}}</td><td>{{.SearchType}}</td></tr>
+        {{- end}}
+        </tbody>
+    </table>
+    {{- end}}
+</section>
+{{- end}}
+{{template "docs.script"}}
+</body>
+</html>
+`
+
+const htmlPartialsTemplate = `{{define "docs.style"}}<style>
+        body { font-family: sans-serif; margin: 2em; color: #222; }
+        table { border-collapse: collapse; margin: 1em 0; }
+        th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+        th { background: #f0f0f0; }
+        section { margin-top: 2em; }
+    </style>{{end}}
+{{define "docs.script"}}<script type="module">
+    import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@11/dist/mermaid.esm.min.mjs";
+    mermaid.initialize({ startOnLoad: true });
+</script>{{end}}`
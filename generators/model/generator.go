@@ -1,9 +1,10 @@
 package model
 
 import (
+	"context"
 	"embed"
+	"errors"
 	"fmt"
-	"os"
 	"path"
 
 	"github.com/vmkteam/mfd-generator/mfd"
@@ -16,27 +17,67 @@ import (
 var content embed.FS
 
 const (
-	mfdFlag = "mfd"
-	pkgFlag = "package"
+	mfdFlag  = "mfd"
+	pkgFlag  = "package"
+	jobsFlag = "jobs"
 
 	modelTemplateFlag    = "model-tmpl"
 	validateTemplateFlag = "validate-tmpl"
 	searchTemplateFlag   = "search-tmpl"
+	databaseTemplateFlag = "database-tmpl"
+	templatesDirFlag     = "templates-dir"
 )
 
 // CreateCommand creates generator command
 func CreateCommand() *cobra.Command {
-	return base.CreateCommand("model", "Create golang model from xml", New())
+	command := base.CreateCommand("model", "Create golang model from xml", New())
+	command.Long = command.Short + "\n\n" + mfd.KeepHelp
+
+	return command
+}
+
+// Templates returns built-in templates which could be exported to templates dir and customized
+func Templates() []mfd.BuiltinTemplate {
+	return []mfd.BuiltinTemplate{
+		{Name: "model", Source: modelDefaultTemplate},
+		{Name: "search", Source: searchDefaultTemplate},
+		{Name: "validate", Source: validateDefaultTemplate},
+		{Name: "database", Source: databaseDefaultTemplate},
+	}
 }
 
 // Generator represents mfd generator
 type Generator struct {
 	options Options
+	files   *mfd.Files
 }
 
 // New creates basic generator
 func New() *Generator {
-	return &Generator{}
+	return &Generator{files: mfd.NewFiles(nil)}
+}
+
+// Runner runs model generator without cobra command
+type Runner = mfd.Runner[Options]
+
+// NewGenerator creates model generator for usage as a library, default options are filled as in command
+func NewGenerator(options Options) *Runner {
+	return mfd.NewRunner(options, func(options *Options) {
+		if options.Package == "" {
+			options.Package = path.Base(options.Output)
+		}
+		options.Def()
+	}, run)
+}
+
+// run runs generator with files if required options are set
+func run(ctx context.Context, options Options, files *mfd.Files) error {
+	if options.MFDPath == "" || options.Output == "" {
+		return errors.New("mfd file and output dir are required")
+	}
+
+	g := &Generator{options: options, files: files}
+	return g.generate(ctx)
 }
 
 // AddFlags adds flags to command
@@ -54,11 +95,14 @@ func (g *Generator) AddFlags(command *cobra.Command) {
 		panic(err)
 	}
 
-	flags.StringP(pkgFlag, "p", "", "package name that will be used in golang files. if not set - last element of output path will be used\n")
+	flags.StringP(pkgFlag, "p", "", "package name that will be used in golang files. if not set - last element of output path will be used")
+	flags.IntP(jobsFlag, "j", 0, "count of files generated concurrently. if not set - count of CPUs will be used\n")
 
 	flags.String(modelTemplateFlag, "", "path to model custom template")
 	flags.String(searchTemplateFlag, "", "path to search custom template")
-	flags.String(validateTemplateFlag, "", "path to validate custom template\n")
+	flags.String(validateTemplateFlag, "", "path to validate custom template")
+	flags.String(databaseTemplateFlag, "", "path to named database custom template")
+	flags.String(templatesDirFlag, "", "path to custom templates dir, see templates export command\n")
 }
 
 // ReadFlags read flags from command
@@ -83,6 +127,10 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 		g.options.Package = path.Base(g.options.Output)
 	}
 
+	if g.options.Jobs, err = flags.GetInt(jobsFlag); err != nil {
+		return err
+	}
+
 	if g.options.ModelTemplatePath, err = flags.GetString(modelTemplateFlag); err != nil {
 		return err
 	}
@@ -92,6 +140,12 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 	if g.options.ValidateTemplatePath, err = flags.GetString(validateTemplateFlag); err != nil {
 		return err
 	}
+	if g.options.DatabaseTemplatePath, err = flags.GetString(databaseTemplateFlag); err != nil {
+		return err
+	}
+	if g.options.TemplatesDir, err = flags.GetString(templatesDirFlag); err != nil {
+		return err
+	}
 
 	g.options.Def()
 
@@ -100,6 +154,10 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 
 // Generate runs generator
 func (g *Generator) Generate() error {
+	return g.generate(context.Background())
+}
+
+func (g *Generator) generate(ctx context.Context) error {
 	// loading project from file
 	project, err := mfd.LoadProject(g.options.MFDPath, false, 0)
 	if err != nil {
@@ -108,42 +166,95 @@ func (g *Generator) Generate() error {
 
 	g.options.GoPGVer = project.GoPGVer
 	g.options.CustomTypes = project.CustomTypes
+	g.options.SearchPath = map[string][]string{}
+	for _, namespace := range project.Namespaces {
+		g.options.SearchPath[namespace.Name] = project.SchemaSearchPath(namespace.Name)
+	}
 
 	// validate names
 	if err := project.ValidateNames(); err != nil {
 		return err
 	}
 
-	// basic generator
-	output := path.Join(g.options.Output, "model.go")
-	modelData := PackNamespace(project.Namespaces, g.options)
-	modelTemplate, err := mfd.LoadTemplate(g.options.ModelTemplatePath, modelDefaultTemplate)
-	if err != nil {
-		return fmt.Errorf("load model template, err=%w", err)
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	if _, err := mfd.FormatAndSave(modelData, output, modelTemplate, true); err != nil {
-		return fmt.Errorf("generate project model, err=%w", err)
+	// loading custom templates
+	templates, err := mfd.LoadTemplates(g.options.TemplatesDir, "model")
+	if err != nil {
+		return fmt.Errorf("load templates, err=%w", err)
 	}
 
-	// generating search
-	output = path.Join(g.options.Output, "model_search.go")
-	searchData := PackSearchNamespace(project.Namespaces, g.options)
-	searchTemplate, err := mfd.LoadTemplate(g.options.SearchTemplatePath, searchDefaultTemplate)
+	modelTemplate, err := templates.Template("model", g.options.ModelTemplatePath, modelDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load model template, err=%w", err)
 	}
 
-	if _, err := mfd.FormatAndSave(searchData, output, searchTemplate, true); err != nil {
-		return fmt.Errorf("generate project search, err=%w", err)
+	searchTemplate, err := templates.Template("search", g.options.SearchTemplatePath, searchDefaultTemplate)
+	if err != nil {
+		return fmt.Errorf("load search template, err=%w", err)
 	}
 
-	// generating validate
-	output = path.Join(g.options.Output, "model_validate.go")
-	validateDate := PackValidateNamespace(project.Namespaces, g.options)
-	validateTemplate, err := mfd.LoadTemplate(g.options.ValidateTemplatePath, validateDefaultTemplate)
+	validateTemplate, err := templates.Template("validate", g.options.ValidateTemplatePath, validateDefaultTemplate)
 	if err != nil {
-		return fmt.Errorf("load model template, err=%w", err)
+		return fmt.Errorf("load validate template, err=%w", err)
+	}
+
+	databaseTemplate, err := templates.Template("database", g.options.DatabaseTemplatePath, databaseDefaultTemplate)
+	if err != nil {
+		return fmt.Errorf("load database template, err=%w", err)
+	}
+
+	// project files are rendered and formatted concurrently
+	tasks := []mfd.Task{
+		// basic generator
+		func(files *mfd.Files) error {
+			output := path.Join(g.options.Output, "model.go")
+			if _, err := files.FormatAndSave(PackNamespace(project.Namespaces, g.options), output, modelTemplate, true, templates.Partials()...); err != nil {
+				return fmt.Errorf("generate project model, err=%w", err)
+			}
+			return nil
+		},
+		// generating search
+		func(files *mfd.Files) error {
+			output := path.Join(g.options.Output, "model_search.go")
+			if _, err := files.FormatAndSave(PackSearchNamespace(project.Namespaces, g.options), output, searchTemplate, true, templates.Partials()...); err != nil {
+				return fmt.Errorf("generate project search, err=%w", err)
+			}
+			return nil
+		},
+		// generating validate
+		func(files *mfd.Files) error {
+			output := path.Join(g.options.Output, "model_validate.go")
+			if _, err := files.FormatAndSave(PackValidateNamespace(project.Namespaces, g.options), output, validateTemplate, true, templates.Partials()...); err != nil {
+				return fmt.Errorf("generate project validate, err=%w", err)
+			}
+			return nil
+		},
+		// generating params
+		func(files *mfd.Files) error {
+			output := path.Join(g.options.Output, "model_params.go")
+			if _, err := GenerateParams(files, project.Namespaces, output, g.options); err != nil {
+				return fmt.Errorf("generate project params, err=%w", err)
+			}
+			return nil
+		},
+	}
+
+	// generating db wrappers for named databases
+	for _, database := range project.NamedDatabases() {
+		tasks = append(tasks, func(files *mfd.Files) error {
+			output := path.Join(g.options.Output, fmt.Sprintf("db_%s.go", mfd.GoFileName(database.Name)))
+			if _, err := files.FormatAndSave(PackDatabase(database, g.options), output, databaseTemplate, true, templates.Partials()...); err != nil {
+				return fmt.Errorf("generate database %s, err=%w", database.Name, err)
+			}
+			return nil
+		})
+	}
+
+	if err := g.files.Parallel(ctx, g.options.Jobs, tasks); err != nil {
+		return err
 	}
 
 	// generating base db files
@@ -151,7 +262,7 @@ func (g *Generator) Generate() error {
 		p := path.Join(g.options.Output, file)
 
 		// check file existence
-		if _, err := os.Stat(p); !os.IsNotExist(err) {
+		if g.files.Exists(p) {
 			continue
 		}
 
@@ -161,20 +272,52 @@ func (g *Generator) Generate() error {
 			return fmt.Errorf("read model template, err=%w", err)
 		}
 
-		if _, err = mfd.Save(b, p); err != nil {
+		if _, err = g.files.Save(b, p); err != nil {
 			return fmt.Errorf("save model template, err=%w", err)
 		}
 	}
 
-	if _, err := mfd.FormatAndSave(validateDate, output, validateTemplate, true); err != nil {
-		return fmt.Errorf("generate project validate, err=%w", err)
+	// generating audit log model and repo for vt services
+	if project.HasAudit() {
+		p := path.Join(g.options.Output, "audit.go")
+		if !g.files.Exists(p) {
+			b, err := content.ReadFile("templates/audit.go.tmpl")
+			if err != nil {
+				return fmt.Errorf("read audit template, err=%w", err)
+			}
+
+			if _, err = g.files.Save(b, p); err != nil {
+				return fmt.Errorf("save audit template, err=%w", err)
+			}
+		}
 	}
 
-	// generating params
-	output = path.Join(g.options.Output, "model_params.go")
-	if _, err := GenerateParams(project.Namespaces, output, g.options); err != nil {
-		return fmt.Errorf("generate project params, err=%w", err)
+	// generating additional files from project output rules
+	if err := g.saveOutputs(project); err != nil {
+		return fmt.Errorf("generate outputs, err=%w", err)
 	}
 
 	return nil
 }
+
+// saveOutputs renders model output rules of project for each entity
+func (g *Generator) saveOutputs(project *mfd.Project) error {
+	rules := project.OutputRules("model")
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var entities []mfd.OutputEntity
+	for _, namespace := range project.Namespaces {
+		for _, entity := range namespace.Entities {
+			entities = append(entities, mfd.OutputEntity{
+				Namespace: namespace.Name,
+				Name:      entity.Name,
+				Package:   g.options.Package,
+				Entity:    PackEntity(*entity, g.options),
+			})
+		}
+	}
+
+	return g.files.SaveOutputs(rules, g.options.MFDPath, g.options.Output, entities)
+}
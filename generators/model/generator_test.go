@@ -2,11 +2,15 @@ package model
 
 import (
 	"bytes"
+	"context"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"testing"
 
 	"github.com/vmkteam/mfd-generator/generators/testdata"
+	"github.com/vmkteam/mfd-generator/mfd"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -27,6 +31,7 @@ func TestGenerator_Generate(t *testing.T) {
 
 		Convey("Check generated files", func() {
 			expectedFilenames := map[string]struct{}{
+				"audit.go":          {},
 				"model.go":          {},
 				"model_params.go":   {},
 				"model_search.go":   {},
@@ -53,3 +58,92 @@ func TestGenerator_Generate(t *testing.T) {
 		})
 	})
 }
+
+func TestRunner_Generate(t *testing.T) {
+	Convey("TestRunner_Generate", t, func() {
+		Convey("Check required options", func() {
+			_, err := NewGenerator(Options{Output: testdata.PackageDB}).Generate(context.Background())
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Check generate to memory", func() {
+			out := mfd.NewMemoryOutput()
+			files, err := NewGenerator(Options{
+				Output:  testdata.PackageDB,
+				MFDPath: testdata.PathExpectedMFD,
+			}).WithOutput(out).Generate(context.Background())
+			So(err, ShouldBeNil)
+			So(files, ShouldHaveLength, len(out.Files()))
+			So(files, ShouldContain, filepath.Join(testdata.PackageDB, "db.go"))
+
+			for _, f := range []string{"model.go", "model_params.go", "model_search.go", "model_validate.go"} {
+				content, err := fs.ReadFile(out, path.Join(testdata.PackageDB, f))
+				So(err, ShouldBeNil)
+				expectedContent, err := os.ReadFile(filepath.Join(testdata.PathExpectedDB, f))
+				So(err, ShouldBeNil)
+
+				// cut first line with version from comparsion
+				_, content, _ = bytes.Cut(content, []byte{'\n'})
+				_, expectedContent, _ = bytes.Cut(expectedContent, []byte{'\n'})
+
+				So(string(content), ShouldResemble, string(expectedContent))
+			}
+
+			_, err = os.Stat(testdata.PackageDB)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("Check canceled context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			files, err := NewGenerator(Options{
+				Output:  testdata.PackageDB,
+				MFDPath: testdata.PathExpectedMFD,
+			}).WithOutput(mfd.NewMemoryOutput()).Generate(ctx)
+			So(err, ShouldEqual, context.Canceled)
+			So(files, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestPackEntity_SearchPath(t *testing.T) {
+	entity := mfd.Entity{Name: "Invoice", Namespace: "billing", Table: "billing.invoices"}
+
+	tests := []struct {
+		name       string
+		goPGVer    int
+		searchPath map[string][]string
+		want       string
+	}{
+		{name: "without search path", goPGVer: mfd.GoPG10, want: "`pg:\"billing.invoices,alias:t,discard_unknown_columns\"`"},
+		{name: "schema not in search path", goPGVer: mfd.GoPG10, searchPath: map[string][]string{"billing": {"public"}}, want: "`pg:\"billing.invoices,alias:t,discard_unknown_columns\"`"},
+		{name: "schema in search path", goPGVer: mfd.GoPG10, searchPath: map[string][]string{"billing": {"billing", "public"}}, want: "`pg:\"invoices,alias:t,discard_unknown_columns\"`"},
+		{name: "go-pg 9", goPGVer: mfd.GoPG9, searchPath: map[string][]string{"billing": {"public"}}, want: "`pg:\"billing.invoices,alias:t,discard_unknown_columns\"`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PackEntity(entity, Options{GoPGVer: tt.goPGVer, SearchPath: tt.searchPath})
+			if string(got.Tag) != tt.want {
+				t.Errorf("Tag = %v, want %v", got.Tag, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackNamespace_Imports(t *testing.T) {
+	imp := &mfd.ProjectImport{Path: "../common/common.mfd", Namespaces: "geo", Package: "example.com/common/pkg/db"}
+	city := &mfd.Entity{Name: "City", Namespace: "geo", Table: "cities", Import: imp}
+	place := &mfd.Entity{Name: "Place", Namespace: "portal", Table: "places", Attributes: mfd.Attributes{
+		{Name: "CityID", DBName: "cityId", GoType: "int", ForeignKey: "City", ForeignEntity: city, Null: mfd.NullableNo},
+	}}
+
+	got := PackNamespace([]*mfd.Namespace{{Name: "portal", Entities: []*mfd.Entity{place}}}, Options{GoPGVer: mfd.GoPG10})
+	if rel := got.Entities[0].Relations[0]; rel.Type != "common.City" {
+		t.Errorf("relation type = %v, want common.City", rel.Type)
+	}
+	want := ImportData{Alias: "common", Path: "example.com/common/pkg/db"}
+	if !got.HasImports || len(got.PackageImports) != 1 || got.PackageImports[0] != want {
+		t.Errorf("PackageImports = %v, want %v", got.PackageImports, want)
+	}
+}
@@ -17,8 +17,9 @@ type NamespaceData struct {
 	GeneratorVersion string
 	Package          string
 
-	HasImports bool
-	Imports    []string
+	HasImports     bool
+	Imports        []string
+	PackageImports []ImportData
 
 	GoPGVer string
 
@@ -28,6 +29,8 @@ type NamespaceData struct {
 // PackNamespace creates a package for template
 func PackNamespace(namespaces []*mfd.Namespace, options Options) NamespaceData {
 	imports := mfd.NewSet()
+	packageImports := map[string]ImportData{}
+	var packages []ImportData
 
 	var models []EntityData
 	for _, namespace := range namespaces {
@@ -39,6 +42,12 @@ func PackNamespace(namespaces []*mfd.Namespace, options Options) NamespaceData {
 			for _, imp := range mdl.Imports {
 				imports.Add(imp)
 			}
+			for _, imp := range mdl.PackageImports {
+				if _, ok := packageImports[imp.Path]; !ok {
+					packageImports[imp.Path] = imp
+					packages = append(packages, imp)
+				}
+			}
 		}
 	}
 
@@ -51,8 +60,9 @@ func PackNamespace(namespaces []*mfd.Namespace, options Options) NamespaceData {
 		GeneratorVersion: mfd.Version,
 		Package:          options.Package,
 
-		HasImports: imports.Len() > 0,
-		Imports:    imports.Elements(),
+		HasImports:     imports.Len() > 0 || len(packages) > 0,
+		Imports:        imports.Elements(),
+		PackageImports: packages,
 
 		GoPGVer: goPGVer,
 
@@ -60,6 +70,12 @@ func PackNamespace(namespaces []*mfd.Namespace, options Options) NamespaceData {
 	}
 }
 
+// ImportData stores aliased import of go package, e.g. model package of imported project
+type ImportData struct {
+	Alias string
+	Path  string
+}
+
 // EntityData stores struct info
 type EntityData struct {
 	mfd.Entity
@@ -71,7 +87,8 @@ type EntityData struct {
 	NoAlias bool
 	Alias   string
 
-	Imports []string
+	Imports        []string
+	PackageImports []ImportData
 
 	Columns []AttributeData
 
@@ -84,6 +101,7 @@ func PackEntity(entity mfd.Entity, options Options) EntityData {
 	imports := mfd.NewSet()
 	columns := make([]AttributeData, 0, len(entity.Attributes))
 	relations := make([]RelationData, 0, len(entity.Attributes))
+	var packageImports []ImportData
 
 	// adding columns
 	for _, attribute := range entity.Attributes {
@@ -98,16 +116,27 @@ func PackEntity(entity mfd.Entity, options Options) EntityData {
 		// adding relation from column
 		if attribute.ForeignKey != "" && !attribute.IsArray {
 			relations = append(relations, PackRelation(*attribute, options))
+
+			// imported entities are referenced from model package of imported project
+			if fk := attribute.ForeignEntity; fk.IsImported() {
+				packageImports = append(packageImports, ImportData{Alias: fk.Import.GoAlias(), Path: fk.Import.Package})
+			}
 		}
 	}
 
+	// table is used without schema if schema is in search_path of database
+	table := entity.Table
+	if searchPath, ok := options.SearchPath[entity.Namespace]; ok {
+		table = entity.TableName(searchPath)
+	}
+
 	// adding annotations for go-pg to column
 	tagName := tagName(options)
 	tags := util.NewAnnotation()
 	if options.GoPGVer < mfd.GoPG10 {
-		tags.AddTag(tagName, util.Quoted(entity.Table, true))
+		tags.AddTag(tagName, util.Quoted(table, true))
 	} else {
-		tags.AddTag(tagName, entity.Table)
+		tags.AddTag(tagName, table)
 	}
 	tags.AddTag(tagName, fmt.Sprintf("alias:%s", util.DefaultAlias))
 	if options.GoPGVer == mfd.GoPG8 {
@@ -125,7 +154,8 @@ func PackEntity(entity mfd.Entity, options Options) EntityData {
 		Tag:   template.HTML(fmt.Sprintf("`%s`", tags.String())),
 		Alias: util.DefaultAlias,
 
-		Imports: imports.Elements(),
+		Imports:        imports.Elements(),
+		PackageImports: packageImports,
 
 		Columns: columns,
 
@@ -235,12 +265,18 @@ func PackRelation(relation mfd.Attribute, options Options) RelationData {
 		comment = "// unsupported"
 	}
 
+	// imported entity is declared in model package of imported project
+	typ := relation.ForeignKey
+	if relation.ForeignEntity.IsImported() {
+		typ = relation.ForeignEntity.Import.GoType(relation.ForeignEntity.Name)
+	}
+
 	return RelationData{
 		Attribute: relation,
 
 		// ObjectID -> Object, UserID -> User
 		Name:     util.ReplaceSuffix(util.ColumnName(relation.DBName), util.ID, ""),
-		Type:     relation.ForeignKey,
+		Type:     typ,
 		Entity:   relation.ForeignEntity,
 		Nullable: relation.Nullable(),
 
@@ -281,3 +317,26 @@ func fixPointer(attribute mfd.Attribute) string {
 
 	return attribute.GoType
 }
+
+// DatabaseData stores named database info for db wrapper template
+type DatabaseData struct {
+	mfd.Database
+
+	Package string
+	GoPGVer string
+}
+
+// PackDatabase packs named database to template data
+func PackDatabase(database mfd.Database, options Options) DatabaseData {
+	goPGVer := ""
+	if options.GoPGVer != mfd.GoPG8 {
+		goPGVer = fmt.Sprintf("/v%d", options.GoPGVer)
+	}
+
+	return DatabaseData{
+		Database: database,
+
+		Package: options.Package,
+		GoPGVer: goPGVer,
+	}
+}
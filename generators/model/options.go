@@ -22,13 +22,23 @@ type Options struct {
 	// go-pg version
 	GoPGVer int
 
+	// Jobs is a count of files generated concurrently, count of CPUs is used if not set
+	Jobs int
+
 	// custom templates
 	ModelTemplatePath    string
 	SearchTemplatePath   string
 	ValidateTemplatePath string
+	DatabaseTemplatePath string
+
+	// TemplatesDir stores custom templates and partials in model subdir
+	TemplatesDir string
 
 	// custom types
 	CustomTypes mfd.CustomTypes
+
+	// SearchPath stores search_path of namespace database by namespace, tables from these schemas are used without schema in go-pg tags
+	SearchPath map[string][]string
 }
 
 // Def fills default values of an options
@@ -7,16 +7,13 @@ import (
 	"go/parser"
 	"go/printer"
 	"go/token"
-	"os"
 
 	"github.com/vmkteam/mfd-generator/mfd"
-
-	"github.com/dizzyfool/genna/util"
 )
 
 // GenerateParams packs json fields to params file
-func GenerateParams(namespaces []*mfd.Namespace, output string, options Options) (bool, error) {
-	paramsFile, err := ReadParamsFile(output, options.Package)
+func GenerateParams(files *mfd.Files, namespaces []*mfd.Namespace, output string, options Options) (bool, error) {
+	paramsFile, err := ReadParamsFile(files, output, options.Package)
 	if err != nil {
 		return false, err
 	}
@@ -35,28 +32,28 @@ func GenerateParams(namespaces []*mfd.Namespace, output string, options Options)
 }
 
 type ParamsFile struct {
-	set  *token.FileSet
-	file *ast.File
+	set   *token.FileSet
+	file  *ast.File
+	files *mfd.Files
 }
 
 // ReadParamsFile reads exiting params file
-func ReadParamsFile(filename, pack string) (*ParamsFile, error) {
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		src := fmt.Sprintf("package %s", pack)
-		if err := os.WriteFile(filename, []byte(src), 0644); err != nil {
-			return nil, fmt.Errorf("write file, err=%w", err)
+func ReadParamsFile(files *mfd.Files, filename, pack string) (*ParamsFile, error) {
+	src := []byte(fmt.Sprintf("package %s", pack))
+	if files.Exists(filename) {
+		var err error
+		if src, err = files.ReadFile(filename); err != nil {
+			return nil, fmt.Errorf("open file, err=%w", err)
 		}
-	} else if err != nil {
-		return nil, fmt.Errorf("open file, err=%w", err)
 	}
 
 	set := token.NewFileSet()
-	file, err := parser.ParseFile(set, filename, nil, parser.ParseComments)
+	file, err := parser.ParseFile(set, filename, src, parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("open file, err=%w", err)
 	}
 
-	return &ParamsFile{set: set, file: file}, nil
+	return &ParamsFile{set: set, file: file, files: files}, nil
 }
 
 // Has checks if params file has specific param
@@ -111,5 +108,5 @@ func (p *ParamsFile) Save(filename string) (bool, error) {
 		return false, fmt.Errorf("dump ast to file, err=%w", err)
 	}
 
-	return util.FmtAndSave(buffer.Bytes(), filename)
+	return p.files.FmtAndSave(buffer.Bytes(), filename)
 }
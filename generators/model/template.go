@@ -9,7 +9,8 @@ const modelDefaultTemplate = `// Code generated by mfd-generator {{ .GeneratorVe
 package {{.Package}}{{if .HasImports}}
 
 import ({{range .Imports}}
-    "{{.}}"{{end}}
+    "{{.}}"{{end}}{{range .PackageImports}}
+    {{.Alias}} "{{.Path}}"{{end}}
 ){{end}}
 
 var Columns = struct {
@@ -47,16 +48,18 @@ var Tables = struct {
 		Alias: "{{.Alias}}",
 	},{{end}}
 }
-{{range $model := .Entities}}
-type {{.Name}} struct {
+{{range .Entities}}{{template "model.struct" .}}{{end}}
+{{define "model.struct"}}
+{{with .Description}}{{comment .}}
+{{end}}type {{.Name}} struct {
 	tableName struct{} {{.Tag}}
 	{{range .Columns}}
-	{{.Name}} {{.GoType}} {{.Tag}} {{.Comment}}{{end}}{{if .HasRelations}}
+	{{with .Description}}{{comment .}}
+	{{end}}{{.Name}} {{.GoType}} {{.Tag}} {{.Comment}}{{end}}{{if .HasRelations}}
 	{{range .Relations}}
 	{{.Name}} *{{.Type}} {{.Tag}} {{.Comment}}{{end}}{{end}}
 }
-{{end}}
-`
+{{end}}`
 
 const searchDefaultTemplate = `// Code generated by mfd-generator {{ .GeneratorVersion }}; DO NOT EDIT.
 
@@ -112,7 +115,8 @@ type Searcher interface {
 	WithApply(a applier)
 }
 
-{{range $model := .Entities}}
+{{range .Entities}}{{template "search.struct" .}}{{end}}
+{{define "search.struct"}}{{$model := .}}
 type {{.Name}}Search struct {
 	search 
 
@@ -144,8 +148,7 @@ func ({{$model.ShortVarName}}s *{{.Name}}Search) Q() applier {
 		return {{$model.ShortVarName}}s.Apply(query), nil
 	}
 }
-{{end}}
-`
+{{end}}`
 
 const validateDefaultTemplate = `// Code generated by mfd-generator {{ .GeneratorVersion }}; DO NOT EDIT.
 
@@ -163,7 +166,8 @@ const (
 	ErrWrongValue = "value"
 )
 
-{{range $model := .Entities}}
+{{range .Entities}}{{template "validate.func" .}}{{end}}
+{{define "validate.func"}}{{$model := .}}
 func ({{$model.ShortVarName}} {{.Name}}) Validate() (errors map[string]string, valid bool) {
 	errors = map[string]string{}
 
@@ -193,5 +197,21 @@ func ({{$model.ShortVarName}} {{.Name}}) Validate() (errors map[string]string, v
 
 	return errors, len(errors) == 0
 }
-{{end}}
+{{end}}`
+
+const databaseDefaultTemplate = `package {{.Package}}
+
+import (
+	"github.com/go-pg/pg{{.GoPGVer}}"
+)
+
+// {{.GoName}} stores connection to {{.Name}} database with namespaces: {{.Namespaces}}.
+type {{.GoName}} struct {
+	DB
+}
+
+// New{{.GoName}} is a function that returns {{.GoName}} as wrapper on postgres connection to {{.Name}} database.
+func New{{.GoName}}(db *pg.DB) {{.GoName}} {
+	return {{.GoName}}{DB: New(db)}
+}
 `
@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"path"
 
@@ -11,26 +13,62 @@ import (
 )
 
 const (
-	mfdFlag = "mfd"
-	pkgFlag = "package"
-	nsFlag  = "namespaces"
+	mfdFlag  = "mfd"
+	pkgFlag  = "package"
+	nsFlag   = "namespaces"
+	jobsFlag = "jobs"
 
 	repoTemplateFlag = "repo-tmpl"
+	templatesDirFlag = "templates-dir"
 )
 
 // CreateCommand creates generator command
 func CreateCommand() *cobra.Command {
-	return base.CreateCommand("repo", "Create repo from xml", New())
+	command := base.CreateCommand("repo", "Create repo from xml", New())
+	command.Long = command.Short + "\n\n" + mfd.KeepHelp
+
+	return command
+}
+
+// Templates returns built-in templates which could be exported to templates dir and customized
+func Templates() []mfd.BuiltinTemplate {
+	return []mfd.BuiltinTemplate{
+		{Name: "repo", Source: repoDefaultTemplate},
+	}
 }
 
 // Generator represents repo generator
 type Generator struct {
 	options Options
+	files   *mfd.Files
 }
 
 // New creates repo generator
 func New() *Generator {
-	return &Generator{}
+	return &Generator{files: mfd.NewFiles(nil)}
+}
+
+// Runner runs repo generator without cobra command
+type Runner = mfd.Runner[Options]
+
+// NewGenerator creates repo generator for usage as a library, default options are filled as in command
+func NewGenerator(options Options) *Runner {
+	return mfd.NewRunner(options, func(options *Options) {
+		if options.Package == "" {
+			options.Package = path.Base(options.Output)
+		}
+		options.Def()
+	}, run)
+}
+
+// run runs generator with files if required options are set
+func run(ctx context.Context, options Options, files *mfd.Files) error {
+	if options.MFDPath == "" || options.Output == "" {
+		return errors.New("mfd file and output dir are required")
+	}
+
+	g := &Generator{options: options, files: files}
+	return g.generate(ctx)
 }
 
 // AddFlags adds flags to command
@@ -50,9 +88,11 @@ func (g *Generator) AddFlags(command *cobra.Command) {
 
 	flags.StringP(pkgFlag, "p", "", "package name that will be used in golang files. if not set - last element of output path will be used")
 
-	flags.StringSliceP(nsFlag, "n", []string{}, "namespaces to generate. separate by comma\n")
+	flags.StringSliceP(nsFlag, "n", []string{}, "namespaces to generate. separate by comma")
+	flags.IntP(jobsFlag, "j", 0, "count of namespaces generated concurrently. if not set - count of CPUs will be used\n")
 
-	flags.String(repoTemplateFlag, "", "path to repo custom template\n")
+	flags.String(repoTemplateFlag, "", "path to repo custom template")
+	flags.String(templatesDirFlag, "", "path to custom templates dir, see templates export command\n")
 }
 
 // ReadFlags read flags from command
@@ -81,9 +121,16 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 		g.options.Package = path.Base(g.options.Output)
 	}
 
+	if g.options.Jobs, err = flags.GetInt(jobsFlag); err != nil {
+		return err
+	}
+
 	if g.options.RepoTemplatePath, err = flags.GetString(repoTemplateFlag); err != nil {
 		return err
 	}
+	if g.options.TemplatesDir, err = flags.GetString(templatesDirFlag); err != nil {
+		return err
+	}
 
 	g.options.Def()
 
@@ -92,8 +139,12 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 
 // Generate runs generator
 func (g *Generator) Generate() error {
-	// loading project from file
-	project, err := mfd.LoadProject(g.options.MFDPath, false, 0)
+	return g.generate(context.Background())
+}
+
+func (g *Generator) generate(ctx context.Context) error {
+	// loading project from file, only generated namespaces and their dependencies are read
+	project, err := mfd.LoadProjectNamespaces(g.options.MFDPath, 0, g.options.Namespaces)
 	if err != nil {
 		return err
 	}
@@ -110,22 +161,47 @@ func (g *Generator) Generate() error {
 		g.options.Namespaces = project.NamespaceNames
 	}
 
-	repoTemplate, err := mfd.LoadTemplate(g.options.RepoTemplatePath, repoDefaultTemplate)
+	templates, err := mfd.LoadTemplates(g.options.TemplatesDir, "repo")
+	if err != nil {
+		return fmt.Errorf("load templates, err=%w", err)
+	}
+
+	repoTemplate, err := templates.Template("repo", g.options.RepoTemplatePath, repoDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load repo template, err=%w", err)
 	}
 
+	var (
+		tasks    []mfd.Task
+		entities []mfd.OutputEntity
+	)
 	for _, namespace := range g.options.Namespaces {
 		// generating each namespace in separate file
 		if ns := project.Namespace(namespace); ns != nil {
-			// getting file name without dots
-			output := path.Join(g.options.Output, mfd.GoFileName(namespace)+".go")
 			data := PackNamespace(ns, g.options)
-			if _, err := mfd.FormatAndSave(data, output, repoTemplate, true); err != nil {
-				return fmt.Errorf("generate repo %s, err=%w", namespace, err)
+			tasks = append(tasks, func(files *mfd.Files) error {
+				// getting file name without dots
+				output := path.Join(g.options.Output, mfd.GoFileName(namespace)+".go")
+				if _, err := files.FormatAndSave(data, output, repoTemplate, true, templates.Partials()...); err != nil {
+					return fmt.Errorf("generate repo %s, err=%w", namespace, err)
+				}
+				return nil
+			})
+
+			for _, entity := range data.Entities {
+				entities = append(entities, mfd.OutputEntity{Namespace: ns.Name, Name: entity.Name, Package: g.options.Package, Entity: entity})
 			}
 		}
 	}
 
+	if err := g.files.Parallel(ctx, g.options.Jobs, tasks); err != nil {
+		return err
+	}
+
+	// generating additional files from project output rules
+	if err := g.files.SaveOutputs(project.OutputRules("repo"), g.options.MFDPath, g.options.Output, entities); err != nil {
+		return fmt.Errorf("generate outputs, err=%w", err)
+	}
+
 	return nil
 }
@@ -1,11 +1,13 @@
 package repo
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/vmkteam/mfd-generator/generators/testdata"
+	"github.com/vmkteam/mfd-generator/mfd"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -46,3 +48,59 @@ func TestGenerator_Generate(t *testing.T) {
 		})
 	})
 }
+
+func TestRunner_TemplatesDir(t *testing.T) {
+	Convey("TestRunner_TemplatesDir", t, func() {
+		dir := t.TempDir()
+		partial := `{{define "repo.entity"}}// {{.Entity.Name}} is customized in {{.Namespace.Name}}Repo
+{{end}}`
+		So(os.MkdirAll(filepath.Join(dir, "repo", "partials"), 0o755), ShouldBeNil)
+		So(os.WriteFile(filepath.Join(dir, "repo", "partials", "entity.tmpl"), []byte(partial), 0o644), ShouldBeNil)
+
+		out := mfd.NewMemoryOutput()
+		files, err := NewGenerator(Options{
+			Output:       "db",
+			MFDPath:      testdata.PathExpectedMFD,
+			Namespaces:   []string{"portal"},
+			TemplatesDir: dir,
+		}).WithOutput(out).Generate(context.Background())
+		So(err, ShouldBeNil)
+		So(files, ShouldResemble, []string{filepath.Join("db", "portal.go")})
+
+		content, err := out.ReadFile(filepath.Join("db", "portal.go"))
+		So(err, ShouldBeNil)
+		So(string(content), ShouldContainSubstring, "// News is customized in PortalRepo")
+		So(string(content), ShouldContainSubstring, "func NewPortalRepo(")
+		So(string(content), ShouldNotContainSubstring, "FullNews")
+	})
+}
+
+func TestRunner_Jobs(t *testing.T) {
+	Convey("TestRunner_Jobs", t, func() {
+		generate := func(jobs int) (*mfd.MemoryOutput, []string) {
+			out := mfd.NewMemoryOutput()
+			files, err := NewGenerator(Options{
+				Output:  "db",
+				MFDPath: testdata.PathExpectedMFD,
+				Jobs:    jobs,
+			}).WithOutput(out).Generate(context.Background())
+			So(err, ShouldBeNil)
+
+			return out, files
+		}
+
+		sequential, want := generate(1)
+		for _, jobs := range []int{0, 2, 8} {
+			out, files := generate(jobs)
+			So(files, ShouldResemble, want)
+
+			for _, filename := range files {
+				content, err := out.ReadFile(filename)
+				So(err, ShouldBeNil)
+				expected, err := sequential.ReadFile(filename)
+				So(err, ShouldBeNil)
+				So(string(content), ShouldEqual, string(expected))
+			}
+		}
+	})
+}
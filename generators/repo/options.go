@@ -25,9 +25,15 @@ type Options struct {
 	// go-pg version
 	GoPGVer int
 
+	// Jobs is a count of namespaces generated concurrently, count of CPUs is used if not set
+	Jobs int
+
 	// custom templates
 	RepoTemplatePath string
 
+	// TemplatesDir stores custom templates and partials in repo subdir
+	TemplatesDir string
+
 	// custom types
 	CustomTypes mfd.CustomTypes
 }
@@ -58,28 +58,29 @@ func ({{.ShortVarName}}r {{.Name}}Repo) WithEnabledOnly() {{.Name}}Repo {
 	return {{.ShortVarName}}r
 }
 
-{{range $i, $e := .Entities}}/*** {{.Name}} ***/
+{{range .Entities}}{{template "repo.entity" dict "Namespace" $ "Entity" .}}{{end}}
+{{define "repo.entity"}}{{$ns := .Namespace}}{{with .Entity}}{{$e := .}}/*** {{.Name}} ***/
 
 // Full{{.Name}} returns full joins with all columns
-func ({{$.ShortVarName}}r {{$.Name}}Repo) Full{{.Name}}() OpFunc {
-	return WithColumns({{$.ShortVarName}}r.join[Tables.{{.Name}}.Name]...)
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) Full{{.Name}}() OpFunc {
+	return WithColumns({{$ns.ShortVarName}}r.join[Tables.{{.Name}}.Name]...)
 }
 
 // Default{{.Name}}Sort returns default sort.
-func ({{$.ShortVarName}}r {{$.Name}}Repo) Default{{.Name}}Sort() OpFunc {
-	return WithSort({{$.ShortVarName}}r.sort[Tables.{{.Name}}.Name]...)
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) Default{{.Name}}Sort() OpFunc {
+	return WithSort({{$ns.ShortVarName}}r.sort[Tables.{{.Name}}.Name]...)
 }
 {{if .HasPKs}}
 // {{.Name}}ByID is a function that returns {{.Name}} by ID(s) or nil.
-func ({{$.ShortVarName}}r {{$.Name}}Repo) {{.Name}}ByID(ctx context.Context{{range .PKs}}, {{.Arg}} {{.Type}}{{end}}, ops ...OpFunc) (*{{.Name}}, error) {
-	return {{$.ShortVarName}}r.One{{.Name}}(ctx, &{{.Name}}Search{ {{range $i, $e := .PKs}}{{if $i}}, {{end}}{{.Field}}: &{{.Arg}}{{end}} }, ops...)
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) {{.Name}}ByID(ctx context.Context{{range .PKs}}, {{.Arg}} {{.Type}}{{end}}, ops ...OpFunc) (*{{.Name}}, error) {
+	return {{$ns.ShortVarName}}r.One{{.Name}}(ctx, &{{.Name}}Search{ {{range $i, $e := .PKs}}{{if $i}}, {{end}}{{.Field}}: &{{.Arg}}{{end}} }, ops...)
 }
 {{end}}
 
 // One{{.Name}} is a function that returns one {{.Name}} by filters. It could return pg.ErrMultiRows.
-func ({{$.ShortVarName}}r {{$.Name}}Repo) One{{.Name}}(ctx context.Context, search *{{.Name}}Search, ops ...OpFunc) (*{{.Name}}, error) {
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) One{{.Name}}(ctx context.Context, search *{{.Name}}Search, ops ...OpFunc) (*{{.Name}}, error) {
 	obj := &{{.Name}}{}
-	err := buildQuery(ctx, {{$.ShortVarName}}r.db, obj, search, {{$.ShortVarName}}r.filters[Tables.{{.Name}}.Name], PagerTwo, ops...).Select()
+	err := buildQuery(ctx, {{$ns.ShortVarName}}r.db, obj, search, {{$ns.ShortVarName}}r.filters[Tables.{{.Name}}.Name], PagerTwo, ops...).Select()
 
 	if errors.Is(err, pg.ErrMultiRows) {
 		return nil, err
@@ -91,19 +92,19 @@ func ({{$.ShortVarName}}r {{$.Name}}Repo) One{{.Name}}(ctx context.Context, sear
 }
 
 // {{.NamePlural}}ByFilters returns {{.Name}} list.
-func ({{$.ShortVarName}}r {{$.Name}}Repo) {{.NamePlural}}ByFilters(ctx context.Context, search *{{.Name}}Search, pager Pager, ops ...OpFunc) ({{.VarNamePlural}} []{{.Name}}, err error) {
-	err = buildQuery(ctx, {{$.ShortVarName}}r.db, &{{.VarNamePlural}}, search, {{$.ShortVarName}}r.filters[Tables.{{.Name}}.Name], pager, ops...).Select()
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) {{.NamePlural}}ByFilters(ctx context.Context, search *{{.Name}}Search, pager Pager, ops ...OpFunc) ({{.VarNamePlural}} []{{.Name}}, err error) {
+	err = buildQuery(ctx, {{$ns.ShortVarName}}r.db, &{{.VarNamePlural}}, search, {{$ns.ShortVarName}}r.filters[Tables.{{.Name}}.Name], pager, ops...).Select()
 	return
 }
 
 // Count{{.NamePlural}} returns count
-func ({{$.ShortVarName}}r {{$.Name}}Repo) Count{{.NamePlural}}(ctx context.Context, search *{{.Name}}Search, ops ...OpFunc) (int, error) {
-	return buildQuery(ctx, {{$.ShortVarName}}r.db, &{{.Name}}{}, search, {{$.ShortVarName}}r.filters[Tables.{{.Name}}.Name], PagerOne, ops...).Count()
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) Count{{.NamePlural}}(ctx context.Context, search *{{.Name}}Search, ops ...OpFunc) (int, error) {
+	return buildQuery(ctx, {{$ns.ShortVarName}}r.db, &{{.Name}}{}, search, {{$ns.ShortVarName}}r.filters[Tables.{{.Name}}.Name], PagerOne, ops...).Count()
 }
 
 // Add{{.Name}} adds {{.Name}} to DB.
-func ({{$.ShortVarName}}r {{$.Name}}Repo) Add{{.Name}}(ctx context.Context, {{.VarName}} *{{.Name}}, ops ...OpFunc) (*{{.Name}}, error) {
-	q := {{$.ShortVarName}}r.db.ModelContext(ctx, {{.VarName}})
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) Add{{.Name}}(ctx context.Context, {{.VarName}} *{{.Name}}, ops ...OpFunc) (*{{.Name}}, error) {
+	q := {{$ns.ShortVarName}}r.db.ModelContext(ctx, {{.VarName}})
 	{{- if .HasNotAddable }}
 	if len(ops) == 0 {
 		q = q.ExcludeColumn({{range .NotAddable}}Columns.{{$e.Name}}.{{.}},{{end}})
@@ -116,8 +117,8 @@ func ({{$.ShortVarName}}r {{$.Name}}Repo) Add{{.Name}}(ctx context.Context, {{.V
 }
 
 // Update{{.Name}} updates {{.Name}} in DB.
-func ({{$.ShortVarName}}r {{$.Name}}Repo) Update{{.Name}}(ctx context.Context, {{.VarName}} *{{.Name}}, ops ...OpFunc) (bool, error) {
-	q := {{$.ShortVarName}}r.db.ModelContext(ctx, {{.VarName}}).WherePK()
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) Update{{.Name}}(ctx context.Context, {{.VarName}} *{{.Name}}, ops ...OpFunc) (bool, error) {
+	q := {{$ns.ShortVarName}}r.db.ModelContext(ctx, {{.VarName}}).WherePK()
 	{{- if .HasNotUpdatable }}
 	if len(ops) == 0 {
 		q = q.ExcludeColumn({{range .NotUpdatable}}Columns.{{$e.Name}}.{{.}},{{end}})
@@ -133,14 +134,14 @@ func ({{$.ShortVarName}}r {{$.Name}}Repo) Update{{.Name}}(ctx context.Context, {
 }
 {{if .HasPKs}}
 // Delete{{.Name}} {{if .HasStatus}}set statusId to deleted in DB{{else}}deletes {{.Name}} from DB{{end}}.
-func ({{$.ShortVarName}}r {{$.Name}}Repo) Delete{{.Name}}(ctx context.Context{{range .PKs}}, {{.Arg}} {{.Type}}{{end}}) (deleted bool, err error) {
+func ({{$ns.ShortVarName}}r {{$ns.Name}}Repo) Delete{{.Name}}(ctx context.Context{{range .PKs}}, {{.Arg}} {{.Type}}{{end}}) (deleted bool, err error) {
 	{{.VarName}} := &{{.Name}}{ {{range $i, $e := .PKs}}{{if $i}}, {{end}}{{.Field}}: {{.Arg}}{{end}}{{if .HasStatus}}, StatusID: StatusDeleted,{{end}} }
 
-{{if .HasStatus}}return {{$.ShortVarName}}r.Update{{.Name}}(ctx, {{.VarName}}, WithColumns(Columns.{{.Name}}.StatusID)){{else}}res, err := {{$.ShortVarName}}r.db.ModelContext(ctx, {{.VarName}}).WherePK().Delete()
+{{if .HasStatus}}return {{$ns.ShortVarName}}r.Update{{.Name}}(ctx, {{.VarName}}, WithColumns(Columns.{{.Name}}.StatusID)){{else}}res, err := {{$ns.ShortVarName}}r.db.ModelContext(ctx, {{.VarName}}).WherePK().Delete()
 	if err != nil {
 		return false, err
 	}
 
 	return res.RowsAffected() > 0, err{{end}}
 }{{end}}
-{{end}}`
+{{end}}{{end}}`
@@ -15,6 +15,7 @@ const (
 	PackageVTTemplate = "vt-template"
 	PackageVTUpdated  = "vt-updated"
 	PackageDBTest     = "test"
+	PackageDocs       = "docs"
 
 	PrefixAll    = "all"
 	PrefixEntity = "entities"
@@ -37,4 +38,6 @@ var (
 	PathExpectedVTTemplateEntity = filepath.Join(PathExpected, PackageVTTemplate, PrefixEntity)
 	PathActualDBTest             = filepath.Join(PathActual, PackageDB, PackageDBTest)
 	PathExpectedDBTest           = filepath.Join(PathExpected, PackageDB, PackageDBTest)
+	PathActualDocs               = filepath.Join(PathActual, PackageDocs)
+	PathExpectedDocs             = filepath.Join(PathExpected, PackageDocs)
 )
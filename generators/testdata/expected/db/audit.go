@@ -0,0 +1,84 @@
+//lint:file-ignore U1000 ignore unused code, it's generated
+//nolint:structcheck,unused
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+const (
+	AuditActionAdd    = "add"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditLog stores changes of vt entity made by user.
+//
+//	CREATE TABLE "auditLogs" (
+//		"auditLogId" serial PRIMARY KEY,
+//		"entity" varchar(255) NOT NULL,
+//		"entityId" varchar(255) NOT NULL,
+//		"action" varchar(32) NOT NULL,
+//		"userId" int,
+//		"changes" jsonb,
+//		"createdAt" timestamptz NOT NULL DEFAULT now()
+//	);
+//	CREATE INDEX "IX_auditLogs_entity_entityId" ON "auditLogs" ("entity", "entityId");
+type AuditLog struct {
+	tableName struct{} `pg:"auditLogs,alias:t,discard_unknown_columns"`
+
+	ID        int             `pg:"auditLogId,pk"`
+	Entity    string          `pg:"entity,use_zero"`
+	EntityID  string          `pg:"entityId,use_zero"`
+	Action    string          `pg:"action,use_zero"`
+	UserID    *int            `pg:"userId"`
+	Changes   json.RawMessage `pg:"changes,type:jsonb"`
+	CreatedAt time.Time       `pg:"createdAt,use_zero"`
+}
+
+// AuditChange stores old and new values of changed field.
+type AuditChange struct {
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+}
+
+type AuditRepo struct {
+	db orm.DB
+}
+
+// NewAuditRepo returns new repository
+func NewAuditRepo(db orm.DB) AuditRepo {
+	return AuditRepo{db: db}
+}
+
+// WithTransaction is a function that wraps AuditRepo with pg.Tx transaction.
+func (ar AuditRepo) WithTransaction(tx *pg.Tx) AuditRepo {
+	ar.db = tx
+	return ar
+}
+
+// AddAuditLog adds AuditLog to DB.
+func (ar AuditRepo) AddAuditLog(ctx context.Context, auditLog *AuditLog) (*AuditLog, error) {
+	if auditLog.CreatedAt.IsZero() {
+		auditLog.CreatedAt = time.Now()
+	}
+
+	_, err := ar.db.ModelContext(ctx, auditLog).Insert()
+	return auditLog, err
+}
+
+// AuditLogs returns changes of entity, newest first.
+func (ar AuditRepo) AuditLogs(ctx context.Context, entity, entityID string, pager Pager) (auditLogs []AuditLog, err error) {
+	q := ar.db.ModelContext(ctx, &auditLogs).
+		Where(`?TableAlias.? = ?`, pg.Ident("entity"), entity).
+		Where(`?TableAlias.? = ?`, pg.Ident("entityId"), entityID).
+		OrderExpr(`?TableAlias.? DESC`, pg.Ident("createdAt"))
+
+	err = pager.Apply(q).Select()
+	return
+}
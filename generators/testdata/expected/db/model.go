@@ -251,12 +251,17 @@ type Category struct {
 	StatusID    int    `pg:"statusId,use_zero"`
 }
 
+// Новости портала
 type News struct {
 	tableName struct{} `pg:"news,alias:t,discard_unknown_columns"`
 
-	ID          int        `pg:"newsId,pk"`
-	Title       string     `pg:"title,use_zero"`
-	Preview     *string    `pg:"preview"`
+	// id новости
+	ID int `pg:"newsId,pk"`
+	// Заголовок новости
+	Title string `pg:"title,use_zero"`
+	// Ссылка на фото-превью новости
+	Preview *string `pg:"preview"`
+	// Контент новости
 	Content     *string    `pg:"content"`
 	CategoryID  int        `pg:"categoryId,use_zero"`
 	CountryID   *int       `pg:"countryId"`
@@ -192,6 +192,7 @@ type NewsService struct {
 	embedlog.Logger
 	portalRepo db.PortalRepo
 	geoRepo    db.GeoRepo
+	auth       Authorizer
 }
 
 func NewNewsService(dbo db.DB, logger embedlog.Logger) *NewsService {
@@ -199,6 +200,7 @@ func NewNewsService(dbo db.DB, logger embedlog.Logger) *NewsService {
 		Logger:     logger,
 		portalRepo: db.NewPortalRepo(dbo),
 		geoRepo:    db.NewGeoRepo(dbo),
+		auth:       DefaultAuthorizer,
 	}
 }
 
@@ -218,10 +220,17 @@ func (s NewsService) dbSort(ops *ViewOps) db.OpFunc {
 
 // Count returns count News according to conditions in search params.
 //
+// Новости портала
+//
 //zenrpc:search NewsSearch
 //zenrpc:return int
 //zenrpc:500 Internal Error
 func (s NewsService) Count(ctx context.Context, search *NewsSearch) (int, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+
 	count, err := s.portalRepo.CountNews(ctx, search.ToDB())
 	if err != nil {
 		return 0, InternalError(err)
@@ -231,11 +240,18 @@ func (s NewsService) Count(ctx context.Context, search *NewsSearch) (int, error)
 
 // Get returns а list of News according to conditions in search params.
 //
+// Новости портала
+//
 //zenrpc:search NewsSearch
 //zenrpc:viewOps ViewOps
 //zenrpc:return []NewsSummary
 //zenrpc:500 Internal Error
 func (s NewsService) Get(ctx context.Context, search *NewsSearch, viewOps *ViewOps) ([]NewsSummary, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
 	list, err := s.portalRepo.NewsByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.portalRepo.FullNews())
 	if err != nil {
 		return nil, InternalError(err)
@@ -251,11 +267,17 @@ func (s NewsService) Get(ctx context.Context, search *NewsSearch, viewOps *ViewO
 
 // GetByID returns a News by its ID.
 //
+// Новости портала
+//
 //zenrpc:id int
 //zenrpc:return News
 //zenrpc:500 Internal Error
 //zenrpc:404 Not Found
 func (s NewsService) GetByID(ctx context.Context, id int) (*News, error) {
+	if !s.auth.CanRead(ctx, "News", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
 	db, err := s.byID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -275,11 +297,17 @@ func (s NewsService) byID(ctx context.Context, id int) (*db.News, error) {
 
 // Add adds a News from the query.
 //
+// Новости портала
+//
 //zenrpc:news News
 //zenrpc:return News
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 func (s NewsService) Add(ctx context.Context, news News) (*News, error) {
+	if !s.auth.CanWrite(ctx, "News", "") {
+		return nil, ErrForbidden
+	}
+
 	if ve := s.isValid(ctx, news, false); ve.HasErrors() {
 		return nil, ve.Error()
 	}
@@ -293,12 +321,18 @@ func (s NewsService) Add(ctx context.Context, news News) (*News, error) {
 
 // Update updates the News data identified by id from the query.
 //
+// Новости портала
+//
 //zenrpc:newsList News
 //zenrpc:return News
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s NewsService) Update(ctx context.Context, news News) (bool, error) {
+	if !s.auth.CanWrite(ctx, "News", entityID(news.ID)) {
+		return false, ErrForbidden
+	}
+
 	if _, err := s.byID(ctx, news.ID); err != nil {
 		return false, err
 	}
@@ -316,12 +350,18 @@ func (s NewsService) Update(ctx context.Context, news News) (bool, error) {
 
 // Delete deletes the News by its ID.
 //
+// Новости портала
+//
 //zenrpc:id int
 //zenrpc:return isDeleted
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s NewsService) Delete(ctx context.Context, id int) (bool, error) {
+	if !s.auth.CanWrite(ctx, "News", entityID(id)) {
+		return false, ErrForbidden
+	}
+
 	if _, err := s.byID(ctx, id); err != nil {
 		return false, err
 	}
@@ -335,11 +375,21 @@ func (s NewsService) Delete(ctx context.Context, id int) (bool, error) {
 
 // Validate verifies that News data is valid.
 //
+// Новости портала
+//
 //zenrpc:news News
 //zenrpc:return []FieldError
 //zenrpc:500 Internal Error
 func (s NewsService) Validate(ctx context.Context, news News) ([]FieldError, error) {
 	isUpdate := news.ID != 0
+	id := ""
+	if isUpdate {
+		id = entityID(news.ID)
+	}
+	if !s.auth.CanWrite(ctx, "News", id) {
+		return nil, ErrForbidden
+	}
+
 	if isUpdate {
 		_, err := s.byID(ctx, news.ID)
 		if err != nil {
@@ -372,6 +422,33 @@ func (s NewsService) isValid(ctx context.Context, news News, isUpdate bool) Vali
 		}
 	}
 
+	if news.CountryID != nil {
+		item, err := s.geoRepo.CountryByID(ctx, *news.CountryID)
+		if err != nil {
+			v.SetInternalError(err)
+		} else if item == nil {
+			v.Append("countryId", FieldErrorIncorrect)
+		}
+	}
+
+	if news.RegionID != nil {
+		item, err := s.geoRepo.RegionByID(ctx, *news.RegionID)
+		if err != nil {
+			v.SetInternalError(err)
+		} else if item == nil {
+			v.Append("regionId", FieldErrorIncorrect)
+		}
+	}
+
+	if news.CityID != nil {
+		item, err := s.geoRepo.CityByID(ctx, *news.CityID)
+		if err != nil {
+			v.SetInternalError(err)
+		} else if item == nil {
+			v.Append("cityId", FieldErrorIncorrect)
+		}
+	}
+
 	if len(news.TagIDs) != 0 {
 		items, err := s.portalRepo.TagsByFilters(ctx, &db.TagSearch{IDs: news.TagIDs}, db.PagerNoLimit)
 		if err != nil {
@@ -381,6 +458,8 @@ func (s NewsService) isValid(ctx context.Context, news News, isUpdate bool) Vali
 		}
 	}
 	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
 	return v
 }
 
@@ -561,3 +640,17 @@ func (s TagService) isValid(ctx context.Context, tag Tag, isUpdate bool) Validat
 	// custom validation starts here
 	return v
 }
+
+// scope checks read access to News and applies authorizer filters to search params.
+func (s NewsService) scope(ctx context.Context, search *NewsSearch) (*NewsSearch, error) {
+	if !s.auth.CanRead(ctx, "News", "") {
+		return nil, ErrForbidden
+	}
+	if search == nil {
+		search = &NewsSearch{}
+	}
+	if err := s.auth.ScopeSearch(ctx, "News", search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
@@ -85,9 +85,13 @@ type CategorySummary struct {
 }
 
 type News struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title" validate:"required,max=255"`
-	Preview     *string    `json:"preview" validate:"omitempty,max=255"`
+	// id новости
+	ID int `json:"id"`
+	// Заголовок новости
+	Title string `json:"title" validate:"required,max=255"`
+	// Ссылка на фото-превью новости
+	Preview *string `json:"preview" validate:"omitempty,max=255"`
+	// Контент новости
 	Content     *string    `json:"content"`
 	CategoryID  int        `json:"categoryId" validate:"required"`
 	CountryID   *int       `json:"countryId"`
@@ -142,9 +146,13 @@ type NewsSearch struct {
 }
 
 type NewsSummary struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	Preview     *string    `json:"preview"`
+	// id новости
+	ID int `json:"id"`
+	// Заголовок новости
+	Title string `json:"title"`
+	// Ссылка на фото-превью новости
+	Preview *string `json:"preview"`
+	// Контент новости
 	Content     *string    `json:"content"`
 	CategoryID  int        `json:"categoryId"`
 	CountryID   *int       `json:"countryId"`
@@ -0,0 +1,115 @@
+package vt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/vmkteam/mfd-generator/generators/testdata/expected/db"
+)
+
+const (
+	AuditActionAdd    = db.AuditActionAdd
+	AuditActionUpdate = db.AuditActionUpdate
+	AuditActionDelete = db.AuditActionDelete
+)
+
+type auditUserKey struct{}
+
+// WithAuditUser returns context with id of the current user, call it in auth middleware of http handler or zenrpc server.
+func WithAuditUser(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, auditUserKey{}, userID)
+}
+
+// AuditUserID returns id of the current user for audit log, nil if unknown.
+// By default user is read from context set by WithAuditUser, replace it on application start to read user from your auth context.
+var AuditUserID = func(ctx context.Context) *int {
+	if userID, ok := ctx.Value(auditUserKey{}).(int); ok {
+		return &userID
+	}
+	return nil
+}
+
+// auditChange is a change of entity made in transaction, it is written to audit log after commit.
+type auditChange[T any] struct {
+	action     string
+	prev, next *T
+}
+
+// AuditLog is a single change of entity.
+type AuditLog struct {
+	ID        int                       `json:"id"`
+	Action    string                    `json:"action"`
+	UserID    *int                      `json:"userId"`
+	Changes   map[string]db.AuditChange `json:"changes"`
+	CreatedAt time.Time                 `json:"createdAt"`
+}
+
+func NewAuditLog(in *db.AuditLog) *AuditLog {
+	if in == nil {
+		return nil
+	}
+
+	auditLog := &AuditLog{
+		ID:        in.ID,
+		Action:    in.Action,
+		UserID:    in.UserID,
+		CreatedAt: in.CreatedAt,
+	}
+	if len(in.Changes) > 0 {
+		_ = json.Unmarshal(in.Changes, &auditLog.Changes)
+	}
+
+	return auditLog
+}
+
+// newAuditLog creates audit log with changed fields of prev and next entities.
+func newAuditLog(ctx context.Context, entity, action, entityID string, fields []string, prev, next interface{}) (*db.AuditLog, error) {
+	prevFields, err := auditFields(prev)
+	if err != nil {
+		return nil, err
+	}
+	nextFields, err := auditFields(next)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]db.AuditChange)
+	for _, field := range fields {
+		oldValue, newValue := prevFields[field], nextFields[field]
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		changes[field] = db.AuditChange{Old: oldValue, New: newValue}
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &db.AuditLog{
+		Entity:   entity,
+		EntityID: entityID,
+		Action:   action,
+		UserID:   AuditUserID(ctx),
+		Changes:  b,
+	}, nil
+}
+
+// auditFields returns json encoded fields of entity by their json names.
+func auditFields(v interface{}) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return fields, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, json.Unmarshal(b, &fields)
+}
@@ -0,0 +1,81 @@
+package vt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmkteam/zenrpc/v2"
+)
+
+var ErrForbidden = zenrpc.NewStringError(http.StatusForbidden, "forbidden")
+
+// Authorizer checks access of the current user to vt entities.
+type Authorizer interface {
+	// CanRead checks if entity can be read, id is empty for lists.
+	CanRead(ctx context.Context, entity, id string) bool
+
+	// CanWrite checks if entity can be added, updated or deleted, id is empty for new entities.
+	CanWrite(ctx context.Context, entity, id string) bool
+
+	// ScopeSearch adds filters to entity search params, e.g. *NewsSearch, before querying db.
+	ScopeSearch(ctx context.Context, entity string, search interface{}) error
+}
+
+// DefaultAuthorizer is used by services, replace it on application start before creating services.
+var DefaultAuthorizer Authorizer = AllowAll{}
+
+// AllowAll allows any user to read and write every entity.
+type AllowAll struct{}
+
+func (AllowAll) CanRead(context.Context, string, string) bool { return true }
+
+func (AllowAll) CanWrite(context.Context, string, string) bool { return true }
+
+func (AllowAll) ScopeSearch(context.Context, string, interface{}) error { return nil }
+
+// EntityRoles stores roles required to access entities, entities without roles are available to any user.
+var EntityRoles = map[string][]string{}
+
+// RoleAuthorizer allows access to entities for users with one of EntityRoles.
+type RoleAuthorizer struct {
+	AllowAll
+
+	// UserRoles returns roles of the current user.
+	UserRoles func(ctx context.Context) []string
+}
+
+func (a RoleAuthorizer) CanRead(ctx context.Context, entity, _ string) bool {
+	return a.hasRole(ctx, entity)
+}
+
+func (a RoleAuthorizer) CanWrite(ctx context.Context, entity, _ string) bool {
+	return a.hasRole(ctx, entity)
+}
+
+func (a RoleAuthorizer) hasRole(ctx context.Context, entity string) bool {
+	roles, ok := EntityRoles[entity]
+	if !ok {
+		return true
+	}
+
+	for _, userRole := range a.UserRoles(ctx) {
+		for _, role := range roles {
+			if role == userRole {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// entityID joins primary keys of entity to a single string.
+func entityID(pks ...interface{}) string {
+	ids := make([]string, len(pks))
+	for i, pk := range pks {
+		ids[i] = fmt.Sprint(pk)
+	}
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,242 @@
+package vt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmkteam/zenrpc/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var (
+	ErrUnsupportedFormat = zenrpc.NewStringError(http.StatusBadRequest, "unsupported file format")
+	ErrInvalidFile       = zenrpc.NewStringError(http.StatusBadRequest, "invalid file")
+)
+
+// ExportFile is a file with exported entities.
+type ExportFile struct {
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Content     []byte `json:"content"`
+}
+
+// ImportFile is a csv or xlsx file with entities, first row is a header with field names.
+type ImportFile struct {
+	Name    string `json:"name"`
+	Format  string `json:"format" validate:"required,oneof=csv xlsx"`
+	Content []byte `json:"content" validate:"required"`
+}
+
+// ImportResult stores import stats and errors of rows that were not imported.
+type ImportResult struct {
+	Added   int           `json:"added"`
+	Updated int           `json:"updated"`
+	Errors  []ImportError `json:"errors"`
+}
+
+// ImportError stores error of a row in imported file.
+type ImportError struct {
+	Row     int          `json:"row"`
+	Message string       `json:"message,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// NewExportFile writes header and rows to csv or xlsx file.
+func NewExportFile(name, format string, header []string, rows [][]string) (*ExportFile, error) {
+	var buf bytes.Buffer
+	file := &ExportFile{Name: name + "." + format}
+
+	switch format {
+	case FormatCSV:
+		file.ContentType = "text/csv"
+		w := csv.NewWriter(&buf)
+		if err := w.Write(header); err != nil {
+			return nil, InternalError(err)
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return nil, InternalError(err)
+		}
+	case FormatXLSX:
+		file.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		f := excelize.NewFile()
+		defer f.Close()
+
+		sheet := f.GetSheetName(0)
+		for i, row := range append([][]string{header}, rows...) {
+			if err := f.SetSheetRow(sheet, "A"+strconv.Itoa(i+1), &row); err != nil {
+				return nil, InternalError(err)
+			}
+		}
+		if _, err := f.WriteTo(&buf); err != nil {
+			return nil, InternalError(err)
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	file.Content = buf.Bytes()
+	return file, nil
+}
+
+// Rows reads file rows as maps with header values as keys.
+func (f ImportFile) Rows() ([]map[string]string, error) {
+	var lines [][]string
+	switch f.Format {
+	case FormatCSV:
+		r := csv.NewReader(bytes.NewReader(f.Content))
+		r.FieldsPerRecord = -1
+
+		var err error
+		if lines, err = r.ReadAll(); err != nil {
+			return nil, ErrInvalidFile
+		}
+	case FormatXLSX:
+		xlsx, err := excelize.OpenReader(bytes.NewReader(f.Content))
+		if err != nil {
+			return nil, ErrInvalidFile
+		}
+		defer xlsx.Close()
+
+		if lines, err = xlsx.GetRows(xlsx.GetSheetName(0)); err != nil {
+			return nil, ErrInvalidFile
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrInvalidFile
+	}
+
+	header, rows := lines[0], make([]map[string]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		row := make(map[string]string, len(header))
+		for i, value := range line {
+			if i < len(header) {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// exportValue converts model field value to string.
+func exportValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+
+	switch val := rv.Interface().(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339)
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		if rv.IsNil() {
+			return ""
+		}
+		fallthrough
+	case reflect.Struct, reflect.Array:
+		b, err := json.Marshal(rv.Interface())
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+
+	return fmt.Sprint(rv.Interface())
+}
+
+// decodeRow fills model fields from row values by their json names.
+func decodeRow(row map[string]string, v interface{}) error {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name, _, _ := strings.Cut(rt.Field(i).Tag.Get("json"), ",")
+		raw, ok := row[name]
+		if !ok || raw == "" || name == "-" {
+			continue
+		}
+
+		if err := setValue(rv.Field(i), raw); err != nil {
+			return fmt.Errorf("invalid %s value: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// setValue sets string value to field according to its type.
+func setValue(fv reflect.Value, raw string) error {
+	if fv.Kind() == reflect.Ptr {
+		pv := reflect.New(fv.Type().Elem())
+		if err := setValue(pv.Elem(), raw); err != nil {
+			return err
+		}
+		fv.Set(pv)
+		return nil
+	}
+
+	if _, ok := fv.Interface().(time.Time); ok {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	default:
+		return json.Unmarshal([]byte(raw), fv.Addr().Interface())
+	}
+
+	return nil
+}
@@ -0,0 +1,1180 @@
+package vt
+
+import (
+	"context"
+	"errors"
+	"github.com/go-pg/pg/v10"
+
+	"github.com/vmkteam/mfd-generator/generators/testdata/expected/db"
+
+	"github.com/vmkteam/embedlog"
+	"github.com/vmkteam/zenrpc/v2"
+)
+
+type CityService struct {
+	zenrpc.Service
+	embedlog.Logger
+	geoRepo   db.GeoRepo
+	auditRepo db.AuditRepo
+	auth      Authorizer
+}
+
+func NewCityService(dbo db.DB, logger embedlog.Logger) *CityService {
+	return &CityService{
+		Logger:    logger,
+		geoRepo:   db.NewGeoRepo(dbo),
+		auditRepo: db.NewAuditRepo(dbo),
+		auth:      DefaultAuthorizer,
+	}
+}
+
+func (s CityService) dbSort(ops *ViewOps) db.OpFunc {
+	v := s.geoRepo.DefaultCitySort()
+	if ops == nil {
+		return v
+	}
+
+	switch ops.SortColumn {
+	case db.Columns.City.ID, db.Columns.City.RegionID, db.Columns.City.CountryID, db.Columns.City.Title, db.Columns.City.AltTitle, db.Columns.City.Alias, db.Columns.City.OrderNumber, db.Columns.City.StatusID:
+		v = db.WithSort(db.NewSortField(ops.SortColumn, ops.SortDesc))
+	}
+
+	return v
+}
+
+// scope checks read access to Cities and applies authorizer filters to search params.
+func (s CityService) scope(ctx context.Context, search *CitySearch) (*CitySearch, error) {
+	if !s.auth.CanRead(ctx, "City", "") {
+		return nil, ErrForbidden
+	}
+	if search == nil {
+		search = &CitySearch{}
+	}
+	if err := s.auth.ScopeSearch(ctx, "City", search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Count returns count Cities according to conditions in search params.
+//
+//zenrpc:search CitySearch
+//zenrpc:return int
+//zenrpc:500 Internal Error
+func (s CityService) Count(ctx context.Context, search *CitySearch) (int, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.geoRepo.CountCities(ctx, search.ToDB())
+	if err != nil {
+		return 0, InternalError(err)
+	}
+	return count, nil
+}
+
+// Get returns а list of Cities according to conditions in search params.
+//
+//zenrpc:search CitySearch
+//zenrpc:viewOps ViewOps
+//zenrpc:return []CitySummary
+//zenrpc:500 Internal Error
+func (s CityService) Get(ctx context.Context, search *CitySearch, viewOps *ViewOps) ([]CitySummary, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.geoRepo.CitiesByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.geoRepo.FullCity())
+	if err != nil {
+		return nil, InternalError(err)
+	}
+	cities := make([]CitySummary, 0, len(list))
+	for i := 0; i < len(list); i++ {
+		if city := NewCitySummary(&list[i]); city != nil {
+			cities = append(cities, *city)
+		}
+	}
+	return cities, nil
+}
+
+// GetByID returns a City by its ID.
+//
+//zenrpc:id int
+//zenrpc:return City
+//zenrpc:500 Internal Error
+//zenrpc:404 Not Found
+func (s CityService) GetByID(ctx context.Context, id int) (*City, error) {
+	if !s.auth.CanRead(ctx, "City", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
+	db, err := s.byID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return NewCity(db), nil
+}
+
+func (s CityService) byID(ctx context.Context, id int) (*db.City, error) {
+	db, err := s.geoRepo.CityByID(ctx, id, s.geoRepo.FullCity())
+	if err != nil {
+		return nil, InternalError(err)
+	} else if db == nil {
+		return nil, ErrNotFound
+	}
+	return db, nil
+}
+
+// History returns changes of the City from audit log, newest first.
+//
+//zenrpc:id int
+//zenrpc:return []AuditLog
+//zenrpc:500 Internal Error
+func (s CityService) History(ctx context.Context, id int) ([]AuditLog, error) {
+	if !s.auth.CanRead(ctx, "City", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
+	list, err := s.auditRepo.AuditLogs(ctx, "City", entityID(id), db.PagerNoLimit)
+	if err != nil {
+		return nil, InternalError(err)
+	}
+	auditLogs := make([]AuditLog, 0, len(list))
+	for i := range list {
+		if auditLog := NewAuditLog(&list[i]); auditLog != nil {
+			auditLogs = append(auditLogs, *auditLog)
+		}
+	}
+	return auditLogs, nil
+}
+
+// Add adds a City from the query.
+//
+//zenrpc:city City
+//zenrpc:return City
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+func (s CityService) Add(ctx context.Context, city City) (*City, error) {
+	if !s.auth.CanWrite(ctx, "City", "") {
+		return nil, ErrForbidden
+	}
+
+	if ve := s.isValid(ctx, city, false); ve.HasErrors() {
+		return nil, ve.Error()
+	}
+
+	db, err := s.geoRepo.AddCity(ctx, city.ToDB())
+	if err != nil {
+		return nil, InternalError(err)
+	}
+
+	added := NewCity(db)
+	s.audit(ctx, AuditActionAdd, nil, added)
+	return added, nil
+}
+
+// Update updates the City data identified by id from the query.
+//
+//zenrpc:cities City
+//zenrpc:return City
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+//zenrpc:404 Not Found
+func (s CityService) Update(ctx context.Context, city City) (bool, error) {
+	if !s.auth.CanWrite(ctx, "City", entityID(city.ID)) {
+		return false, ErrForbidden
+	}
+
+	old, err := s.byID(ctx, city.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if ve := s.isValid(ctx, city, true); ve.HasErrors() {
+		return false, ve.Error()
+	}
+
+	ok, err := s.geoRepo.UpdateCity(ctx, city.ToDB())
+	if err != nil {
+		return false, InternalError(err)
+	}
+
+	if ok {
+		s.audit(ctx, AuditActionUpdate, NewCity(old), &city)
+	}
+	return ok, nil
+}
+
+// Delete deletes the City by its ID.
+//
+//zenrpc:id int
+//zenrpc:return isDeleted
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+//zenrpc:404 Not Found
+func (s CityService) Delete(ctx context.Context, id int) (bool, error) {
+	if !s.auth.CanWrite(ctx, "City", entityID(id)) {
+		return false, ErrForbidden
+	}
+
+	old, err := s.byID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := s.geoRepo.DeleteCity(ctx, id)
+	if err != nil {
+		return false, InternalError(err)
+	}
+
+	if ok {
+		s.audit(ctx, AuditActionDelete, NewCity(old), nil)
+	}
+	return ok, err
+}
+
+// Validate verifies that City data is valid.
+//
+//zenrpc:city City
+//zenrpc:return []FieldError
+//zenrpc:500 Internal Error
+func (s CityService) Validate(ctx context.Context, city City) ([]FieldError, error) {
+	isUpdate := city.ID != 0
+	id := ""
+	if isUpdate {
+		id = entityID(city.ID)
+	}
+	if !s.auth.CanWrite(ctx, "City", id) {
+		return nil, ErrForbidden
+	}
+
+	if isUpdate {
+		_, err := s.byID(ctx, city.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ve := s.isValid(ctx, city, isUpdate)
+	if ve.HasInternalError() {
+		return nil, ve.Error()
+	}
+
+	return ve.Fields(), nil
+}
+
+func (s CityService) isValid(ctx context.Context, city City, isUpdate bool) Validator {
+	var v Validator
+
+	if v.CheckBasic(ctx, city); v.HasInternalError() {
+		return v
+	}
+
+	// check alias unique
+	search := &db.CitySearch{
+		Alias: &city.Alias,
+		NotID: &city.ID,
+	}
+	item, err := s.geoRepo.OneCity(ctx, search)
+	if err != nil {
+		v.SetInternalError(err)
+	} else if item != nil {
+		v.Append("alias", FieldErrorUnique)
+	}
+
+	// check fks
+	if city.RegionID != 0 {
+		item, err := s.geoRepo.RegionByID(ctx, city.RegionID)
+		if err != nil {
+			v.SetInternalError(err)
+		} else if item == nil {
+			v.Append("regionId", FieldErrorIncorrect)
+		}
+	}
+
+	if city.CountryID != 0 {
+		item, err := s.geoRepo.CountryByID(ctx, city.CountryID)
+		if err != nil {
+			v.SetInternalError(err)
+		} else if item == nil {
+			v.Append("countryId", FieldErrorIncorrect)
+		}
+	}
+
+	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
+	return v
+}
+
+// audit writes changed fields of the City to audit log.
+func (s CityService) audit(ctx context.Context, action string, prev, next *City) {
+	item := next
+	if item == nil {
+		item = prev
+	}
+
+	fields := []string{"id", "regionId", "countryId", "title", "altTitle", "alias", "orderNumber", "statusId"}
+	auditLog, err := newAuditLog(ctx, "City", action, entityID(item.ID), fields, prev, next)
+	if err == nil {
+		_, err = s.auditRepo.AddAuditLog(ctx, auditLog)
+	}
+	if err != nil {
+		s.Error(ctx, "write audit log failed", "entity", "City", "action", action, "err", err)
+	}
+}
+
+type CountryService struct {
+	zenrpc.Service
+	embedlog.Logger
+	geoRepo db.GeoRepo
+	auth    Authorizer
+}
+
+func NewCountryService(dbo db.DB, logger embedlog.Logger) *CountryService {
+	return &CountryService{
+		Logger:  logger,
+		geoRepo: db.NewGeoRepo(dbo),
+		auth:    DefaultAuthorizer,
+	}
+}
+
+func (s CountryService) dbSort(ops *ViewOps) db.OpFunc {
+	v := s.geoRepo.DefaultCountrySort()
+	if ops == nil {
+		return v
+	}
+
+	switch ops.SortColumn {
+	case db.Columns.Country.ID, db.Columns.Country.Title, db.Columns.Country.AltTitle, db.Columns.Country.Alias, db.Columns.Country.OrderNumber, db.Columns.Country.H1, db.Columns.Country.PageTitle, db.Columns.Country.MetaDescription, db.Columns.Country.StatusID:
+		v = db.WithSort(db.NewSortField(ops.SortColumn, ops.SortDesc))
+	}
+
+	return v
+}
+
+// scope checks read access to Countries and applies authorizer filters to search params.
+func (s CountryService) scope(ctx context.Context, search *CountrySearch) (*CountrySearch, error) {
+	if !s.auth.CanRead(ctx, "Country", "") {
+		return nil, ErrForbidden
+	}
+	if search == nil {
+		search = &CountrySearch{}
+	}
+	if err := s.auth.ScopeSearch(ctx, "Country", search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Count returns count Countries according to conditions in search params.
+//
+//zenrpc:search CountrySearch
+//zenrpc:return int
+//zenrpc:500 Internal Error
+func (s CountryService) Count(ctx context.Context, search *CountrySearch) (int, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.geoRepo.CountCountries(ctx, search.ToDB())
+	if err != nil {
+		return 0, InternalError(err)
+	}
+	return count, nil
+}
+
+// Get returns а list of Countries according to conditions in search params.
+//
+//zenrpc:search CountrySearch
+//zenrpc:viewOps ViewOps
+//zenrpc:return []CountrySummary
+//zenrpc:500 Internal Error
+func (s CountryService) Get(ctx context.Context, search *CountrySearch, viewOps *ViewOps) ([]CountrySummary, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.geoRepo.CountriesByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.geoRepo.FullCountry())
+	if err != nil {
+		return nil, InternalError(err)
+	}
+	countries := make([]CountrySummary, 0, len(list))
+	for i := 0; i < len(list); i++ {
+		if country := NewCountrySummary(&list[i]); country != nil {
+			countries = append(countries, *country)
+		}
+	}
+	return countries, nil
+}
+
+// GetByID returns a Country by its ID.
+//
+//zenrpc:id int
+//zenrpc:return Country
+//zenrpc:500 Internal Error
+//zenrpc:404 Not Found
+func (s CountryService) GetByID(ctx context.Context, id int) (*Country, error) {
+	if !s.auth.CanRead(ctx, "Country", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
+	db, err := s.byID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return NewCountry(db), nil
+}
+
+func (s CountryService) byID(ctx context.Context, id int) (*db.Country, error) {
+	db, err := s.geoRepo.CountryByID(ctx, id, s.geoRepo.FullCountry())
+	if err != nil {
+		return nil, InternalError(err)
+	} else if db == nil {
+		return nil, ErrNotFound
+	}
+	return db, nil
+}
+
+// Add adds a Country from the query.
+//
+//zenrpc:country Country
+//zenrpc:return Country
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+func (s CountryService) Add(ctx context.Context, country Country) (*Country, error) {
+	if !s.auth.CanWrite(ctx, "Country", "") {
+		return nil, ErrForbidden
+	}
+
+	if ve := s.isValid(ctx, country, false); ve.HasErrors() {
+		return nil, ve.Error()
+	}
+
+	db, err := s.geoRepo.AddCountry(ctx, country.ToDB())
+	if err != nil {
+		return nil, InternalError(err)
+	}
+	return NewCountry(db), nil
+}
+
+// Update updates the Country data identified by id from the query.
+//
+//zenrpc:countries Country
+//zenrpc:return Country
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+//zenrpc:404 Not Found
+func (s CountryService) Update(ctx context.Context, country Country) (bool, error) {
+	if !s.auth.CanWrite(ctx, "Country", entityID(country.ID)) {
+		return false, ErrForbidden
+	}
+
+	if _, err := s.byID(ctx, country.ID); err != nil {
+		return false, err
+	}
+
+	if ve := s.isValid(ctx, country, true); ve.HasErrors() {
+		return false, ve.Error()
+	}
+
+	ok, err := s.geoRepo.UpdateCountry(ctx, country.ToDB())
+	if err != nil {
+		return false, InternalError(err)
+	}
+	return ok, nil
+}
+
+// Delete deletes the Country by its ID.
+//
+//zenrpc:id int
+//zenrpc:return isDeleted
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+//zenrpc:404 Not Found
+func (s CountryService) Delete(ctx context.Context, id int) (bool, error) {
+	if !s.auth.CanWrite(ctx, "Country", entityID(id)) {
+		return false, ErrForbidden
+	}
+
+	if _, err := s.byID(ctx, id); err != nil {
+		return false, err
+	}
+
+	ok, err := s.geoRepo.DeleteCountry(ctx, id)
+	if err != nil {
+		return false, InternalError(err)
+	}
+	return ok, err
+}
+
+// Validate verifies that Country data is valid.
+//
+//zenrpc:country Country
+//zenrpc:return []FieldError
+//zenrpc:500 Internal Error
+func (s CountryService) Validate(ctx context.Context, country Country) ([]FieldError, error) {
+	isUpdate := country.ID != 0
+	id := ""
+	if isUpdate {
+		id = entityID(country.ID)
+	}
+	if !s.auth.CanWrite(ctx, "Country", id) {
+		return nil, ErrForbidden
+	}
+
+	if isUpdate {
+		_, err := s.byID(ctx, country.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ve := s.isValid(ctx, country, isUpdate)
+	if ve.HasInternalError() {
+		return nil, ve.Error()
+	}
+
+	return ve.Fields(), nil
+}
+
+func (s CountryService) isValid(ctx context.Context, country Country, isUpdate bool) Validator {
+	var v Validator
+
+	if v.CheckBasic(ctx, country); v.HasInternalError() {
+		return v
+	}
+
+	// check alias unique
+	search := &db.CountrySearch{
+		Alias: &country.Alias,
+		NotID: &country.ID,
+	}
+	item, err := s.geoRepo.OneCountry(ctx, search)
+	if err != nil {
+		v.SetInternalError(err)
+	} else if item != nil {
+		v.Append("alias", FieldErrorUnique)
+	}
+
+	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
+	return v
+}
+
+type RegionService struct {
+	zenrpc.Service
+	embedlog.Logger
+	geoRepo   db.GeoRepo
+	dbo       db.DB
+	auditRepo db.AuditRepo
+	auth      Authorizer
+}
+
+func NewRegionService(dbo db.DB, logger embedlog.Logger) *RegionService {
+	return &RegionService{
+		Logger:    logger,
+		geoRepo:   db.NewGeoRepo(dbo),
+		dbo:       dbo,
+		auditRepo: db.NewAuditRepo(dbo),
+		auth:      DefaultAuthorizer,
+	}
+}
+
+func (s RegionService) dbSort(ops *ViewOps) db.OpFunc {
+	v := s.geoRepo.DefaultRegionSort()
+	if ops == nil {
+		return v
+	}
+
+	switch ops.SortColumn {
+	case db.Columns.Region.ID, db.Columns.Region.CountryID, db.Columns.Region.Title, db.Columns.Region.AltTitle, db.Columns.Region.Alias, db.Columns.Region.OrderNumber, db.Columns.Region.Image, db.Columns.Region.H1, db.Columns.Region.PageTitle, db.Columns.Region.MetaDescription, db.Columns.Region.StatusID:
+		v = db.WithSort(db.NewSortField(ops.SortColumn, ops.SortDesc))
+	}
+
+	return v
+}
+
+// scope checks read access to Regions and applies authorizer filters to search params.
+func (s RegionService) scope(ctx context.Context, search *RegionSearch) (*RegionSearch, error) {
+	if !s.auth.CanRead(ctx, "Region", "") {
+		return nil, ErrForbidden
+	}
+	if search == nil {
+		search = &RegionSearch{}
+	}
+	if err := s.auth.ScopeSearch(ctx, "Region", search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Count returns count Regions according to conditions in search params.
+//
+//zenrpc:search RegionSearch
+//zenrpc:return int
+//zenrpc:500 Internal Error
+func (s RegionService) Count(ctx context.Context, search *RegionSearch) (int, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.geoRepo.CountRegions(ctx, search.ToDB())
+	if err != nil {
+		return 0, InternalError(err)
+	}
+	return count, nil
+}
+
+// Get returns а list of Regions according to conditions in search params.
+//
+//zenrpc:search RegionSearch
+//zenrpc:viewOps ViewOps
+//zenrpc:return []RegionSummary
+//zenrpc:500 Internal Error
+func (s RegionService) Get(ctx context.Context, search *RegionSearch, viewOps *ViewOps) ([]RegionSummary, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.geoRepo.RegionsByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.geoRepo.FullRegion())
+	if err != nil {
+		return nil, InternalError(err)
+	}
+	regions := make([]RegionSummary, 0, len(list))
+	for i := 0; i < len(list); i++ {
+		if region := NewRegionSummary(&list[i]); region != nil {
+			regions = append(regions, *region)
+		}
+	}
+	return regions, nil
+}
+
+// GetByID returns a Region by its ID.
+//
+//zenrpc:id int
+//zenrpc:return Region
+//zenrpc:500 Internal Error
+//zenrpc:404 Not Found
+func (s RegionService) GetByID(ctx context.Context, id int) (*Region, error) {
+	if !s.auth.CanRead(ctx, "Region", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
+	db, err := s.byID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return NewRegion(db), nil
+}
+
+func (s RegionService) byID(ctx context.Context, id int) (*db.Region, error) {
+	db, err := s.geoRepo.RegionByID(ctx, id, s.geoRepo.FullRegion())
+	if err != nil {
+		return nil, InternalError(err)
+	} else if db == nil {
+		return nil, ErrNotFound
+	}
+	return db, nil
+}
+
+// History returns changes of the Region from audit log, newest first.
+//
+//zenrpc:id int
+//zenrpc:return []AuditLog
+//zenrpc:500 Internal Error
+func (s RegionService) History(ctx context.Context, id int) ([]AuditLog, error) {
+	if !s.auth.CanRead(ctx, "Region", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
+	list, err := s.auditRepo.AuditLogs(ctx, "Region", entityID(id), db.PagerNoLimit)
+	if err != nil {
+		return nil, InternalError(err)
+	}
+	auditLogs := make([]AuditLog, 0, len(list))
+	for i := range list {
+		if auditLog := NewAuditLog(&list[i]); auditLog != nil {
+			auditLogs = append(auditLogs, *auditLog)
+		}
+	}
+	return auditLogs, nil
+}
+
+// Export returns a csv or xlsx file with Regions according to conditions in search params.
+//
+//zenrpc:search RegionSearch
+//zenrpc:format file format: csv or xlsx
+//zenrpc:return ExportFile
+//zenrpc:500 Internal Error
+//zenrpc:400 Unsupported Format
+func (s RegionService) Export(ctx context.Context, search *RegionSearch, format string) (*ExportFile, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.geoRepo.RegionsByFilters(ctx, search.ToDB(), db.PagerNoLimit, s.dbSort(nil), s.geoRepo.FullRegion())
+	if err != nil {
+		return nil, InternalError(err)
+	}
+
+	header := []string{"id", "countryId", "title", "altTitle", "alias", "orderNumber", "image", "h1", "pageTitle", "metaDescription", "statusId"}
+	rows := make([][]string, 0, len(list))
+	for i := range list {
+		if region := NewRegion(&list[i]); region != nil {
+			rows = append(rows, []string{
+				exportValue(region.ID),
+				exportValue(region.CountryID),
+				exportValue(region.Title),
+				exportValue(region.AltTitle),
+				exportValue(region.Alias),
+				exportValue(region.OrderNumber),
+				exportValue(region.Image),
+				exportValue(region.H1),
+				exportValue(region.PageTitle),
+				exportValue(region.MetaDescription),
+				exportValue(region.StatusID),
+			})
+		}
+	}
+	return NewExportFile("regions", format, header, rows)
+}
+
+// Add adds a Region from the query.
+//
+//zenrpc:region Region
+//zenrpc:return Region
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+func (s RegionService) Add(ctx context.Context, region Region) (*Region, error) {
+	if !s.auth.CanWrite(ctx, "Region", "") {
+		return nil, ErrForbidden
+	}
+
+	if ve := s.isValid(ctx, region, false); ve.HasErrors() {
+		return nil, ve.Error()
+	}
+
+	db, err := s.geoRepo.AddRegion(ctx, region.ToDB())
+	if err != nil {
+		return nil, InternalError(err)
+	}
+
+	added := NewRegion(db)
+	s.audit(ctx, AuditActionAdd, nil, added)
+	return added, nil
+}
+
+// Update updates the Region data identified by id from the query.
+//
+//zenrpc:regions Region
+//zenrpc:return Region
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+//zenrpc:404 Not Found
+func (s RegionService) Update(ctx context.Context, region Region) (bool, error) {
+	if !s.auth.CanWrite(ctx, "Region", entityID(region.ID)) {
+		return false, ErrForbidden
+	}
+
+	old, err := s.byID(ctx, region.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if ve := s.isValid(ctx, region, true); ve.HasErrors() {
+		return false, ve.Error()
+	}
+
+	ok, err := s.geoRepo.UpdateRegion(ctx, region.ToDB())
+	if err != nil {
+		return false, InternalError(err)
+	}
+
+	if ok {
+		s.audit(ctx, AuditActionUpdate, NewRegion(old), &region)
+	}
+	return ok, nil
+}
+
+// Delete deletes the Region by its ID.
+//
+//zenrpc:id int
+//zenrpc:return isDeleted
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+//zenrpc:404 Not Found
+func (s RegionService) Delete(ctx context.Context, id int) (bool, error) {
+	if !s.auth.CanWrite(ctx, "Region", entityID(id)) {
+		return false, ErrForbidden
+	}
+
+	old, err := s.byID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := s.geoRepo.DeleteRegion(ctx, id)
+	if err != nil {
+		return false, InternalError(err)
+	}
+
+	if ok {
+		s.audit(ctx, AuditActionDelete, NewRegion(old), nil)
+	}
+	return ok, err
+}
+
+// Validate verifies that Region data is valid.
+//
+//zenrpc:region Region
+//zenrpc:return []FieldError
+//zenrpc:500 Internal Error
+func (s RegionService) Validate(ctx context.Context, region Region) ([]FieldError, error) {
+	isUpdate := region.ID != 0
+	id := ""
+	if isUpdate {
+		id = entityID(region.ID)
+	}
+	if !s.auth.CanWrite(ctx, "Region", id) {
+		return nil, ErrForbidden
+	}
+
+	if isUpdate {
+		_, err := s.byID(ctx, region.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ve := s.isValid(ctx, region, isUpdate)
+	if ve.HasInternalError() {
+		return nil, ve.Error()
+	}
+
+	return ve.Fields(), nil
+}
+
+func (s RegionService) isValid(ctx context.Context, region Region, isUpdate bool) Validator {
+	var v Validator
+
+	if v.CheckBasic(ctx, region); v.HasInternalError() {
+		return v
+	}
+
+	// check alias unique
+	search := &db.RegionSearch{
+		Alias: &region.Alias,
+		NotID: &region.ID,
+	}
+	item, err := s.geoRepo.OneRegion(ctx, search)
+	if err != nil {
+		v.SetInternalError(err)
+	} else if item != nil {
+		v.Append("alias", FieldErrorUnique)
+	}
+
+	// check fks
+	if region.CountryID != 0 {
+		item, err := s.geoRepo.CountryByID(ctx, region.CountryID)
+		if err != nil {
+			v.SetInternalError(err)
+		} else if item == nil {
+			v.Append("countryId", FieldErrorIncorrect)
+		}
+	}
+
+	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
+	return v
+}
+
+// audit writes changed fields of the Region to audit log.
+func (s RegionService) audit(ctx context.Context, action string, prev, next *Region) {
+	item := next
+	if item == nil {
+		item = prev
+	}
+
+	fields := []string{"id", "countryId", "title", "altTitle", "alias", "orderNumber", "image", "h1", "pageTitle", "metaDescription", "statusId"}
+	auditLog, err := newAuditLog(ctx, "Region", action, entityID(item.ID), fields, prev, next)
+	if err == nil {
+		_, err = s.auditRepo.AddAuditLog(ctx, auditLog)
+	}
+	if err != nil {
+		s.Error(ctx, "write audit log failed", "entity", "Region", "action", action, "err", err)
+	}
+}
+
+// GetCities returns Cities of the Region by its ID.
+//
+//zenrpc:id int
+//zenrpc:return []City
+//zenrpc:500 Internal Error
+//zenrpc:404 Not Found
+func (s RegionService) GetCities(ctx context.Context, id int) ([]City, error) {
+	if !s.auth.CanRead(ctx, "Region", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
+	if _, err := s.byID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	_, list, err := s.scopeCities(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]City, 0, len(list))
+	for i := range list {
+		if item := NewCity(&list[i]); item != nil {
+			items = append(items, *item)
+		}
+	}
+	return items, nil
+}
+
+// SaveCities replaces Cities of the Region: new items are added, existing are updated, missing are deleted.
+// Cities hidden from the current user by City authorizer scope are kept as is.
+// Primary keys of updated items should be unique and belong to Cities of the Region.
+//
+//zenrpc:id int
+//zenrpc:items []City
+//zenrpc:return []City
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+//zenrpc:404 Not Found
+func (s RegionService) SaveCities(ctx context.Context, id int, items []City) ([]City, error) {
+	if !s.auth.CanWrite(ctx, "Region", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
+	if _, err := s.byID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	cityService, list, err := s.scopeCities(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[int]*db.City, len(list))
+	for i := range list {
+		existing[list[i].ID] = &list[i]
+	}
+
+	// updated items should be unique children of the Region
+	var v Validator
+	updated := make(map[int]struct{}, len(items))
+	for i := range items {
+		childID := items[i].ID
+		if childID == 0 {
+			continue
+		}
+		if _, ok := existing[childID]; !ok {
+			v.Append("id", FieldErrorIncorrect)
+		} else if _, ok := updated[childID]; ok {
+			v.Append("id", FieldErrorUnique)
+		}
+		updated[childID] = struct{}{}
+	}
+	if v.HasErrors() {
+		return nil, v.Error()
+	}
+
+	// check access and validate all items before saving
+	for i := range items {
+		items[i].RegionID = id
+		isUpdate := items[i].ID != 0
+		childID := ""
+		if isUpdate {
+			childID = entityID(items[i].ID)
+		}
+		if !s.auth.CanWrite(ctx, "City", childID) {
+			return nil, ErrForbidden
+		}
+		if ve := cityService.isValid(ctx, items[i], isUpdate); ve.HasErrors() {
+			return nil, ve.Error()
+		}
+	}
+
+	// missing items are deleted
+	deleted := make(map[int]struct{}, len(existing))
+	for childID := range existing {
+		deleted[childID] = struct{}{}
+	}
+	for i := range items {
+		delete(deleted, items[i].ID)
+	}
+	for childID := range deleted {
+		if !s.auth.CanWrite(ctx, "City", entityID(childID)) {
+			return nil, ErrForbidden
+		}
+	}
+
+	var changes []auditChange[City]
+	err = s.dbo.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		repo := s.geoRepo.WithTransaction(tx)
+		for i := range items {
+			if old, ok := existing[items[i].ID]; ok {
+				if _, err := repo.UpdateCity(ctx, items[i].ToDB()); err != nil {
+					return err
+				}
+				changes = append(changes, auditChange[City]{action: AuditActionUpdate, prev: NewCity(old), next: &items[i]})
+				continue
+			}
+			added, err := repo.AddCity(ctx, items[i].ToDB())
+			if err != nil {
+				return err
+			}
+			changes = append(changes, auditChange[City]{action: AuditActionAdd, next: NewCity(added)})
+		}
+		for childID := range deleted {
+			if _, err := repo.DeleteCity(ctx, childID); err != nil {
+				return err
+			}
+			changes = append(changes, auditChange[City]{action: AuditActionDelete, prev: NewCity(existing[childID])})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, InternalError(err)
+	}
+
+	for _, change := range changes {
+		cityService.audit(ctx, change.action, change.prev, change.next)
+	}
+
+	return s.GetCities(ctx, id)
+}
+
+// scopeCities returns Cities of the Region available to the current user by City authorizer
+// and City service which uses authorizer of the Region service.
+func (s RegionService) scopeCities(ctx context.Context, id int) (*CityService, []db.City, error) {
+	cityService := NewCityService(s.dbo, s.Logger)
+	cityService.auth = s.auth
+
+	search, err := cityService.scope(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbSearch := search.ToDB()
+	dbSearch.RegionID = &id
+
+	list, err := s.geoRepo.CitiesByFilters(ctx, dbSearch, db.PagerNoLimit)
+	if err != nil {
+		return nil, nil, InternalError(err)
+	}
+	return cityService, list, nil
+}
+
+// Import adds or updates Regions from a csv or xlsx file, rows with filled primary key are updated.
+// Only columns present in the file are updated, other columns of existing Regions are kept.
+// The file is saved in a transaction if all rows are valid, otherwise nothing is saved and errors of rows are returned.
+//
+//zenrpc:file ImportFile
+//zenrpc:return ImportResult
+//zenrpc:500 Internal Error
+//zenrpc:400 Invalid File
+func (s RegionService) Import(ctx context.Context, file ImportFile) (*ImportResult, error) {
+	if !s.auth.CanWrite(ctx, "Region", "") {
+		return nil, ErrForbidden
+	}
+
+	rows, err := file.Rows()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	items := make([]Region, 0, len(rows))
+	olds := make([]*db.Region, 0, len(rows))
+	for i, row := range rows {
+		// first line in file is a header
+		line := i + 2
+
+		var region Region
+		if err := decodeRow(row, &region); err != nil {
+			result.Errors = append(result.Errors, ImportError{Row: line, Message: err.Error()})
+			continue
+		}
+
+		// old is nil for added rows
+		var old *db.Region
+		isUpdate := region.ID != 0
+		if isUpdate {
+			if !s.auth.CanWrite(ctx, "Region", entityID(region.ID)) {
+				result.Errors = append(result.Errors, ImportError{Row: line, Message: ErrForbidden.Error()})
+				continue
+			}
+			if old, err = s.byID(ctx, region.ID); errors.Is(err, ErrNotFound) {
+				result.Errors = append(result.Errors, ImportError{Row: line, Message: err.Error()})
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			// columns of the file overlay the existing row
+			region = *NewRegion(old)
+			if err := decodeRow(row, &region); err != nil {
+				result.Errors = append(result.Errors, ImportError{Row: line, Message: err.Error()})
+				continue
+			}
+		}
+
+		ve := s.isValid(ctx, region, isUpdate)
+		if ve.HasInternalError() {
+			return nil, ve.Error()
+		} else if ve.HasErrors() {
+			result.Errors = append(result.Errors, ImportError{Row: line, Fields: ve.Fields()})
+			continue
+		}
+
+		items = append(items, region)
+		olds = append(olds, old)
+	}
+
+	// file with invalid rows is not imported
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	var changes []auditChange[Region]
+	err = s.dbo.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		repo := s.geoRepo.WithTransaction(tx)
+		for i := range items {
+			if olds[i] != nil {
+				if _, err := repo.UpdateRegion(ctx, items[i].ToDB()); err != nil {
+					return err
+				}
+				changes = append(changes, auditChange[Region]{action: AuditActionUpdate, prev: NewRegion(olds[i]), next: &items[i]})
+				result.Updated++
+				continue
+			}
+			added, err := repo.AddRegion(ctx, items[i].ToDB())
+			if err != nil {
+				return err
+			}
+			changes = append(changes, auditChange[Region]{action: AuditActionAdd, next: NewRegion(added)})
+			result.Added++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, InternalError(err)
+	}
+
+	for _, change := range changes {
+		s.audit(ctx, change.action, change.prev, change.next)
+	}
+
+	return result, nil
+}
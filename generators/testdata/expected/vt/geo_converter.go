@@ -0,0 +1,136 @@
+package vt
+
+import (
+	"github.com/vmkteam/mfd-generator/generators/testdata/expected/db"
+)
+
+func NewCity(in *db.City) *City {
+	if in == nil {
+		return nil
+	}
+
+	city := &City{
+		ID:          in.ID,
+		RegionID:    in.RegionID,
+		CountryID:   in.CountryID,
+		Title:       in.Title,
+		AltTitle:    in.AltTitle,
+		Alias:       in.Alias,
+		OrderNumber: in.OrderNumber,
+		StatusID:    in.StatusID,
+
+		Region:  NewRegionSummary(in.Region),
+		Country: NewCountrySummary(in.Country),
+		Status:  NewStatus(in.StatusID),
+	}
+
+	return city
+}
+
+func NewCitySummary(in *db.City) *CitySummary {
+	if in == nil {
+		return nil
+	}
+
+	return &CitySummary{
+		ID:          in.ID,
+		RegionID:    in.RegionID,
+		CountryID:   in.CountryID,
+		Title:       in.Title,
+		AltTitle:    in.AltTitle,
+		Alias:       in.Alias,
+		OrderNumber: in.OrderNumber,
+
+		Region:  NewRegionSummary(in.Region),
+		Country: NewCountrySummary(in.Country),
+		Status:  NewStatus(in.StatusID),
+	}
+}
+
+func NewCountry(in *db.Country) *Country {
+	if in == nil {
+		return nil
+	}
+
+	country := &Country{
+		ID:              in.ID,
+		Title:           in.Title,
+		AltTitle:        in.AltTitle,
+		Alias:           in.Alias,
+		OrderNumber:     in.OrderNumber,
+		H1:              in.H1,
+		PageTitle:       in.PageTitle,
+		MetaDescription: in.MetaDescription,
+		StatusID:        in.StatusID,
+
+		Status: NewStatus(in.StatusID),
+	}
+
+	return country
+}
+
+func NewCountrySummary(in *db.Country) *CountrySummary {
+	if in == nil {
+		return nil
+	}
+
+	return &CountrySummary{
+		ID:              in.ID,
+		Title:           in.Title,
+		AltTitle:        in.AltTitle,
+		Alias:           in.Alias,
+		OrderNumber:     in.OrderNumber,
+		H1:              in.H1,
+		PageTitle:       in.PageTitle,
+		MetaDescription: in.MetaDescription,
+
+		Status: NewStatus(in.StatusID),
+	}
+}
+
+func NewRegion(in *db.Region) *Region {
+	if in == nil {
+		return nil
+	}
+
+	region := &Region{
+		ID:              in.ID,
+		CountryID:       in.CountryID,
+		Title:           in.Title,
+		AltTitle:        in.AltTitle,
+		Alias:           in.Alias,
+		OrderNumber:     in.OrderNumber,
+		Image:           in.Image,
+		H1:              in.H1,
+		PageTitle:       in.PageTitle,
+		MetaDescription: in.MetaDescription,
+		StatusID:        in.StatusID,
+
+		Country: NewCountrySummary(in.Country),
+		Status:  NewStatus(in.StatusID),
+	}
+
+	return region
+}
+
+func NewRegionSummary(in *db.Region) *RegionSummary {
+	if in == nil {
+		return nil
+	}
+
+	return &RegionSummary{
+		ID:              in.ID,
+		CountryID:       in.CountryID,
+		Title:           in.Title,
+		AltTitle:        in.AltTitle,
+		Alias:           in.Alias,
+		OrderNumber:     in.OrderNumber,
+		Image:           in.Image,
+		H1:              in.H1,
+		PageTitle:       in.PageTitle,
+		MetaDescription: in.MetaDescription,
+
+		Country: NewCountrySummary(in.Country),
+		Status:  NewStatus(in.StatusID),
+	}
+}
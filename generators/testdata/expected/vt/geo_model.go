@@ -0,0 +1,260 @@
+//nolint:dupl
+package vt
+
+import (
+	"github.com/vmkteam/mfd-generator/generators/testdata/expected/db"
+)
+
+type City struct {
+	ID          int     `json:"id"`
+	RegionID    int     `json:"regionId" validate:"required"`
+	CountryID   int     `json:"countryId" validate:"required"`
+	Title       string  `json:"title" validate:"required,max=255"`
+	AltTitle    *string `json:"altTitle" validate:"omitempty,max=255"`
+	Alias       string  `json:"alias" validate:"required,alias,max=255"`
+	OrderNumber int     `json:"orderNumber" validate:"required"`
+	StatusID    int     `json:"statusId" validate:"required,status"`
+
+	Region  *RegionSummary  `json:"region"`
+	Country *CountrySummary `json:"country"`
+	Status  *Status         `json:"status"`
+}
+
+func (c *City) ToDB() *db.City {
+	if c == nil {
+		return nil
+	}
+
+	city := &db.City{
+		ID:          c.ID,
+		RegionID:    c.RegionID,
+		CountryID:   c.CountryID,
+		Title:       c.Title,
+		AltTitle:    c.AltTitle,
+		Alias:       c.Alias,
+		OrderNumber: c.OrderNumber,
+		StatusID:    c.StatusID,
+	}
+
+	return city
+}
+
+type CitySearch struct {
+	ID          *int    `json:"id"`
+	RegionID    *int    `json:"regionId"`
+	CountryID   *int    `json:"countryId"`
+	Title       *string `json:"title"`
+	AltTitle    *string `json:"altTitle"`
+	Alias       *string `json:"alias"`
+	OrderNumber *int    `json:"orderNumber"`
+	StatusID    *int    `json:"statusId"`
+	IDs         []int   `json:"ids"`
+	NotID       *int    `json:"notId"`
+}
+
+func (cs *CitySearch) ToDB() *db.CitySearch {
+	if cs == nil {
+		return nil
+	}
+
+	return &db.CitySearch{
+		ID:            cs.ID,
+		RegionID:      cs.RegionID,
+		CountryID:     cs.CountryID,
+		TitleILike:    cs.Title,
+		AltTitleILike: cs.AltTitle,
+		Alias:         cs.Alias,
+		OrderNumber:   cs.OrderNumber,
+		StatusID:      cs.StatusID,
+		IDs:           cs.IDs,
+		NotID:         cs.NotID,
+	}
+}
+
+type CitySummary struct {
+	ID          int     `json:"id"`
+	RegionID    int     `json:"regionId"`
+	CountryID   int     `json:"countryId"`
+	Title       string  `json:"title"`
+	AltTitle    *string `json:"altTitle"`
+	Alias       string  `json:"alias"`
+	OrderNumber int     `json:"orderNumber"`
+
+	Region  *RegionSummary  `json:"region"`
+	Country *CountrySummary `json:"country"`
+	Status  *Status         `json:"status"`
+}
+
+type Country struct {
+	ID              int     `json:"id"`
+	Title           string  `json:"title" validate:"required,max=255"`
+	AltTitle        *string `json:"altTitle" validate:"omitempty,max=255"`
+	Alias           string  `json:"alias" validate:"required,alias,max=255"`
+	OrderNumber     int     `json:"orderNumber" validate:"required"`
+	H1              *string `json:"h1" validate:"omitempty,max=500"`
+	PageTitle       *string `json:"pageTitle" validate:"omitempty,max=500"`
+	MetaDescription *string `json:"metaDescription" validate:"omitempty,max=1000"`
+	StatusID        int     `json:"statusId" validate:"required,status"`
+
+	Status *Status `json:"status"`
+}
+
+func (c *Country) ToDB() *db.Country {
+	if c == nil {
+		return nil
+	}
+
+	country := &db.Country{
+		ID:              c.ID,
+		Title:           c.Title,
+		AltTitle:        c.AltTitle,
+		Alias:           c.Alias,
+		OrderNumber:     c.OrderNumber,
+		H1:              c.H1,
+		PageTitle:       c.PageTitle,
+		MetaDescription: c.MetaDescription,
+		StatusID:        c.StatusID,
+	}
+
+	return country
+}
+
+type CountrySearch struct {
+	ID              *int    `json:"id"`
+	Title           *string `json:"title"`
+	AltTitle        *string `json:"altTitle"`
+	Alias           *string `json:"alias"`
+	OrderNumber     *int    `json:"orderNumber"`
+	H1              *string `json:"h1"`
+	PageTitle       *string `json:"pageTitle"`
+	MetaDescription *string `json:"metaDescription"`
+	StatusID        *int    `json:"statusId"`
+	IDs             []int   `json:"ids"`
+	NotID           *int    `json:"notId"`
+}
+
+func (cs *CountrySearch) ToDB() *db.CountrySearch {
+	if cs == nil {
+		return nil
+	}
+
+	return &db.CountrySearch{
+		ID:                   cs.ID,
+		TitleILike:           cs.Title,
+		AltTitleILike:        cs.AltTitle,
+		Alias:                cs.Alias,
+		OrderNumber:          cs.OrderNumber,
+		H1ILike:              cs.H1,
+		PageTitleILike:       cs.PageTitle,
+		MetaDescriptionILike: cs.MetaDescription,
+		StatusID:             cs.StatusID,
+		IDs:                  cs.IDs,
+		NotID:                cs.NotID,
+	}
+}
+
+type CountrySummary struct {
+	ID              int     `json:"id"`
+	Title           string  `json:"title"`
+	AltTitle        *string `json:"altTitle"`
+	Alias           string  `json:"alias"`
+	OrderNumber     int     `json:"orderNumber"`
+	H1              *string `json:"h1"`
+	PageTitle       *string `json:"pageTitle"`
+	MetaDescription *string `json:"metaDescription"`
+
+	Status *Status `json:"status"`
+}
+
+type Region struct {
+	ID              int     `json:"id"`
+	CountryID       int     `json:"countryId" validate:"required"`
+	Title           string  `json:"title" validate:"required,max=255"`
+	AltTitle        *string `json:"altTitle" validate:"omitempty,max=255"`
+	Alias           string  `json:"alias" validate:"required,alias,max=255"`
+	OrderNumber     int     `json:"orderNumber" validate:"required"`
+	Image           *string `json:"image" validate:"omitempty,max=32"`
+	H1              *string `json:"h1" validate:"omitempty,max=500"`
+	PageTitle       *string `json:"pageTitle" validate:"omitempty,max=500"`
+	MetaDescription *string `json:"metaDescription" validate:"omitempty,max=1000"`
+	StatusID        int     `json:"statusId" validate:"required,status"`
+
+	Country *CountrySummary `json:"country"`
+	Status  *Status         `json:"status"`
+}
+
+func (r *Region) ToDB() *db.Region {
+	if r == nil {
+		return nil
+	}
+
+	region := &db.Region{
+		ID:              r.ID,
+		CountryID:       r.CountryID,
+		Title:           r.Title,
+		AltTitle:        r.AltTitle,
+		Alias:           r.Alias,
+		OrderNumber:     r.OrderNumber,
+		Image:           r.Image,
+		H1:              r.H1,
+		PageTitle:       r.PageTitle,
+		MetaDescription: r.MetaDescription,
+		StatusID:        r.StatusID,
+	}
+
+	return region
+}
+
+type RegionSearch struct {
+	ID              *int    `json:"id"`
+	CountryID       *int    `json:"countryId"`
+	Title           *string `json:"title"`
+	AltTitle        *string `json:"altTitle"`
+	Alias           *string `json:"alias"`
+	OrderNumber     *int    `json:"orderNumber"`
+	Image           *string `json:"image"`
+	H1              *string `json:"h1"`
+	PageTitle       *string `json:"pageTitle"`
+	MetaDescription *string `json:"metaDescription"`
+	StatusID        *int    `json:"statusId"`
+	IDs             []int   `json:"ids"`
+	NotID           *int    `json:"notId"`
+}
+
+func (rs *RegionSearch) ToDB() *db.RegionSearch {
+	if rs == nil {
+		return nil
+	}
+
+	return &db.RegionSearch{
+		ID:                   rs.ID,
+		CountryID:            rs.CountryID,
+		TitleILike:           rs.Title,
+		AltTitleILike:        rs.AltTitle,
+		Alias:                rs.Alias,
+		OrderNumber:          rs.OrderNumber,
+		ImageILike:           rs.Image,
+		H1ILike:              rs.H1,
+		PageTitleILike:       rs.PageTitle,
+		MetaDescriptionILike: rs.MetaDescription,
+		StatusID:             rs.StatusID,
+		IDs:                  rs.IDs,
+		NotID:                rs.NotID,
+	}
+}
+
+type RegionSummary struct {
+	ID              int     `json:"id"`
+	CountryID       int     `json:"countryId"`
+	Title           string  `json:"title"`
+	AltTitle        *string `json:"altTitle"`
+	Alias           string  `json:"alias"`
+	OrderNumber     int     `json:"orderNumber"`
+	Image           *string `json:"image"`
+	H1              *string `json:"h1"`
+	PageTitle       *string `json:"pageTitle"`
+	MetaDescription *string `json:"metaDescription"`
+
+	Country *CountrySummary `json:"country"`
+	Status  *Status         `json:"status"`
+}
@@ -13,12 +13,14 @@ type CategoryService struct {
 	zenrpc.Service
 	embedlog.Logger
 	portalRepo db.PortalRepo
+	auth       Authorizer
 }
 
 func NewCategoryService(dbo db.DB, logger embedlog.Logger) *CategoryService {
 	return &CategoryService{
 		Logger:     logger,
 		portalRepo: db.NewPortalRepo(dbo),
+		auth:       DefaultAuthorizer,
 	}
 }
 
@@ -36,12 +38,31 @@ func (s CategoryService) dbSort(ops *ViewOps) db.OpFunc {
 	return v
 }
 
+// scope checks read access to Categories and applies authorizer filters to search params.
+func (s CategoryService) scope(ctx context.Context, search *CategorySearch) (*CategorySearch, error) {
+	if !s.auth.CanRead(ctx, "Category", "") {
+		return nil, ErrForbidden
+	}
+	if search == nil {
+		search = &CategorySearch{}
+	}
+	if err := s.auth.ScopeSearch(ctx, "Category", search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
 // Count returns count Categories according to conditions in search params.
 //
 //zenrpc:search CategorySearch
 //zenrpc:return int
 //zenrpc:500 Internal Error
 func (s CategoryService) Count(ctx context.Context, search *CategorySearch) (int, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+
 	count, err := s.portalRepo.CountCategories(ctx, search.ToDB())
 	if err != nil {
 		return 0, InternalError(err)
@@ -56,6 +77,11 @@ func (s CategoryService) Count(ctx context.Context, search *CategorySearch) (int
 //zenrpc:return []CategorySummary
 //zenrpc:500 Internal Error
 func (s CategoryService) Get(ctx context.Context, search *CategorySearch, viewOps *ViewOps) ([]CategorySummary, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
 	list, err := s.portalRepo.CategoriesByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.portalRepo.FullCategory())
 	if err != nil {
 		return nil, InternalError(err)
@@ -76,6 +102,10 @@ func (s CategoryService) Get(ctx context.Context, search *CategorySearch, viewOp
 //zenrpc:500 Internal Error
 //zenrpc:404 Not Found
 func (s CategoryService) GetByID(ctx context.Context, id int) (*Category, error) {
+	if !s.auth.CanRead(ctx, "Category", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
 	db, err := s.byID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -100,6 +130,10 @@ func (s CategoryService) byID(ctx context.Context, id int) (*db.Category, error)
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 func (s CategoryService) Add(ctx context.Context, category Category) (*Category, error) {
+	if !s.auth.CanWrite(ctx, "Category", "") {
+		return nil, ErrForbidden
+	}
+
 	if ve := s.isValid(ctx, category, false); ve.HasErrors() {
 		return nil, ve.Error()
 	}
@@ -119,6 +153,10 @@ func (s CategoryService) Add(ctx context.Context, category Category) (*Category,
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s CategoryService) Update(ctx context.Context, category Category) (bool, error) {
+	if !s.auth.CanWrite(ctx, "Category", entityID(category.ID)) {
+		return false, ErrForbidden
+	}
+
 	if _, err := s.byID(ctx, category.ID); err != nil {
 		return false, err
 	}
@@ -142,6 +180,10 @@ func (s CategoryService) Update(ctx context.Context, category Category) (bool, e
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s CategoryService) Delete(ctx context.Context, id int) (bool, error) {
+	if !s.auth.CanWrite(ctx, "Category", entityID(id)) {
+		return false, ErrForbidden
+	}
+
 	if _, err := s.byID(ctx, id); err != nil {
 		return false, err
 	}
@@ -160,6 +202,14 @@ func (s CategoryService) Delete(ctx context.Context, id int) (bool, error) {
 //zenrpc:500 Internal Error
 func (s CategoryService) Validate(ctx context.Context, category Category) ([]FieldError, error) {
 	isUpdate := category.ID != 0
+	id := ""
+	if isUpdate {
+		id = entityID(category.ID)
+	}
+	if !s.auth.CanWrite(ctx, "Category", id) {
+		return nil, ErrForbidden
+	}
+
 	if isUpdate {
 		_, err := s.byID(ctx, category.ID)
 		if err != nil {
@@ -183,6 +233,8 @@ func (s CategoryService) isValid(ctx context.Context, category Category, isUpdat
 	}
 
 	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
 	return v
 }
 
@@ -191,6 +243,7 @@ type NewsService struct {
 	embedlog.Logger
 	portalRepo db.PortalRepo
 	geoRepo    db.GeoRepo
+	auth       Authorizer
 }
 
 func NewNewsService(dbo db.DB, logger embedlog.Logger) *NewsService {
@@ -198,6 +251,7 @@ func NewNewsService(dbo db.DB, logger embedlog.Logger) *NewsService {
 		Logger:     logger,
 		portalRepo: db.NewPortalRepo(dbo),
 		geoRepo:    db.NewGeoRepo(dbo),
+		auth:       DefaultAuthorizer,
 	}
 }
 
@@ -215,12 +269,33 @@ func (s NewsService) dbSort(ops *ViewOps) db.OpFunc {
 	return v
 }
 
+// scope checks read access to News and applies authorizer filters to search params.
+func (s NewsService) scope(ctx context.Context, search *NewsSearch) (*NewsSearch, error) {
+	if !s.auth.CanRead(ctx, "News", "") {
+		return nil, ErrForbidden
+	}
+	if search == nil {
+		search = &NewsSearch{}
+	}
+	if err := s.auth.ScopeSearch(ctx, "News", search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
 // Count returns count News according to conditions in search params.
 //
+// Новости портала
+//
 //zenrpc:search NewsSearch
 //zenrpc:return int
 //zenrpc:500 Internal Error
 func (s NewsService) Count(ctx context.Context, search *NewsSearch) (int, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+
 	count, err := s.portalRepo.CountNews(ctx, search.ToDB())
 	if err != nil {
 		return 0, InternalError(err)
@@ -230,11 +305,18 @@ func (s NewsService) Count(ctx context.Context, search *NewsSearch) (int, error)
 
 // Get returns а list of News according to conditions in search params.
 //
+// Новости портала
+//
 //zenrpc:search NewsSearch
 //zenrpc:viewOps ViewOps
 //zenrpc:return []NewsSummary
 //zenrpc:500 Internal Error
 func (s NewsService) Get(ctx context.Context, search *NewsSearch, viewOps *ViewOps) ([]NewsSummary, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
 	list, err := s.portalRepo.NewsByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.portalRepo.FullNews())
 	if err != nil {
 		return nil, InternalError(err)
@@ -250,11 +332,17 @@ func (s NewsService) Get(ctx context.Context, search *NewsSearch, viewOps *ViewO
 
 // GetByID returns a News by its ID.
 //
+// Новости портала
+//
 //zenrpc:id int
 //zenrpc:return News
 //zenrpc:500 Internal Error
 //zenrpc:404 Not Found
 func (s NewsService) GetByID(ctx context.Context, id int) (*News, error) {
+	if !s.auth.CanRead(ctx, "News", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
 	db, err := s.byID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -274,11 +362,17 @@ func (s NewsService) byID(ctx context.Context, id int) (*db.News, error) {
 
 // Add adds a News from the query.
 //
+// Новости портала
+//
 //zenrpc:news News
 //zenrpc:return News
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 func (s NewsService) Add(ctx context.Context, news News) (*News, error) {
+	if !s.auth.CanWrite(ctx, "News", "") {
+		return nil, ErrForbidden
+	}
+
 	if ve := s.isValid(ctx, news, false); ve.HasErrors() {
 		return nil, ve.Error()
 	}
@@ -292,12 +386,18 @@ func (s NewsService) Add(ctx context.Context, news News) (*News, error) {
 
 // Update updates the News data identified by id from the query.
 //
+// Новости портала
+//
 //zenrpc:newsList News
 //zenrpc:return News
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s NewsService) Update(ctx context.Context, news News) (bool, error) {
+	if !s.auth.CanWrite(ctx, "News", entityID(news.ID)) {
+		return false, ErrForbidden
+	}
+
 	if _, err := s.byID(ctx, news.ID); err != nil {
 		return false, err
 	}
@@ -315,12 +415,18 @@ func (s NewsService) Update(ctx context.Context, news News) (bool, error) {
 
 // Delete deletes the News by its ID.
 //
+// Новости портала
+//
 //zenrpc:id int
 //zenrpc:return isDeleted
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s NewsService) Delete(ctx context.Context, id int) (bool, error) {
+	if !s.auth.CanWrite(ctx, "News", entityID(id)) {
+		return false, ErrForbidden
+	}
+
 	if _, err := s.byID(ctx, id); err != nil {
 		return false, err
 	}
@@ -334,11 +440,21 @@ func (s NewsService) Delete(ctx context.Context, id int) (bool, error) {
 
 // Validate verifies that News data is valid.
 //
+// Новости портала
+//
 //zenrpc:news News
 //zenrpc:return []FieldError
 //zenrpc:500 Internal Error
 func (s NewsService) Validate(ctx context.Context, news News) ([]FieldError, error) {
 	isUpdate := news.ID != 0
+	id := ""
+	if isUpdate {
+		id = entityID(news.ID)
+	}
+	if !s.auth.CanWrite(ctx, "News", id) {
+		return nil, ErrForbidden
+	}
+
 	if isUpdate {
 		_, err := s.byID(ctx, news.ID)
 		if err != nil {
@@ -407,6 +523,8 @@ func (s NewsService) isValid(ctx context.Context, news News, isUpdate bool) Vali
 		}
 	}
 	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
 	return v
 }
 
@@ -414,12 +532,14 @@ type TagService struct {
 	zenrpc.Service
 	embedlog.Logger
 	portalRepo db.PortalRepo
+	auth       Authorizer
 }
 
 func NewTagService(dbo db.DB, logger embedlog.Logger) *TagService {
 	return &TagService{
 		Logger:     logger,
 		portalRepo: db.NewPortalRepo(dbo),
+		auth:       DefaultAuthorizer,
 	}
 }
 
@@ -437,12 +557,31 @@ func (s TagService) dbSort(ops *ViewOps) db.OpFunc {
 	return v
 }
 
+// scope checks read access to Tags and applies authorizer filters to search params.
+func (s TagService) scope(ctx context.Context, search *TagSearch) (*TagSearch, error) {
+	if !s.auth.CanRead(ctx, "Tag", "") {
+		return nil, ErrForbidden
+	}
+	if search == nil {
+		search = &TagSearch{}
+	}
+	if err := s.auth.ScopeSearch(ctx, "Tag", search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
 // Count returns count Tags according to conditions in search params.
 //
 //zenrpc:search TagSearch
 //zenrpc:return int
 //zenrpc:500 Internal Error
 func (s TagService) Count(ctx context.Context, search *TagSearch) (int, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+
 	count, err := s.portalRepo.CountTags(ctx, search.ToDB())
 	if err != nil {
 		return 0, InternalError(err)
@@ -457,6 +596,11 @@ func (s TagService) Count(ctx context.Context, search *TagSearch) (int, error) {
 //zenrpc:return []TagSummary
 //zenrpc:500 Internal Error
 func (s TagService) Get(ctx context.Context, search *TagSearch, viewOps *ViewOps) ([]TagSummary, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
 	list, err := s.portalRepo.TagsByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.portalRepo.FullTag())
 	if err != nil {
 		return nil, InternalError(err)
@@ -477,6 +621,10 @@ func (s TagService) Get(ctx context.Context, search *TagSearch, viewOps *ViewOps
 //zenrpc:500 Internal Error
 //zenrpc:404 Not Found
 func (s TagService) GetByID(ctx context.Context, id int) (*Tag, error) {
+	if !s.auth.CanRead(ctx, "Tag", entityID(id)) {
+		return nil, ErrForbidden
+	}
+
 	db, err := s.byID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -501,6 +649,10 @@ func (s TagService) byID(ctx context.Context, id int) (*db.Tag, error) {
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 func (s TagService) Add(ctx context.Context, tag Tag) (*Tag, error) {
+	if !s.auth.CanWrite(ctx, "Tag", "") {
+		return nil, ErrForbidden
+	}
+
 	if ve := s.isValid(ctx, tag, false); ve.HasErrors() {
 		return nil, ve.Error()
 	}
@@ -520,6 +672,10 @@ func (s TagService) Add(ctx context.Context, tag Tag) (*Tag, error) {
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s TagService) Update(ctx context.Context, tag Tag) (bool, error) {
+	if !s.auth.CanWrite(ctx, "Tag", entityID(tag.ID)) {
+		return false, ErrForbidden
+	}
+
 	if _, err := s.byID(ctx, tag.ID); err != nil {
 		return false, err
 	}
@@ -543,6 +699,10 @@ func (s TagService) Update(ctx context.Context, tag Tag) (bool, error) {
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s TagService) Delete(ctx context.Context, id int) (bool, error) {
+	if !s.auth.CanWrite(ctx, "Tag", entityID(id)) {
+		return false, ErrForbidden
+	}
+
 	if _, err := s.byID(ctx, id); err != nil {
 		return false, err
 	}
@@ -561,6 +721,14 @@ func (s TagService) Delete(ctx context.Context, id int) (bool, error) {
 //zenrpc:500 Internal Error
 func (s TagService) Validate(ctx context.Context, tag Tag) ([]FieldError, error) {
 	isUpdate := tag.ID != 0
+	id := ""
+	if isUpdate {
+		id = entityID(tag.ID)
+	}
+	if !s.auth.CanWrite(ctx, "Tag", id) {
+		return nil, ErrForbidden
+	}
+
 	if isUpdate {
 		_, err := s.byID(ctx, tag.ID)
 		if err != nil {
@@ -584,5 +752,7 @@ func (s TagService) isValid(ctx context.Context, tag Tag, isUpdate bool) Validat
 	}
 
 	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
 	return v
 }
@@ -2,9 +2,13 @@ package vttmpl
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"html/template"
+	"maps"
 	"path"
+	"slices"
 
 	"github.com/vmkteam/mfd-generator/mfd"
 
@@ -16,11 +20,13 @@ const (
 	mfdFlag      = "mfd"
 	nsFlag       = "namespaces"
 	entitiesFlag = "entities"
+	jobsFlag     = "jobs"
 
 	routesTemplateFlag = "routes-tmpl"
 	listTemplateFlag   = "list-tmpl"
 	filterTemplateFlag = "filter-tmpl"
 	formTemplateFlag   = "form-tmpl"
+	templatesDirFlag   = "templates-dir"
 )
 
 // CreateCommand creates generator command
@@ -28,14 +34,47 @@ func CreateCommand() *cobra.Command {
 	return base.CreateCommand("template", "Create vt template from xml", New())
 }
 
+// Templates returns built-in templates which could be exported to templates dir and customized
+func Templates() []mfd.BuiltinTemplate {
+	return []mfd.BuiltinTemplate{
+		{Name: "routes", Source: routesDefaultTemplate},
+		{Name: "list", Source: listDefaultTemplate},
+		{Name: "filter", Source: filterDefaultTemplate},
+		{Name: "form", Source: formDefaultTemplate},
+		{Name: "children", Source: childTableTemplate},
+	}
+}
+
 // Generator represents mfd generator
 type Generator struct {
 	options Options
+	files   *mfd.Files
+
+	// templates are custom templates from templates dir
+	templates *mfd.Templates
 }
 
 // New creates basic generator
 func New() *Generator {
-	return &Generator{}
+	return &Generator{files: mfd.NewFiles(nil)}
+}
+
+// Runner runs vt template generator without cobra command
+type Runner = mfd.Runner[Options]
+
+// NewGenerator creates vt template generator for usage as a library
+func NewGenerator(options Options) *Runner {
+	return mfd.NewRunner(options, nil, run)
+}
+
+// run runs generator with files if required options are set
+func run(ctx context.Context, options Options, files *mfd.Files) error {
+	if options.MFDPath == "" || options.Output == "" {
+		return errors.New("mfd file and output dir are required")
+	}
+
+	g := &Generator{options: options, files: files}
+	return g.generate(ctx)
 }
 
 // AddFlags adds flags to command
@@ -55,11 +94,13 @@ func (g *Generator) AddFlags(command *cobra.Command) {
 
 	flags.StringSliceP(nsFlag, "n", []string{}, "namespaces to generate. separate by comma\n")
 	flags.StringSliceP(entitiesFlag, "e", []string{}, "entities to generate, must be in vt.xml file. separate by comma")
+	flags.IntP(jobsFlag, "j", 0, "count of entities generated concurrently. if not set - count of CPUs will be used\n")
 
 	flags.String(routesTemplateFlag, "", "path to routes custom template")
 	flags.String(listTemplateFlag, "", "path to list custom template")
 	flags.String(filterTemplateFlag, "", "path to filter custom template")
-	flags.String(formTemplateFlag, "", "path to form custom template\n")
+	flags.String(formTemplateFlag, "", "path to form custom template")
+	flags.String(templatesDirFlag, "", "path to custom templates dir, see templates export command\n")
 }
 
 // ReadFlags read flags from command
@@ -84,6 +125,10 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 		return err
 	}
 
+	if g.options.Jobs, err = flags.GetInt(jobsFlag); err != nil {
+		return err
+	}
+
 	if g.options.RoutesTemplatePath, err = flags.GetString(routesTemplateFlag); err != nil {
 		return err
 	}
@@ -93,7 +138,10 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 	if g.options.FiltersTemplatePath, err = flags.GetString(filterTemplateFlag); err != nil {
 		return err
 	}
-	if g.options.FiltersTemplatePath, err = flags.GetString(formTemplateFlag); err != nil {
+	if g.options.FormTemplatePath, err = flags.GetString(formTemplateFlag); err != nil {
+		return err
+	}
+	if g.options.TemplatesDir, err = flags.GetString(templatesDirFlag); err != nil {
 		return err
 	}
 
@@ -101,9 +149,12 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 }
 
 // Generate runs generator
-//
-//nolint:gocognit // the func is not as complicated as the linter says
 func (g *Generator) Generate() error {
+	return g.generate(context.Background())
+}
+
+//nolint:gocognit // the func is not as complicated as the linter says
+func (g *Generator) generate(ctx context.Context) error {
 	// loading project from file
 	project, err := mfd.LoadProject(g.options.MFDPath, false, 0)
 	if err != nil {
@@ -120,26 +171,35 @@ func (g *Generator) Generate() error {
 	}
 
 	// loading templates
-	routesTemplate, err := mfd.LoadTemplate(g.options.RoutesTemplatePath, routesDefaultTemplate)
+	if g.templates, err = mfd.LoadTemplates(g.options.TemplatesDir, "template"); err != nil {
+		return fmt.Errorf("load templates, err=%w", err)
+	}
+
+	routesTemplate, err := g.templates.Template("routes", g.options.RoutesTemplatePath, routesDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load routes template, err=%w", err)
 	}
 
-	listTemplate, err := mfd.LoadTemplate(g.options.ListTemplatePath, listDefaultTemplate)
+	listTemplate, err := g.templates.Template("list", g.options.ListTemplatePath, listDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load list template, err=%w", err)
 	}
 
-	filterTemplate, err := mfd.LoadTemplate(g.options.FiltersTemplatePath, filterDefaultTemplate)
+	filterTemplate, err := g.templates.Template("filter", g.options.FiltersTemplatePath, filterDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load filter template, err=%w", err)
 	}
 
-	formTemplate, err := mfd.LoadTemplate(g.options.ListTemplatePath, formDefaultTemplate)
+	formTemplate, err := g.templates.Template("form", g.options.FormTemplatePath, formDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load form template, err=%w", err)
 	}
 
+	childrenTemplate, err := g.templates.Template("children", "", childTableTemplate)
+	if err != nil {
+		return fmt.Errorf("load children template, err=%w", err)
+	}
+
 	// generating routes for all namespaces
 	if _, err := g.SaveRoutes(project.VTNamespaces, routesTemplate); err != nil {
 		return fmt.Errorf("generate routes, err=%w", err)
@@ -150,6 +210,10 @@ func (g *Generator) Generate() error {
 		return fmt.Errorf("read translation, err=%w", err)
 	}
 
+	// translations are saved in order of languages
+	languages := slices.Sorted(maps.Keys(translations))
+
+	var tasks []mfd.Task
 	for _, namespace := range g.options.Namespaces {
 		ns := project.VTNamespace(namespace)
 		if ns == nil {
@@ -172,35 +236,71 @@ func (g *Generator) Generate() error {
 				continue
 			}
 
-			if err := g.SaveEntity(*entity, "List.vue", listTemplate); err != nil {
-				return fmt.Errorf("generate entity %s list, err=%w", entity.Name, err)
-			}
+			tasks = append(tasks, func(files *mfd.Files) error {
+				g := g.withFiles(files)
 
-			if err := g.SaveEntity(*entity, "components/MultiListFilters.vue", filterTemplate); err != nil {
-				return fmt.Errorf("generate entity %s filters, err=%w", entity.Name, err)
-			}
+				if err := g.SaveEntity(*entity, ns, "List.vue", listTemplate); err != nil {
+					return fmt.Errorf("generate entity %s list, err=%w", entity.Name, err)
+				}
 
-			// do not generate form on
-			if entity.Mode != mfd.ModeReadOnlyWithTemplates {
-				if err := g.SaveEntity(*entity, "Form.vue", formTemplate); err != nil {
-					return fmt.Errorf("generate entity %s form, err=%w", entity.Name, err)
+				if err := g.SaveEntity(*entity, ns, "components/MultiListFilters.vue", filterTemplate); err != nil {
+					return fmt.Errorf("generate entity %s filters, err=%w", entity.Name, err)
 				}
-			}
 
-			// saving translations
-			for lang, translation := range translations {
-				if err := g.SaveLang(translation.Entity(ns.Name, entity.Name), lang); err != nil {
-					return fmt.Errorf("save translation lang %s, err=%w", lang, err)
+				// do not generate form on
+				if entity.Mode != mfd.ModeReadOnlyWithTemplates {
+					if err := g.SaveEntity(*entity, ns, "Form.vue", formTemplate); err != nil {
+						return fmt.Errorf("generate entity %s form, err=%w", entity.Name, err)
+					}
+
+					// inline tables for children edited in form
+					for _, child := range PackChildren(*entity, ns) {
+						output := path.Join("components", child.Name+"Table.vue")
+						if err := g.save(child, path.Join(entity.Name, output), childrenTemplate); err != nil {
+							return fmt.Errorf("generate entity %s children %s, err=%w", entity.Name, child.Name, err)
+						}
+					}
 				}
-			}
+
+				// saving translations
+				for _, lang := range languages {
+					translation := translations[lang]
+					if err := g.SaveLang(translation.Entity(ns.Name, entity.Name), lang); err != nil {
+						return fmt.Errorf("save translation lang %s, err=%w", lang, err)
+					}
+				}
+
+				return nil
+			})
 		}
 	}
 
-	return mfd.SaveMFD(g.options.MFDPath, project)
+	if err := g.files.Parallel(ctx, g.options.Jobs, tasks); err != nil {
+		return err
+	}
+
+	return g.files.SaveMFD(g.options.MFDPath, project)
+}
+
+// withFiles returns copy of generator which writes files to files
+func (g *Generator) withFiles(files *mfd.Files) *Generator {
+	clone := *g
+	clone.files = files
+
+	return &clone
 }
 
 // SaveEntity saves vt entity to template with special delims
-func (g *Generator) SaveEntity(entity mfd.VTEntity, output, tmpl string) error {
+func (g *Generator) SaveEntity(entity mfd.VTEntity, namespace *mfd.VTNamespace, output, tmpl string) error {
+	packed := PackEntity(entity)
+	packed.Children = PackChildren(entity, namespace)
+	packed.HasChildren = len(packed.Children) > 0
+
+	return g.save(packed, path.Join(entity.Name, output), tmpl)
+}
+
+// save saves data to template with special delims
+func (g *Generator) save(data interface{}, output, tmpl string) error {
 	parsed, err := template.New("base").
 		Delims("[[", "]]").
 		Funcs(mfd.TemplateFunctions).
@@ -209,14 +309,19 @@ func (g *Generator) SaveEntity(entity mfd.VTEntity, output, tmpl string) error {
 		return fmt.Errorf("parsing template, err=%w", err)
 	}
 
-	packed := PackEntity(entity)
+	// custom partials redefine sub-templates of vue templates, routes template uses another delims
+	for _, partial := range g.templates.Partials() {
+		if parsed, err = parsed.Parse(partial); err != nil {
+			return fmt.Errorf("parse partial, err=%w", err)
+		}
+	}
 
 	var buffer bytes.Buffer
-	if err := parsed.ExecuteTemplate(&buffer, "base", packed); err != nil {
+	if err := parsed.ExecuteTemplate(&buffer, "base", data); err != nil {
 		return fmt.Errorf("processing model template, err=%w", err)
 	}
 
-	_, err = mfd.Save(buffer.Bytes(), path.Join(g.options.Output, "src/pages/Entity", entity.Name, output))
+	_, err = g.files.Save(buffer.Bytes(), path.Join(g.options.Output, "src/pages/Entity", output))
 	return err
 }
 
@@ -237,7 +342,7 @@ func (g *Generator) SaveRoutes(namespaces []*mfd.VTNamespace, tmpl string) (bool
 		return false, fmt.Errorf("processing model template, err=%w", err)
 	}
 
-	return mfd.Save(buffer.Bytes(), path.Join(g.options.Output, "src/pages/Entity/routes.ts"))
+	return g.files.Save(buffer.Bytes(), path.Join(g.options.Output, "src/pages/Entity/routes.ts"))
 }
 
 func (g *Generator) SaveLang(entity *mfd.TranslationEntity, lang string) error {
@@ -246,7 +351,7 @@ func (g *Generator) SaveLang(entity *mfd.TranslationEntity, lang string) error {
 	}
 
 	output := path.Join(g.options.Output, "src/pages/Entity", entity.Name, lang+".json")
-	if err := mfd.MarshalJSONToFile(output, entity.ToJSONMap()); err != nil {
+	if err := g.files.MarshalJSONToFile(output, entity.ToJSONMap()); err != nil {
 		return fmt.Errorf("save translation lang %s, err=%w", lang, err)
 	}
 
@@ -14,9 +14,15 @@ type Options struct {
 	// Entities to generate
 	Entities []string
 
+	// Jobs is a count of entities generated concurrently, count of CPUs is used if not set
+	Jobs int
+
 	// custom templates
 	RoutesTemplatePath  string
 	ListTemplatePath    string
 	FiltersTemplatePath string
 	FormTemplatePath    string
+
+	// TemplatesDir stores custom templates and partials in template subdir
+	TemplatesDir string
 }
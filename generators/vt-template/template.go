@@ -9,7 +9,8 @@ export default [{{range $model := .Entities}}
     component: () =>
       import("@/pages/Entity/{{.Name}}/List.vue"),
     meta: {
-      breadcrumbs: ["dashboard", "{{.JSName}}List"]
+      breadcrumbs: ["dashboard", "{{.JSName}}List"]{{if .RoleNames}},
+      roles: [{{range $i, $e := .RoleNames}}{{if $i}}, {{end}}"{{.}}"{{end}}]{{end}}
     }
   },
   {{if not .ReadOnly}}{
@@ -18,7 +19,8 @@ export default [{{range $model := .Entities}}
     component: () =>
       import("@/pages/Entity/{{.Name}}/Form.vue"),
     meta: {
-      breadcrumbs: ["dashboard", "{{.JSName}}List", "{{.JSName}}Edit"]
+      breadcrumbs: ["dashboard", "{{.JSName}}List", "{{.JSName}}Edit"]{{if .RoleNames}},
+      roles: [{{range $i, $e := .RoleNames}}{{if $i}}, {{end}}"{{.}}"{{end}}]{{end}}
     }
   },
   {
@@ -27,14 +29,18 @@ export default [{{range $model := .Entities}}
     component: () =>
       import("@/pages/Entity/{{.Name}}/Form.vue"),
     meta: {
-      breadcrumbs: ["dashboard", "{{.JSName}}List", "{{.JSName}}Add"]
+      breadcrumbs: ["dashboard", "{{.JSName}}List", "{{.JSName}}Add"]{{if .RoleNames}},
+      roles: [{{range $i, $e := .RoleNames}}{{if $i}}, {{end}}"{{.}}"{{end}}]{{end}}
     }
   },{{end}}{{end}}
 ];
 `
 
 // fuck backtick js
-const listDefaultTemplate = `<template>
+const listDefaultTemplate = `[[template "list.template" .]]
+[[template "list.script" .]]
+<style lang="scss"></style>
+[[define "list.template"]]<template>
   <vt-entity-view>
     <v-layout
       align-start
@@ -66,7 +72,38 @@ const listDefaultTemplate = `<template>
               </v-flex>
               <v-spacer />
               <v-flex shrink>
-                [[if not .ReadOnly]]<v-btn
+                [[if .HasExport]]<v-btn
+                  text
+                  color="primary"
+                  :disabled="store.isLoading"
+                  :loading="isExporting"
+                  @click.stop="exportList('xlsx')"
+                >
+                  <v-icon left>
+                    cloud_download
+                  </v-icon>
+                  {{ $t("common.list.exportLabel") }}
+                </v-btn>[[end]][[if .HasImport]]
+                <v-btn
+                  text
+                  color="primary"
+                  :disabled="store.isLoading"
+                  :loading="isImporting"
+                  @click.stop="$refs.importFile.click()"
+                >
+                  <v-icon left>
+                    cloud_upload
+                  </v-icon>
+                  {{ $t("common.list.importLabel") }}
+                </v-btn>
+                <input
+                  ref="importFile"
+                  type="file"
+                  accept=".csv,.xlsx"
+                  hidden
+                  @change="importList"
+                >
+                [[end]][[if not .ReadOnly]]<v-btn
                   dark
                   color="success"
                   :to="{ name: '[[.JSName]]Add' }"
@@ -79,7 +116,31 @@ const listDefaultTemplate = `<template>
               </v-flex>
             </v-layout>
           </v-flex>
-        </v-layout>
+        </v-layout>[[if .HasImport]]
+        <v-layout
+          v-if="importResult"
+          justify-center
+        >
+          <v-flex
+            xs12
+            md8
+          >
+            <v-alert
+              :type="importResult.errors && importResult.errors.length ? 'warning' : 'success'"
+              dismissible
+              text
+              @input="importResult = null"
+            >
+              {{ $t("common.list.importResultLabel", importResult) }}
+              <div
+                v-for="item in importResult.errors"
+                :key="item.row"
+              >
+                {{ item.row }}: {{ item.message || item.fields.map(f => f.field).join(", ") }}
+              </div>
+            </v-alert>
+          </v-flex>
+        </v-layout>[[end]]
 
         [[raw "<!-- Complex Table -->"]]
         <v-layout justify-center>
@@ -177,7 +238,15 @@ const listDefaultTemplate = `<template>
                   </router-link>[[end]]
                 </template>[[end]][[end]][[if not $.ReadOnly]]
                 <template #item.[[range $.PKs]][[.JSName]]="{ item }"[[end]]>
-                  <span class="text-no-wrap">
+                  <span class="text-no-wrap">[[if $.HasAudit]]
+                    <v-btn
+                      text
+                      icon
+                      color="grey"
+                      @click="showHistory(item)"
+                    >
+                      <v-icon small>history</v-icon>
+                    </v-btn>[[end]]
                     <v-hover v-slot="{ hover }">
                       <v-btn
                         text
@@ -196,11 +265,41 @@ const listDefaultTemplate = `<template>
           </v-flex>
         </v-layout>
       </v-flex>
-    </v-layout>
+    </v-layout>[[if .HasAudit]]
+
+    [[raw "<!-- History -->"]]
+    <v-navigation-drawer
+      v-model="isHistoryShown"
+      width="400"
+      temporary
+      right
+      fixed
+    >
+      <v-list dense>
+        <v-subheader>{{ $t("common.list.historyLabel") }}</v-subheader>
+        <v-list-item
+          v-for="log in history"
+          :key="log.id"
+        >
+          <v-list-item-content>
+            <v-list-item-title>
+              {{ log.action }}, {{ log.createdAt | dateTime }}
+            </v-list-item-title>
+            <v-list-item-subtitle
+              v-for="(change, field) in log.changes"
+              :key="field"
+            >
+              {{ field }}: {{ change.old }} → {{ change.new }}
+            </v-list-item-subtitle>
+          </v-list-item-content>
+        </v-list-item>
+      </v-list>
+    </v-navigation-drawer>[[end]]
   </vt-entity-view>
 </template>
+[[end -]]
 
-[[raw "<"]]script lang="ts">
+[[define "list.script"]][[raw "<"]]script lang="ts">
 import { Component } from 'vue-property-decorator';
 import { Observer } from 'mobx-vue';
 import EntityList from '@/common/Entity/EntityList';
@@ -210,6 +309,9 @@ import {
   [[.Name]]Search as SearchModel
 } from '@/services/api/factory';
 import MultiFilters from './components/MultiListFilters.vue';
+[[- if or .HasExport .HasAudit ]]
+import api from '@/services/api';
+[[- end ]]
 
 @Observer
 @Component({
@@ -218,6 +320,64 @@ import MultiFilters from './components/MultiListFilters.vue';
 })
 export default class List extends EntityList {
   store: Store = new Store(Model, SearchModel);
+[[- if .HasExport ]]
+
+  isExporting = false;
+
+  async exportList(format: string) {
+    this.isExporting = true;
+    try {
+      const file = await api.[[.JSName]].export({ search: this.store.filters, format });
+      const content = Uint8Array.from(atob(file.content), c => c.charCodeAt(0));
+      const link = document.createElement('a');
+      link.href = URL.createObjectURL(new Blob([content], { type: file.contentType }));
+      link.download = file.name;
+      link.click();
+      URL.revokeObjectURL(link.href);
+    } finally {
+      this.isExporting = false;
+    }
+  }
+[[- end ]]
+[[- if .HasImport ]]
+
+  isImporting = false;
+  importResult: any = null;
+
+  async importList(event: Event) {
+    const input = event.target as HTMLInputElement;
+    const file = input.files && input.files[0];
+    if (!file) {
+      return;
+    }
+
+    this.isImporting = true;
+    try {
+      const content = await new Promise<string>((resolve, reject) => {
+        const reader = new FileReader();
+        reader.onload = () => resolve(String(reader.result).split(',')[1]);
+        reader.onerror = reject;
+        reader.readAsDataURL(file);
+      });
+      const format = file.name.split('.').pop();
+      this.importResult = await api.[[.JSName]].import({ file: { name: file.name, format, content } });
+      this.submitFilters();
+    } finally {
+      input.value = '';
+      this.isImporting = false;
+    }
+  }
+[[- end ]]
+[[- if .HasAudit ]]
+
+  isHistoryShown = false;
+  history: any[] = [];
+
+  async showHistory(item: Model) {
+    this.history = await api.[[.JSName]].history({ [[range $i, $e := .PKs]][[if $i]], [[end]][[.JSName]]: item.[[.JSName]][[end]] });
+    this.isHistoryShown = true;
+  }
+[[- end ]]
 
   get headers () {
     return [
@@ -245,11 +405,11 @@ export default class List extends EntityList {
   }
 }
 </script>
+[[end]]`
 
-<style lang="scss"></style>
-`
-
-const filterDefaultTemplate = `<template>
+const filterDefaultTemplate = `[[template "filter.template" .]]
+[[template "filter.script" .]]
+[[- define "filter.template"]]<template>
   <vt-multi-filter
     :items="filterItems"
     :filters="filters"
@@ -258,8 +418,9 @@ const filterDefaultTemplate = `<template>
     @submitFilters="$emit('submitFilters')"
   />
 </template>
+[[end -]]
 
-<script lang="ts">
+[[define "filter.script"]]<script lang="ts">
 import { Component } from 'vue-property-decorator';
 import { Observer } from 'mobx-vue';
 import EntityListFilters from '@/common/Entity/EntityListFilters';
@@ -292,7 +453,15 @@ export default class MultiListFilters extends EntityListFilters {
         [[- end ]]
         [[- if .IsFK ]]
         entity: '[[ .FKJSName | ToLower ]]',
+        [[- if .IsRemote ]]
+        searchBy: '[[ .FKJSRemoteSearch ]]',
+        remote: true,
+        [[- if not .IsArray ]]
+        itemText: '[[ .FKJSSearch ]]',
+        [[- end ]]
+        [[- else ]]
         searchBy: '[[ .FKJSSearch ]]',
+        [[- end ]]
         async: true,
         [[- end ]]
         [[- if and .IsFK .IsArray ]]
@@ -310,9 +479,12 @@ export default class MultiListFilters extends EntityListFilters {
   ].filter(Boolean)
 }
 </script>
-`
+[[end]]`
 
-const formDefaultTemplate = `<template>
+const formDefaultTemplate = `[[template "form.template" .]]
+[[template "form.script" .]]
+<style scoped></style>
+[[define "form.template"]]<template>
   <vt-entity-view>
     <v-layout
       align-start
@@ -373,7 +545,10 @@ const formDefaultTemplate = `<template>
             }"
           >
             Основные
-          </v-tab>
+          </v-tab>[[range .Children]]
+          <v-tab :disabled="!$route.params.id">
+            {{ $t("[[$.JSName]].form.[[.JSName]]Label") }}
+          </v-tab>[[end]]
         </v-tabs>
         <v-card v-if="store.model">
           <v-form
@@ -390,18 +565,25 @@ const formDefaultTemplate = `<template>
 	                    v-model="store.model.[[.JSName]]"
 						[[raw ":error-messages="]]"$t(i18nFieldError(store.errors.[[.JSName]]))"
                     	:disabled="store.isLoading"
-						:label="$t('[[$.JSName]].form.[[.JSName]]Label')"
+						:label="$t('[[$.JSName]].form.[[.JSName]]Label')"[[if .Hint]]
+						hint="[[.Hint]]"
+						persistent-hint[[end]]
 						color="primary"
 	                  />
 					</template>
                   </vt-form-field>
                   [[else]][[raw "<vt-form-field"]]
                     v-model="store.model.[[.JSName]]"[[if .IsFK]]
-                    entity="[[ .FKJSName | ToLower ]]"
+                    entity="[[ .FKJSName | ToLower ]]"[[if .IsRemote]]
+                    search-by="[[.FKJSRemoteSearch]]"
+                    item-text="[[.FKJSSearch]]"
+                    remote[[else]]
                     search-by="[[.FKJSSearch]]"
-                    prefetch[[end]]
+                    prefetch[[end]][[end]]
                     component="[[.Component]]"
-                    :label="$t('[[$.JSName]].form.[[.JSName]]Label')"
+                    :label="$t('[[$.JSName]].form.[[.JSName]]Label')"[[if .Hint]]
+                    hint="[[.Hint]]"
+                    persistent-hint[[end]]
                     :error-messages="$t(i18nFieldError(store.errors.[[.JSName]]))"
                     :disabled="store.isLoading"
                     placeholder=""[[if .Required]]
@@ -410,7 +592,13 @@ const formDefaultTemplate = `<template>
                     iso[[end]][[range .Params]]
                     [[.]][[end]]
                   />[[end]][[end]][[raw "<!--  end generated part -->"]]
-                </v-tab-item>
+                </v-tab-item>[[range .Children]]
+                <v-tab-item>
+                  [[raw "<"]][[.Name]]Table
+                    v-if="$route.params.id"
+                    :parent-id="Number($route.params.id)"
+                  />
+                </v-tab-item>[[end]]
               </v-tabs-items>
             </v-card-text>
             <v-card-actions>
@@ -461,20 +649,168 @@ const formDefaultTemplate = `<template>
     </v-layout>
   </vt-entity-view>
 </template>
+[[end -]]
 
-[[raw "<script"]] lang="ts">
+[[define "form.script"]][[raw "<script"]] lang="ts">
 import { Component } from 'vue-property-decorator';
 import { Observer } from 'mobx-vue';
 import { [[.Name]] as Model } from '@/services/api/factory';
 import Store from '@/common/Entity/EntityModelStore';
 import EntityForm from '@/common/Entity/EntityForm';
+[[- range .Children ]]
+import [[.Name]]Table from './components/[[.Name]]Table.vue';
+[[- end ]]
 
 @Observer
 @Component
+[[- if .HasChildren ]]({
+  components: {
+  [[- range .Children ]]
+    [[.Name]]Table,
+  [[- end ]]
+  },
+})
+[[- end ]]
 export default class Form extends EntityForm {
   store: Store<Model> = new Store<Model>(Model);
 }
 </script>
+[[end]]`
 
+const childTableTemplate = `[[template "children.template" .]]
+[[template "children.script" .]]
 <style scoped></style>
-`
+[[define "children.template"]]<template>
+  <div>
+    <v-simple-table dense>
+      <thead>
+        <tr>
+          [[- range .Columns ]]
+          <th>{{ $t('[[$.EntityJSName]].form.[[.JSName]]Label') }}</th>
+          [[- end ]]
+          <th />
+        </tr>
+      </thead>
+      <tbody>
+        <tr
+          v-for="(item, index) in items"
+          :key="index"
+        >
+          [[- range .Columns ]]
+          <td>
+            [[- if .IsCheckBox ]]
+            <v-checkbox
+              v-model="item.[[.JSName]]"
+              :disabled="isLoading"
+              color="primary"
+              hide-details
+            />
+            [[- else ]]
+            [[raw "<vt-form-field"]]
+              v-model="item.[[.JSName]]"[[if .IsFK]]
+              entity="[[ .FKJSName | ToLower ]]"[[if .IsRemote]]
+              search-by="[[.FKJSRemoteSearch]]"
+              item-text="[[.FKJSSearch]]"
+              remote[[else]]
+              search-by="[[.FKJSSearch]]"
+              prefetch[[end]][[end]]
+              component="[[.Component]]"
+              :disabled="isLoading"
+              placeholder=""
+              hide-details[[if .Required]]
+              required[[end]][[if eq .Component "vt-datetime-picker"]]
+              iso[[end]][[range .Params]]
+              [[.]][[end]]
+            />
+            [[- end ]]
+          </td>
+          [[- end ]]
+          <td>
+            <v-btn
+              icon
+              small
+              :disabled="isLoading"
+              @click.stop="items.splice(index, 1)"
+            >
+              <v-icon>delete</v-icon>
+            </v-btn>
+          </td>
+        </tr>
+      </tbody>
+    </v-simple-table>
+    <v-alert
+      v-if="error"
+      type="error"
+      dense
+      text
+      class="mt-2"
+    >
+      {{ error }}
+    </v-alert>
+    <v-layout class="mt-2">
+      <v-btn
+        text
+        color="primary"
+        :disabled="isLoading"
+        @click.stop="items.push({})"
+      >
+        <v-icon left>
+          add
+        </v-icon>
+        {{ $t("common.form.addButtonLabel") }}
+      </v-btn>
+      <v-spacer />
+      <v-btn
+        color="success"
+        :disabled="isLoading"
+        :loading="isLoading"
+        @click.stop="save"
+      >
+        {{ $t("common.form.saveButtonLabel") }}
+      </v-btn>
+    </v-layout>
+  </div>
+</template>
+[[end -]]
+
+[[define "children.script"]][[raw "<script"]] lang="ts">
+import { Component, Prop, Vue } from 'vue-property-decorator';
+import api from '@/services/api';
+
+@Component
+export default class [[.Name]]Table extends Vue {
+  @Prop({ type: Number, required: true }) parentId!: number;
+
+  items: any[] = [];
+  isLoading = false;
+  error = '';
+
+  async mounted() {
+    await this.load();
+  }
+
+  async load() {
+    this.isLoading = true;
+    try {
+      this.items = await api.[[.ServiceJSName]].get[[.Name]]({ id: this.parentId });
+    } catch (e) {
+      this.error = e.message;
+    } finally {
+      this.isLoading = false;
+    }
+  }
+
+  async save() {
+    this.isLoading = true;
+    this.error = '';
+    try {
+      this.items = await api.[[.ServiceJSName]].save[[.Name]]({ id: this.parentId, items: this.items });
+    } catch (e) {
+      this.error = e.message;
+    } finally {
+      this.isLoading = false;
+    }
+  }
+}
+</script>
+[[end]]`
@@ -20,9 +20,17 @@ type EntityData struct {
 
 	ReadOnly bool
 
+	HasExport bool
+	HasImport bool
+
+	HasAudit bool
+
 	ListColumns   []AttributeData
 	FilterColumns []InputData
 	FormColumns   []InputData
+
+	HasChildren bool
+	Children    []ChildData
 }
 
 // PackEntity packs mfd vt entity to template data
@@ -38,6 +46,11 @@ func PackEntity(vtEntity mfd.VTEntity) EntityData {
 		JSName:   mfd.VarName(vtEntity.Name),
 		PKs:      pkPairs,
 		ReadOnly: vtEntity.Mode == mfd.ModeReadOnlyWithTemplates,
+
+		HasExport: vtEntity.ImportExport,
+		HasImport: vtEntity.HasImport(),
+
+		HasAudit: vtEntity.HasAudit(),
 	}
 
 	if title := vtEntity.Entity.TitleAttribute(); title != nil {
@@ -52,7 +65,7 @@ func PackEntity(vtEntity mfd.VTEntity) EntityData {
 		if attr.Search != mfd.TypeHTMLNone && attr.Search != "" {
 			tmpl.FilterColumns = append(tmpl.FilterColumns, PackInput(*attr, vtEntity, true))
 		}
-		if attr.Form != mfd.TypeHTMLNone && attr.Form != "" {
+		if attr.Form != mfd.TypeHTMLNone && attr.Form != "" && !attr.IsChildren() {
 			tmpl.FormColumns = append(tmpl.FormColumns, PackInput(*attr, vtEntity, false))
 		}
 	}
@@ -60,6 +73,58 @@ func PackEntity(vtEntity mfd.VTEntity) EntityData {
 	return tmpl
 }
 
+// ChildData stores has-many relation info edited inline in form
+type ChildData struct {
+	Name   string
+	JSName string
+
+	EntityJSName  string
+	ServiceJSName string
+	Columns       []InputData
+}
+
+// PackChildren packs inline edited children of vt entity to template data
+// Only children from the same vt namespace with full mode are supported
+func PackChildren(vtEntity mfd.VTEntity, namespace *mfd.VTNamespace) []ChildData {
+	if vtEntity.Mode != mfd.ModeFull || len(vtEntity.Entity.PKs()) != 1 {
+		return nil
+	}
+
+	var children []ChildData
+	for _, tmpl := range vtEntity.TmplAttributes {
+		if !tmpl.IsChildren() || tmpl.ChildEntity == nil || tmpl.ChildAttribute == nil {
+			continue
+		}
+
+		childVT := namespace.VTEntity(tmpl.ChildEntity.Name)
+		if childVT == nil || childVT.Mode != mfd.ModeFull || len(tmpl.ChildEntity.PKs()) != 1 {
+			continue
+		}
+
+		child := ChildData{
+			Name:          tmpl.Name,
+			JSName:        mfd.VarName(tmpl.Name),
+			EntityJSName:  mfd.VarName(childVT.Name),
+			ServiceJSName: mfd.VarName(vtEntity.Name),
+		}
+
+		for _, attr := range childVT.TmplAttributes {
+			if attr.Form == mfd.TypeHTMLNone || attr.Form == "" || attr.IsChildren() {
+				continue
+			}
+			// parent fk is set by service
+			if attr.VTAttribute != nil && attr.VTAttribute.AttrName == tmpl.ChildAttribute.Name {
+				continue
+			}
+			child.Columns = append(child.Columns, PackInput(*attr, *childVT, false))
+		}
+
+		children = append(children, child)
+	}
+
+	return children
+}
+
 // AttributeData stores attribute info
 type AttributeData struct {
 	JSName string
@@ -85,7 +150,7 @@ func PackAttribute(vtEntity mfd.VTEntity, tmpl mfd.TmplAttribute) AttributeData
 		if attr.IsDateTime() {
 			pipe = "tableDate"
 		}
-		if attr.ForeignKey != "" {
+		if attr.ForeignKey != "" && !attr.ForeignEntity.IsImported() {
 			pipe = fmt.Sprintf(`getField("%s")`, mfd.VarName(tmpl.FKOpts))
 			isSortable = false
 		}
@@ -115,8 +180,15 @@ type InputData struct {
 	FKJSSearch string
 	SearchType string
 
+	// remote search picker for large related entities
+	IsRemote         bool
+	FKJSRemoteSearch string
+
 	Required bool
 
+	// Hint is a description of attribute shown under form input
+	Hint string
+
 	IsArray    bool
 	IsCheckBox bool
 	IsNumber   bool
@@ -172,6 +244,9 @@ func PackInput(tmpl mfd.TmplAttribute, vtEntity mfd.VTEntity, isSearch bool) Inp
 		inp.Required = tmpl.VTAttribute.Required
 
 		attr := tmpl.VTAttribute.Attribute
+		if !isSearch && attr != nil {
+			inp.Hint = strings.TrimSpace(attr.Description)
+		}
 
 		if attr.ForeignKey == mfd.VfsFile {
 			inp.Component = filterComponent(tmpl.Form, isSearch)
@@ -180,7 +255,7 @@ func PackInput(tmpl mfd.TmplAttribute, vtEntity mfd.VTEntity, isSearch bool) Inp
 			inp.FKJSSearch = mfd.VarName(tmpl.FKOpts)
 			inp.Params = append(inp.Params, `:file="store.model.`+template.HTML(inp.FKJSName)+`"
                     @input:file="file => store.model.`+template.HTML(inp.FKJSName)+` = file"`)
-		} else if attr.ForeignKey != "" {
+		} else if attr.ForeignKey != "" && !attr.ForeignEntity.IsImported() {
 			inp.Component = "vt-entity-autocomplete"
 			inp.IsFK = true
 			inp.FKJSName = mfd.VarName(attr.ForeignEntity.Name)
@@ -189,6 +264,21 @@ func PackInput(tmpl mfd.TmplAttribute, vtEntity mfd.VTEntity, isSearch bool) Inp
 				inp.Params = append(inp.Params, `multiple`, `chips`)
 				inp.IsArray = true
 			}
+
+			input := tmpl.Form
+			if isSearch {
+				input = tmpl.Search
+			}
+
+			if input == mfd.TypeHTMLAutocomplete {
+				inp.IsRemote = true
+				inp.FKJSRemoteSearch = remoteSearchField(*attr.ForeignEntity, inp.FKJSSearch)
+
+				// search by ids array of related entity
+				if search := vtEntity.Entity.SearchByName(tmpl.VTAttribute.SearchName); isSearch && search != nil && search.SearchType.IsArraySearch() {
+					inp.IsArray = true
+				}
+			}
 		}
 	}
 
@@ -199,6 +289,15 @@ func PackInput(tmpl mfd.TmplAttribute, vtEntity mfd.VTEntity, isSearch bool) Inp
 	return inp
 }
 
+// remoteSearchField returns vt search field of related entity used by remote picker
+func remoteSearchField(foreign mfd.Entity, def string) string {
+	if title := foreign.TitleAttribute(); title != nil && !title.PrimaryKey {
+		return mfd.VarName(title.Name)
+	}
+
+	return def
+}
+
 func filterComponent(input string, isSearch bool) string {
 	defaultComponent := "v-text-field"
 	switch input {
@@ -246,7 +345,7 @@ func filterInputType(input string, inp InputData) string {
 		return "datetime"
 	case mfd.TypeHTMLDate:
 		return "date"
-	case mfd.TypeHTMLSelect:
+	case mfd.TypeHTMLSelect, mfd.TypeHTMLAutocomplete:
 		return "select"
 	case mfd.TypeHTMLCheckbox:
 		return "boolean"
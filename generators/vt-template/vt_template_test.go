@@ -0,0 +1,187 @@
+package vttmpl
+
+import (
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+func TestPackInput_Autocomplete(t *testing.T) {
+	city := &mfd.Entity{
+		Name: "City",
+		Attributes: mfd.Attributes{
+			{Name: "ID", DBName: "cityId", DBType: "int4", GoType: "int", PrimaryKey: true},
+			{Name: "Title", DBName: "title", DBType: "varchar", GoType: "string"},
+		},
+	}
+	cityID := &mfd.Attribute{Name: "CityID", DBName: "cityId", DBType: "int4", GoType: "int", ForeignKey: "City", ForeignEntity: city}
+	tagIDs := &mfd.Attribute{Name: "TagIDs", DBName: "tagIds", DBType: "int4", GoType: "[]int", IsArray: true, ForeignKey: "City", ForeignEntity: city}
+
+	news := &mfd.Entity{
+		Name:       "News",
+		Attributes: mfd.Attributes{cityID, tagIDs},
+		Searches: mfd.Searches{
+			{Name: "CityIDs", AttrName: "CityID", SearchType: mfd.SearchArray, Attribute: cityID},
+		},
+	}
+	vtEntity := mfd.VTEntity{Name: "News", Entity: news}
+
+	tests := []struct {
+		name       string
+		tmpl       mfd.TmplAttribute
+		isSearch   bool
+		wantRemote bool
+		wantArray  bool
+		wantSearch string
+		wantType   string
+	}{
+		{
+			name:       "prefetch select",
+			tmpl:       mfd.TmplAttribute{Name: "CityID", FKOpts: "title", Form: mfd.TypeHTMLInput, VTAttribute: &mfd.VTAttribute{SearchName: "CityID", Attribute: cityID}},
+			wantRemote: false,
+		},
+		{
+			name:       "remote form",
+			tmpl:       mfd.TmplAttribute{Name: "CityID", FKOpts: "alias", Form: mfd.TypeHTMLAutocomplete, VTAttribute: &mfd.VTAttribute{SearchName: "CityID", Attribute: cityID}},
+			wantRemote: true,
+			wantSearch: "title",
+		},
+		{
+			name:       "remote multiple form",
+			tmpl:       mfd.TmplAttribute{Name: "TagIDs", FKOpts: "title", Form: mfd.TypeHTMLAutocomplete, VTAttribute: &mfd.VTAttribute{SearchName: "TagIDs", Attribute: tagIDs}},
+			wantRemote: true,
+			wantArray:  true,
+			wantSearch: "title",
+		},
+		{
+			name:       "remote array search",
+			tmpl:       mfd.TmplAttribute{Name: "CityIDs", FKOpts: "title", Search: mfd.TypeHTMLAutocomplete, VTAttribute: &mfd.VTAttribute{SearchName: "CityIDs", Attribute: cityID}},
+			isSearch:   true,
+			wantRemote: true,
+			wantArray:  true,
+			wantSearch: "title",
+			wantType:   "multi-select",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inp := PackInput(tt.tmpl, vtEntity, tt.isSearch)
+			if inp.IsRemote != tt.wantRemote {
+				t.Errorf("IsRemote = %v, want %v", inp.IsRemote, tt.wantRemote)
+			}
+			if inp.IsArray != tt.wantArray {
+				t.Errorf("IsArray = %v, want %v", inp.IsArray, tt.wantArray)
+			}
+			if inp.FKJSRemoteSearch != tt.wantSearch {
+				t.Errorf("FKJSRemoteSearch = %v, want %v", inp.FKJSRemoteSearch, tt.wantSearch)
+			}
+			if tt.isSearch && inp.SearchType != tt.wantType {
+				t.Errorf("SearchType = %v, want %v", inp.SearchType, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestPackChildren(t *testing.T) {
+	region := &mfd.Entity{
+		Name: "Region",
+		Attributes: mfd.Attributes{
+			{Name: "ID", DBName: "regionId", DBType: "int4", GoType: "int", PrimaryKey: true},
+		},
+	}
+	regionID := &mfd.Attribute{Name: "RegionID", DBName: "regionId", DBType: "int4", GoType: "int", ForeignKey: "Region", ForeignEntity: region}
+	title := &mfd.Attribute{Name: "Title", DBName: "title", DBType: "varchar", GoType: "string"}
+	city := &mfd.Entity{
+		Name: "City",
+		Attributes: mfd.Attributes{
+			{Name: "ID", DBName: "cityId", DBType: "int4", GoType: "int", PrimaryKey: true},
+			title,
+			regionID,
+		},
+	}
+
+	cityVT := &mfd.VTEntity{
+		Name:   "City",
+		Entity: city,
+		Mode:   mfd.ModeFull,
+		TmplAttributes: mfd.TmplAttributes{
+			{Name: "Title", Form: mfd.TypeHTMLInput, VTAttribute: &mfd.VTAttribute{AttrName: "Title", Attribute: title}},
+			{Name: "RegionID", Form: mfd.TypeHTMLInput, VTAttribute: &mfd.VTAttribute{AttrName: "RegionID", Attribute: regionID}},
+		},
+	}
+	children := &mfd.TmplAttribute{Name: "Cities", Children: "City.RegionID", Form: mfd.TypeHTMLChildren, ChildEntity: city, ChildAttribute: regionID}
+	regionVT := mfd.VTEntity{
+		Name:           "Region",
+		Entity:         region,
+		Mode:           mfd.ModeFull,
+		TmplAttributes: mfd.TmplAttributes{children},
+	}
+	namespace := &mfd.VTNamespace{Name: "geo", Entities: []*mfd.VTEntity{cityVT, &regionVT}}
+
+	packed := PackChildren(regionVT, namespace)
+	if len(packed) != 1 {
+		t.Fatalf("len(children) = %v, want 1", len(packed))
+	}
+	if packed[0].Name != "Cities" || packed[0].ServiceJSName != "region" || packed[0].EntityJSName != "city" {
+		t.Errorf("unexpected child %+v", packed[0])
+	}
+	// parent fk must be excluded from inline columns
+	if len(packed[0].Columns) != 1 || packed[0].Columns[0].JSName != "title" {
+		t.Errorf("unexpected columns %+v", packed[0].Columns)
+	}
+	if entity := PackEntity(regionVT); len(entity.FormColumns) != 0 {
+		t.Errorf("children must not be packed as form column, got %+v", entity.FormColumns)
+	}
+
+	children.Form = mfd.TypeHTMLNone
+	if packed := PackChildren(regionVT, namespace); len(packed) != 0 {
+		t.Errorf("disabled children packed: %+v", packed)
+	}
+}
+
+func TestPackEntity_ImportExport(t *testing.T) {
+	entity := &mfd.Entity{
+		Name:       "Tag",
+		Attributes: mfd.Attributes{{Name: "ID", DBName: "tagId", DBType: "int4", GoType: "int", PrimaryKey: true}},
+	}
+
+	tests := []struct {
+		mode       string
+		flag       bool
+		wantExport bool
+		wantImport bool
+	}{
+		{mode: mfd.ModeFull, flag: false},
+		{mode: mfd.ModeFull, flag: true, wantExport: true, wantImport: true},
+		{mode: mfd.ModeReadOnlyWithTemplates, flag: true, wantExport: true},
+	}
+	for _, tt := range tests {
+		packed := PackEntity(mfd.VTEntity{Name: "Tag", Entity: entity, Mode: tt.mode, ImportExport: tt.flag})
+		if packed.HasExport != tt.wantExport || packed.HasImport != tt.wantImport {
+			t.Errorf("mode %s, flag %v: HasExport = %v, HasImport = %v", tt.mode, tt.flag, packed.HasExport, packed.HasImport)
+		}
+	}
+}
+
+func TestPackEntity_Audit(t *testing.T) {
+	entity := &mfd.Entity{
+		Name:       "Tag",
+		Attributes: mfd.Attributes{{Name: "ID", DBName: "tagId", DBType: "int4", GoType: "int", PrimaryKey: true}},
+	}
+
+	tests := []struct {
+		mode string
+		flag bool
+		want bool
+	}{
+		{mode: mfd.ModeFull, flag: false},
+		{mode: mfd.ModeFull, flag: true, want: true},
+		{mode: mfd.ModeReadOnlyWithTemplates, flag: true},
+	}
+	for _, tt := range tests {
+		packed := PackEntity(mfd.VTEntity{Name: "Tag", Entity: entity, Mode: tt.mode, Audit: tt.flag})
+		if packed.HasAudit != tt.want {
+			t.Errorf("mode %s, flag %v: HasAudit = %v, want %v", tt.mode, tt.flag, packed.HasAudit, tt.want)
+		}
+	}
+}
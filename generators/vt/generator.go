@@ -2,10 +2,11 @@ package vt
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"path"
-	"regexp"
 	"slices"
 
 	"github.com/vmkteam/mfd-generator/mfd"
@@ -21,28 +22,76 @@ const (
 	nsFlag       = "namespaces"
 	embedLogFlag = "embedlog-pkg"
 	entityFlag   = "entities"
+	jobsFlag     = "jobs"
 
 	modelTemplateFlag     = "model-tmpl"
 	converterTemplateFlag = "converter-tmpl"
 	serviceTemplateFlag   = "service-tmpl"
 	serverTemplateFlag    = "server-tmpl"
+	templatesDirFlag      = "templates-dir"
 
 	defaultLoggerPkg = "github.com/vmkteam/embedlog"
 )
 
 // CreateCommand creates generator command
 func CreateCommand() *cobra.Command {
-	return base.CreateCommand("vt", "Create vt from xml", New())
+	command := base.CreateCommand("vt", "Create vt from xml", New())
+	command.Long = command.Short + "\n\n" + mfd.KeepHelp
+
+	return command
+}
+
+// Templates returns built-in templates which could be exported to templates dir and customized
+func Templates() []mfd.BuiltinTemplate {
+	return []mfd.BuiltinTemplate{
+		{Name: "model", Source: modelDefaultTemplate},
+		{Name: "converter", Source: converterDefaultTemplate},
+		{Name: "service", Source: serviceDefaultTemplate},
+		{Name: "server", Source: serverDefaultTemplate},
+		{Name: "authorizer", Source: authorizerDefaultTemplate},
+		{Name: "export", Source: exportDefaultTemplate},
+		{Name: "audit", Source: auditDefaultTemplate},
+	}
 }
 
 // Generator represents mfd vt generator
 type Generator struct {
 	options Options
+	files   *mfd.Files
+
+	// templates are custom templates from templates dir
+	templates *mfd.Templates
 }
 
 // New creates vt generator
 func New() *Generator {
-	return &Generator{}
+	return &Generator{files: mfd.NewFiles(nil)}
+}
+
+// Runner runs vt generator without cobra command
+type Runner = mfd.Runner[Options]
+
+// NewGenerator creates vt generator for usage as a library, default options are filled as in command
+func NewGenerator(options Options) *Runner {
+	return mfd.NewRunner(options, func(options *Options) {
+		if options.EmbedLogPackage == "" {
+			options.EmbedLogPackage = defaultLoggerPkg
+		}
+		if options.Package == "" {
+			options.Package = path.Base(options.Output)
+		}
+		options.Def()
+	}, run)
+}
+
+// run runs generator with files if required options are set
+func run(ctx context.Context, options Options, files *mfd.Files) error {
+	if options.MFDPath == "" || options.Output == "" {
+		return errors.New("mfd file and output dir are required")
+	}
+
+	g := &Generator{options: options, files: files}
+	return g.generate(ctx)
 }
 
 // AddFlags adds flags to command
@@ -70,12 +119,14 @@ func (g *Generator) AddFlags(command *cobra.Command) {
 	flags.StringP(pkgFlag, "p", "", "package name that will be used in golang files. if not set - last element of output path will be used")
 
 	flags.StringSliceP(nsFlag, "n", []string{}, "namespaces to generate. separate by comma\n")
-	flags.StringSliceP(entityFlag, "e", []string{}, "entities to generate. separate by comma\n")
+	flags.StringSliceP(entityFlag, "e", []string{}, "entities to generate. separate by comma")
+	flags.IntP(jobsFlag, "j", 0, "count of namespaces generated concurrently. if not set - count of CPUs will be used\n")
 
 	flags.String(modelTemplateFlag, "", "path to model custom template")
 	flags.String(converterTemplateFlag, "", "path to converter custom template")
 	flags.String(serviceTemplateFlag, "", "path to service custom template")
-	flags.String(serverTemplateFlag, "", "path to server custom template\n")
+	flags.String(serverTemplateFlag, "", "path to server custom template")
+	flags.String(templatesDirFlag, "", "path to custom templates dir, see templates export command\n")
 }
 
 // ReadFlags read flags from command
@@ -120,6 +171,10 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 		g.options.Package = path.Base(g.options.Output)
 	}
 
+	if g.options.Jobs, err = flags.GetInt(jobsFlag); err != nil {
+		return err
+	}
+
 	if g.options.ModelTemplatePath, err = flags.GetString(modelTemplateFlag); err != nil {
 		return err
 	}
@@ -132,6 +187,9 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 	if g.options.ServerTemplatePath, err = flags.GetString(serverTemplateFlag); err != nil {
 		return err
 	}
+	if g.options.TemplatesDir, err = flags.GetString(templatesDirFlag); err != nil {
+		return err
+	}
 
 	g.options.Def()
 
@@ -140,6 +198,10 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 
 // Generate runs generator
 func (g *Generator) Generate() error {
+	return g.generate(context.Background())
+}
+
+func (g *Generator) generate(ctx context.Context) error {
 	// loading project from file
 	project, err := mfd.LoadProject(g.options.MFDPath, false, 0)
 	if err != nil {
@@ -153,72 +215,186 @@ func (g *Generator) Generate() error {
 
 	g.options.GoPGVer = project.GoPGVer
 	g.options.CustomTypes = project.CustomTypes
+	g.options.Databases = project.DatabasesByNamespace()
 
 	if len(g.options.Namespaces) == 0 {
 		g.options.Namespaces = project.NamespaceNames
 	}
 
-	modelTemplate, err := mfd.LoadTemplate(g.options.ModelTemplatePath, modelDefaultTemplate)
+	if g.templates, err = mfd.LoadTemplates(g.options.TemplatesDir, "vt"); err != nil {
+		return fmt.Errorf("load templates, err=%w", err)
+	}
+
+	modelTemplate, err := g.templates.Template("model", g.options.ModelTemplatePath, modelDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load model template, err=%w", err)
 	}
 
-	converterTemplate, err := mfd.LoadTemplate(g.options.ConverterTemplatePath, converterDefaultTemplate)
+	converterTemplate, err := g.templates.Template("converter", g.options.ConverterTemplatePath, converterDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load converter template, err=%w", err)
 	}
 
-	serviceTemplate, err := mfd.LoadTemplate(g.options.ServiceTemplatePath, serviceDefaultTemplate)
+	serviceTemplate, err := g.templates.Template("service", g.options.ServiceTemplatePath, serviceDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load service template, err=%w", err)
 	}
 
-	serverTemplate, err := mfd.LoadTemplate(g.options.ServerTemplatePath, serverDefaultTemplate)
+	serverTemplate, err := g.templates.Template("server", g.options.ServerTemplatePath, serverDefaultTemplate)
 	if err != nil {
 		return fmt.Errorf("load server template, err=%w", err)
 	}
 
 	if len(g.options.Entities) > 0 {
-		return g.PartialUpdate(project, modelTemplate, converterTemplate, serviceTemplate)
+		if err := g.PartialUpdate(project, modelTemplate, converterTemplate, serviceTemplate); err != nil {
+			return err
+		}
+
+		return g.SaveOutputs(project)
 	}
 
+	tasks := make([]mfd.Task, 0, len(g.options.Namespaces))
 	for _, namespace := range g.options.Namespaces {
 		ns := project.VTNamespace(namespace)
 		if ns == nil {
 			return fmt.Errorf("namespace %s not found in project", namespace)
 		}
 
-		// generating each namespace in separate file
-		baseName := mfd.GoFileName(ns.Name)
+		tasks = append(tasks, func(files *mfd.Files) error {
+			// generating each namespace in separate file
+			baseName := mfd.GoFileName(ns.Name)
 
-		modelData, err := PackNamespace(ns, g.options)
+			modelData, err := PackNamespace(ns, g.options)
+			if err != nil {
+				return fmt.Errorf("generate vt model, err=%w", err)
+			}
+
+			// generate model file
+			output := path.Join(g.options.Output, fmt.Sprintf("%s_model.go", baseName))
+			if _, err := files.FormatAndSave(modelData, output, modelTemplate, true, g.templates.Partials()...); err != nil {
+				return fmt.Errorf("generate vt model, err=%w", err)
+			}
+
+			// generate converter file
+			output = path.Join(g.options.Output, fmt.Sprintf("%s_converter.go", baseName))
+			if _, err := files.FormatAndSave(modelData, output, converterTemplate, true, g.templates.Partials()...); err != nil {
+				return fmt.Errorf("generate vt converter, err=%w", err)
+			}
+
+			// generate service file
+			output = path.Join(g.options.Output, fmt.Sprintf("%s.go", baseName))
+			serviceData := PackServiceNamespace(ns, g.options)
+			if _, err := files.FormatAndSave(serviceData, output, serviceTemplate, true, g.templates.Partials()...); err != nil {
+				return fmt.Errorf("generate service %s, err=%w", namespace, err)
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.files.Parallel(ctx, g.options.Jobs, tasks); err != nil {
+		return err
+	}
+
+	if err := g.SaveHelpers(project); err != nil {
+		return err
+	}
+
+	if err := g.SaveOutputs(project); err != nil {
+		return err
+	}
+
+	// printing zenrpc server code
+	if err := PrintServer(project.VTNamespaces, serverTemplate, g.options); err != nil {
+		return fmt.Errorf("generate vt server, err=%w", err)
+	}
+
+	return nil
+}
+
+// SaveOutputs renders vt output rules of project for generated namespaces and entities
+func (g *Generator) SaveOutputs(project *mfd.Project) error {
+	rules := project.OutputRules("vt")
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var entities []mfd.OutputEntity
+	for _, namespace := range g.options.Namespaces {
+		ns := project.VTNamespace(namespace)
+		if ns == nil {
+			continue
+		}
+
+		data, err := PackNamespace(ns, g.options)
 		if err != nil {
-			return fmt.Errorf("generate vt model, err=%w", err)
+			return fmt.Errorf("pack vt namespace %s, err=%w", namespace, err)
 		}
 
-		// generate model file
-		output := path.Join(g.options.Output, fmt.Sprintf("%s_model.go", baseName))
-		if _, err := mfd.FormatAndSave(modelData, output, modelTemplate, true); err != nil {
-			return fmt.Errorf("generate vt model, err=%w", err)
+		for _, entity := range data.Entities {
+			if len(g.options.Entities) > 0 && !slices.Contains(g.options.Entities, entity.VarName) {
+				continue
+			}
+
+			entities = append(entities, mfd.OutputEntity{Namespace: ns.Name, Name: entity.Name, Package: g.options.Package, Entity: entity})
 		}
+	}
+
+	if err := g.files.SaveOutputs(rules, g.options.MFDPath, g.options.Output, entities); err != nil {
+		return fmt.Errorf("generate outputs, err=%w", err)
+	}
 
-		// generate converter file
-		output = path.Join(g.options.Output, fmt.Sprintf("%s_converter.go", baseName))
-		if _, err := mfd.FormatAndSave(modelData, output, converterTemplate, true); err != nil {
-			return fmt.Errorf("generate vt converter, err=%w", err)
+	return nil
+}
+
+// SaveHelpers generates authorizer and, if any of generated entities uses them, export and audit helpers
+func (g *Generator) SaveHelpers(project *mfd.Project) error {
+	var hasExport, hasAudit bool
+	for _, namespace := range g.options.Namespaces {
+		if ns := project.VTNamespace(namespace); ns != nil {
+			for _, entity := range ns.Entities {
+				hasExport = hasExport || entity.ImportExport && entity.Mode != mfd.ModeNone
+				hasAudit = hasAudit || entity.HasAudit()
+			}
 		}
+	}
 
-		// generate service file
-		output = path.Join(g.options.Output, fmt.Sprintf("%s.go", baseName))
-		serviceData := PackServiceNamespace(ns, g.options)
-		if _, err := mfd.FormatAndSave(serviceData, output, serviceTemplate, true); err != nil {
-			return fmt.Errorf("generate service %s, err=%w", namespace, err)
+	data := HelperData{
+		Package:      g.options.Package,
+		ModelPackage: g.options.ModelPackage,
+
+		Roles: PackHelperRoles(project.VTNamespaces),
+	}
+
+	output := path.Join(g.options.Output, "authorizer.go")
+	authorizerTemplate, err := g.templates.Template("authorizer", "", authorizerDefaultTemplate)
+	if err != nil {
+		return fmt.Errorf("load authorizer template, err=%w", err)
+	}
+	if _, err := g.files.FormatAndSave(data, output, authorizerTemplate, true, g.templates.Partials()...); err != nil {
+		return fmt.Errorf("generate vt authorizer, err=%w", err)
+	}
+
+	if hasExport {
+		output := path.Join(g.options.Output, "export.go")
+		exportTemplate, err := g.templates.Template("export", "", exportDefaultTemplate)
+		if err != nil {
+			return fmt.Errorf("load export template, err=%w", err)
+		}
+		if _, err := g.files.FormatAndSave(data, output, exportTemplate, true, g.templates.Partials()...); err != nil {
+			return fmt.Errorf("generate vt export, err=%w", err)
 		}
 	}
 
-	// printing zenrpc server code
-	if err := PrintServer(project.VTNamespaces, serverTemplate, g.options); err != nil {
-		return fmt.Errorf("generate vt server, err=%w", err)
+	if hasAudit {
+		output := path.Join(g.options.Output, "audit.go")
+		auditTemplate, err := g.templates.Template("audit", "", auditDefaultTemplate)
+		if err != nil {
+			return fmt.Errorf("load audit template, err=%w", err)
+		}
+		if _, err := g.files.FormatAndSave(data, output, auditTemplate, true, g.templates.Partials()...); err != nil {
+			return fmt.Errorf("generate vt audit, err=%w", err)
+		}
 	}
 
 	return nil
@@ -292,19 +468,15 @@ func (g *Generator) PartialUpdate(project *mfd.Project, modelTemplate, converter
 
 		baseName := mfd.GoFileName(ns.Name)
 
-		// precompile regexp
-		funcRe := regexp.MustCompile(FuncPattern)
-		structRe := regexp.MustCompile(StructPattern)
-
 		for _, e := range ee {
 			// generate service file
 			output := path.Join(g.options.Output, fmt.Sprintf("%s.go", baseName))
 			serviceData.Entities = []ServiceEntityData{e}
 			buf := new(bytes.Buffer)
-			if err := mfd.Render(buf, serviceTemplate, serviceData); err != nil {
+			if err := mfd.Render(buf, serviceTemplate, serviceData, g.templates.Partials()...); err != nil {
 				return fmt.Errorf("render service, err=%w", err)
 			}
-			if _, err := mfd.UpdateFile(buf, output, "{", "}", structRe, true); err != nil {
+			if _, err := g.files.UpdateFile(buf, output, true); err != nil {
 				return fmt.Errorf("update file, service=%s, err=%w", namespace, err)
 			}
 		}
@@ -314,24 +486,25 @@ func (g *Generator) PartialUpdate(project *mfd.Project, modelTemplate, converter
 			output := path.Join(g.options.Output, fmt.Sprintf("%s_model.go", baseName))
 			modelData.Entities = []EntityData{entity}
 			modelBuf := new(bytes.Buffer)
-			if err := mfd.Render(modelBuf, modelTemplate, modelData); err != nil {
+			if err := mfd.Render(modelBuf, modelTemplate, modelData, g.templates.Partials()...); err != nil {
 				return fmt.Errorf("render model, model=%s, err=%w", entity.Name, err)
 			}
-			if _, err := mfd.UpdateFile(modelBuf, output, "{", "}", structRe, true); err != nil {
+			if _, err := g.files.UpdateFile(modelBuf, output, true); err != nil {
 				return fmt.Errorf("update model file, entity=%s, err=%w", entity.Name, err)
 			}
 			// generate converter file
 			converterBuf := new(bytes.Buffer)
-			if err := mfd.Render(converterBuf, converterTemplate, modelData); err != nil {
+			if err := mfd.Render(converterBuf, converterTemplate, modelData, g.templates.Partials()...); err != nil {
 				return fmt.Errorf("render converer, model=%s, err=%w", entity.Name, err)
 			}
 			output = path.Join(g.options.Output, fmt.Sprintf("%s_converter.go", baseName))
-			if _, err := mfd.UpdateFile(converterBuf, output, "{", "}", funcRe, true); err != nil {
+			if _, err := g.files.UpdateFile(converterBuf, output, true); err != nil {
 				return fmt.Errorf("update converter file, entity=%s, err=%w", entity.Name, err)
 			}
 		}
 	}
 
+	// helpers are shared by all entities, they are generated only by full generation
 	return nil
 }
 
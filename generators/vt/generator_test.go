@@ -124,7 +124,7 @@ func TestGenerator_Generate(t *testing.T) {
 			generator.options.Output = testdata.PathActualVT
 			generator.options.MFDPath = testdata.PathExpectedMFD
 			generator.options.Package = testdata.PackageVT
-			generator.options.Namespaces = []string{"portal"}
+			generator.options.Namespaces = []string{"portal", "geo"}
 			generator.options.ModelPackage = "github.com/vmkteam/mfd-generator/generators/testdata/expected/db"
 			generator.options.EmbedLogPackage = defaultLoggerPkg
 
@@ -134,6 +134,12 @@ func TestGenerator_Generate(t *testing.T) {
 
 		Convey("Check generated files", func() {
 			expectedFilenames := map[string]struct{}{
+				"audit.go":            {},
+				"authorizer.go":       {},
+				"export.go":           {},
+				"geo.go":              {},
+				"geo_converter.go":    {},
+				"geo_model.go":        {},
 				"portal.go":           {},
 				"portal_converter.go": {},
 				"portal_model.go":     {},
@@ -88,6 +88,9 @@ type EntityData struct {
 
 	Params    []ParamsData
 	HasParams bool
+
+	// DBVar is a name of connection to entity database in server code
+	DBVar string
 }
 
 // PackEntity packs mfd vt entity to template data
@@ -112,7 +115,7 @@ func PackEntity(vtEntity mfd.VTEntity, options Options) (EntityData, error) {
 
 			// model column
 			tmpl.ModelColumns = append(tmpl.ModelColumns, PackAttribute(vtEntity, *vtAttr))
-			if attr.ForeignKey != "" && !attr.IsArray {
+			if attr.ForeignKey != "" && !attr.IsArray && !attr.ForeignEntity.IsImported() {
 				tmpl.ModelRelations = append(tmpl.ModelRelations, PackRelation(vtAttr))
 			}
 
@@ -120,7 +123,7 @@ func PackEntity(vtEntity mfd.VTEntity, options Options) (EntityData, error) {
 			if vtAttr.Summary {
 				tmpl.SummaryColumns = append(tmpl.SummaryColumns, PackSummaryAttribute(vtEntity, *vtAttr))
 
-				if attr.ForeignKey != "" && !attr.IsArray {
+				if attr.ForeignKey != "" && !attr.IsArray && !attr.ForeignEntity.IsImported() {
 					tmpl.SummaryRelations = append(tmpl.SummaryRelations, PackRelation(vtAttr))
 				}
 			}
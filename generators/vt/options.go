@@ -34,14 +34,32 @@ type Options struct {
 	// go-pg version
 	GoPGVer int
 
+	// Jobs is a count of namespaces generated concurrently, count of CPUs is used if not set
+	Jobs int
+
 	// custom templates
 	ModelTemplatePath     string
 	ConverterTemplatePath string
 	ServiceTemplatePath   string
 	ServerTemplatePath    string
 
+	// TemplatesDir stores custom templates and partials in vt subdir
+	TemplatesDir string
+
 	// custom types
 	CustomTypes mfd.CustomTypes
+
+	// Databases stores named databases by namespace, namespaces of default database are not listed
+	Databases map[string]mfd.Database
+}
+
+// Database returns database of namespace, default database has DB wrapper and dbo connection
+func (o *Options) Database(namespace string) mfd.Database {
+	if database, ok := o.Databases[namespace]; ok {
+		return database
+	}
+
+	return mfd.Database{}
 }
 
 // Def fills default values of an options
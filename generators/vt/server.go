@@ -17,6 +17,7 @@ func PackServerNamespaces(namespaces []*mfd.VTNamespace, options Options) (Names
 				return NamespaceData{}, err
 			}
 
+			mdl.DBVar = options.Database(namespace.Name).VarName()
 			models = append(models, mdl)
 		}
 	}
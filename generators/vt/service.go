@@ -1,6 +1,7 @@
 package vt
 
 import (
+	"fmt"
 	"html/template"
 
 	"github.com/vmkteam/mfd-generator/generators/model"
@@ -19,6 +20,9 @@ type ServiceNamespaceData struct {
 	Name    string
 	VarName string
 
+	// DBType is a go type of db wrapper of namespace database
+	DBType string
+
 	HasImports bool
 	Imports    []string
 
@@ -35,6 +39,17 @@ func PackServiceNamespace(namespace *mfd.VTNamespace, options Options) ServiceNa
 		}
 
 		packed := PackServiceEntity(*entity, options)
+		packed.Children = PackServiceChildren(*entity, namespace, options)
+		packed.HasChildren = len(packed.Children) > 0
+		if packed.HasChildren {
+			imports.Append(pgImport(options.GoPGVer))
+			packed.UniqueRelations = appendChildRelations(packed.UniqueRelations, packed.Children)
+		}
+
+		if packed.HasImport {
+			imports.Append("errors", pgImport(options.GoPGVer))
+		}
+
 		entities = append(entities, packed)
 		for _, imp := range packed.Imports {
 			imports.Append(imp)
@@ -51,6 +66,8 @@ func PackServiceNamespace(namespace *mfd.VTNamespace, options Options) ServiceNa
 		Name:    name,
 		VarName: mfd.VarName(name),
 
+		DBType: options.Database(namespace.Name).GoName(),
+
 		HasImports: imports.Len() > 0,
 		Imports:    imports.Elements(),
 
@@ -62,6 +79,44 @@ func (tp ServiceNamespaceData) Raw(s string) template.HTML {
 	return template.HTML(s)
 }
 
+// HelperData stores info for authorizer, export and audit helpers
+type HelperData struct {
+	Package      string
+	ModelPackage string
+
+	Roles []HelperRoleData
+}
+
+// HelperRoleData stores roles required to access entity
+type HelperRoleData struct {
+	Entity string
+	Roles  []string
+}
+
+// PackHelperRoles packs roles of every vt entity in project to template data
+func PackHelperRoles(namespaces []*mfd.VTNamespace) []HelperRoleData {
+	var roles []HelperRoleData
+	for _, namespace := range namespaces {
+		for _, entity := range namespace.Entities {
+			names := entity.RoleNames()
+			if entity.Mode == mfd.ModeNone || len(names) == 0 {
+				continue
+			}
+
+			roles = append(roles, HelperRoleData{
+				Entity: entity.Name,
+				Roles:  names,
+			})
+		}
+	}
+
+	return roles
+}
+
+func (tp HelperData) Raw(s string) template.HTML {
+	return template.HTML(s)
+}
+
 // ServiceEntityData stores entity info
 type ServiceEntityData struct {
 	Name          string
@@ -86,7 +141,23 @@ type ServiceEntityData struct {
 	Relations       []ServiceRelationData
 	UniqueRelations []ServiceRelationData
 
+	HasChildren bool
+	Children    []ServiceChildData
+
+	ImportExport  bool
+	HasImport     bool
+	ExportColumns []ServiceExportColumnData
+
+	// TxContext is set if RunInTransaction takes context, it is used by import
+	TxContext bool
+
+	Audit       bool
+	AuditFields []string
+
 	ReadOnly bool
+
+	// Description is a description of entity from project, it is added to method docs
+	Description string
 }
 
 // PackServiceEntity packs mfd vt entity to template data
@@ -100,6 +171,8 @@ func PackServiceEntity(vtEntity mfd.VTEntity, options Options) ServiceEntityData
 	var relations []ServiceRelationData
 	var uniqueRelations []ServiceRelationData
 	var sortColumns []string
+	var exportColumns []ServiceExportColumnData
+	var auditFields []string
 	foreignKeys := make(map[string]struct{})
 	for _, vtAttr := range vtEntity.Attributes {
 		if vtAttr.AttrName != "" {
@@ -107,7 +180,17 @@ func PackServiceEntity(vtEntity mfd.VTEntity, options Options) ServiceEntityData
 				sortColumns = append(sortColumns, vtAttr.Attribute.Name)
 			}
 
-			if vtAttr.Attribute.ForeignKey != "" && vtAttr.Attribute.ForeignEntity != nil {
+			auditFields = append(auditFields, mfd.JSONName(vtAttr.Name))
+
+			if vtAttr.Summary {
+				exportColumns = append(exportColumns, ServiceExportColumnData{
+					Field:    util.ColumnName(vtAttr.Name),
+					JSONName: mfd.JSONName(vtAttr.Name),
+				})
+			}
+
+			// fks to imported entities are not checked, they are stored in another project
+			if vtAttr.Attribute.ForeignKey != "" && vtAttr.Attribute.ForeignEntity != nil && !vtAttr.Attribute.ForeignEntity.IsImported() {
 				serviceRelationData := PackServiceRelationData(*vtAttr, *vtAttr.Attribute.ForeignEntity)
 				relations = append(relations, serviceRelationData)
 				if _, ok := foreignKeys[vtAttr.Attribute.ForeignEntity.Namespace]; !ok {
@@ -167,10 +250,27 @@ func PackServiceEntity(vtEntity mfd.VTEntity, options Options) ServiceEntityData
 		Relations:       relations,
 		UniqueRelations: uniqueRelations,
 
+		ImportExport:  vtEntity.ImportExport,
+		HasImport:     vtEntity.HasImport(),
+		ExportColumns: exportColumns,
+
+		TxContext: options.GoPGVer >= mfd.GoPG10,
+
+		Audit:       vtEntity.HasAudit(),
+		AuditFields: auditFields,
+
 		ReadOnly: vtEntity.Mode == mfd.ModeReadOnly || vtEntity.Mode == mfd.ModeReadOnlyWithTemplates,
+
+		Description: vtEntity.Entity.Description,
 	}
 }
 
+// ServiceExportColumnData stores exported column info
+type ServiceExportColumnData struct {
+	Field    string
+	JSONName string
+}
+
 // ServiceRelationData stores relation info
 type ServiceRelationData struct {
 	Name      string
@@ -198,3 +298,93 @@ func PackServiceRelationData(vtAttr mfd.VTAttribute, foreign mfd.Entity) Service
 		IsArray:   attr.IsArray,
 	}
 }
+
+// ServiceChildData stores has-many relation info edited inline in parent form
+type ServiceChildData struct {
+	Name         string
+	Entity       string
+	EntityPlural string
+	VarName      string
+	NameSpace    string
+	FK           string
+	Nullable     bool
+	TxContext    bool
+
+	// Audit is set if changes of child entity are written to audit log
+	Audit bool
+
+	ParentPK base.PKPair
+	PK       base.PKPair
+}
+
+// PackServiceChildren packs inline edited children of vt entity to template data
+// Only children from the same vt namespace with full mode are supported
+func PackServiceChildren(vtEntity mfd.VTEntity, namespace *mfd.VTNamespace, options Options) []ServiceChildData {
+	parentPKs := vtEntity.Entity.PKs()
+	if vtEntity.Mode != mfd.ModeFull || len(parentPKs) != 1 {
+		return nil
+	}
+
+	baseOptions := base.Options{
+		Package:     options.Package,
+		GoPGVer:     options.GoPGVer,
+		CustomTypes: options.CustomTypes,
+	}
+	parent := base.PackEntity(*vtEntity.Entity, baseOptions)
+
+	var children []ServiceChildData
+	for _, tmpl := range vtEntity.TmplAttributes {
+		if !tmpl.IsChildren() || tmpl.ChildEntity == nil || tmpl.ChildAttribute == nil {
+			continue
+		}
+
+		childVT := namespace.VTEntity(tmpl.ChildEntity.Name)
+		if childVT == nil || childVT.Mode != mfd.ModeFull || len(tmpl.ChildEntity.PKs()) != 1 {
+			continue
+		}
+
+		child := base.PackEntity(*tmpl.ChildEntity, baseOptions)
+		children = append(children, ServiceChildData{
+			Name:         tmpl.Name,
+			Entity:       child.Name,
+			EntityPlural: child.NamePlural,
+			VarName:      child.VarName,
+			NameSpace:    tmpl.ChildEntity.Namespace,
+			FK:           tmpl.ChildAttribute.Name,
+			Nullable:     tmpl.ChildAttribute.Nullable(),
+			TxContext:    options.GoPGVer >= mfd.GoPG10,
+			Audit:        childVT.HasAudit(),
+
+			ParentPK: parent.PKs[0],
+			PK:       child.PKs[0],
+		})
+	}
+
+	return children
+}
+
+// appendChildRelations adds children namespaces repos to service relations
+func appendChildRelations(relations []ServiceRelationData, children []ServiceChildData) []ServiceRelationData {
+	namespaces := make(map[string]struct{}, len(relations))
+	for _, rel := range relations {
+		namespaces[rel.NameSpace] = struct{}{}
+	}
+
+	for _, child := range children {
+		if _, ok := namespaces[child.NameSpace]; !ok {
+			namespaces[child.NameSpace] = struct{}{}
+			relations = append(relations, ServiceRelationData{NameSpace: child.NameSpace})
+		}
+	}
+
+	return relations
+}
+
+// pgImport returns go-pg import path for version
+func pgImport(goPGVer int) string {
+	if goPGVer == mfd.GoPG8 {
+		return "github.com/go-pg/pg"
+	}
+
+	return fmt.Sprintf("github.com/go-pg/pg/v%d", goPGVer)
+}
@@ -0,0 +1,35 @@
+package vt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+func TestPackHelperRoles(t *testing.T) {
+	namespaces := []*mfd.VTNamespace{
+		{
+			Name: "portal",
+			Entities: []*mfd.VTEntity{
+				{Name: "News", Mode: mfd.ModeFull, Roles: "admin, editor"},
+				{Name: "Tag", Mode: mfd.ModeFull},
+				{Name: "Category", Mode: mfd.ModeNone, Roles: "admin"},
+			},
+		},
+		{
+			Name: "geo",
+			Entities: []*mfd.VTEntity{
+				{Name: "City", Mode: mfd.ModeReadOnly, Roles: "manager,"},
+			},
+		},
+	}
+
+	want := []HelperRoleData{
+		{Entity: "News", Roles: []string{"admin", "editor"}},
+		{Entity: "City", Roles: []string{"manager"}},
+	}
+	if got := PackHelperRoles(namespaces); !reflect.DeepEqual(got, want) {
+		t.Errorf("PackHelperRoles() = %+v, want %+v", got, want)
+	}
+}
@@ -8,10 +8,13 @@ import ({{if .HasImports}}{{range .Imports}}
 {{end}}
 	"{{.ModelPackage}}"
 )
-{{range $model := .Entities}}
+{{range .Entities}}
+{{template "vt.model" .}}{{end}}
+{{define "vt.model"}}{{$model := .}}
 type {{.Name}} struct {
 	{{- range .ModelColumns}}
-	{{.Name}} {{.GoType}} {{.Tag}} {{.Comment}}{{end}}{{if .HasModelRelations}}
+	{{with .Attribute.Description}}{{comment .}}
+	{{end}}{{.Name}} {{.GoType}} {{.Tag}} {{.Comment}}{{end}}{{if .HasModelRelations}}
 	{{range .ModelRelations}}
 	{{.Name}} *{{.Type}}{{if ne .Type "Status"}}Summary{{end}} {{.Tag}}{{end}}{{end}}
 }
@@ -59,7 +62,8 @@ func ({{.ShortVarName}}s *{{.Name}}Search) ToDB() *db.{{.Name}}Search {
 
 type {{.Name}}Summary struct {
 	{{- range .SummaryColumns}}{{if ne .Name "StatusID"}}
-	{{.Name}} {{.GoType}} {{.Tag}} {{.Comment}}{{end}}{{end}}{{if .HasSummaryRelations}}
+	{{with .Attribute.Description}}{{comment .}}
+	{{end}}{{.Name}} {{.GoType}} {{.Tag}} {{.Comment}}{{end}}{{end}}{{if .HasSummaryRelations}}
 	{{range .SummaryRelations}}
 	{{.Name}} *{{.Type}}{{if ne .Name "Status"}}Summary{{end}} {{.Tag}}{{end}}{{end}}
 }{{if .HasParams}}{{range .Params}}
@@ -78,7 +82,9 @@ const converterDefaultTemplate = `package {{.Package}}
 import (
 	"{{.ModelPackage}}"
 )
-{{range $model := .Entities}}
+{{range .Entities}}
+{{template "vt.converter" .}}{{end}}
+{{define "vt.converter"}}{{$model := .}}
 func New{{.Name}}(in *db.{{.Name}}) *{{.Name}} {
 	if in == nil {
 		return nil
@@ -137,36 +143,48 @@ import (
 	"github.com/vmkteam/zenrpc/v2"
 )
 
-{{- range $model := .Entities }}
+{{- range .Entities }}
+{{template "vt.service" dict "Namespace" $ "Entity" .}}{{end}}
+{{define "vt.service"}}{{$ns := .Namespace}}{{with .Entity}}{{$model := .}}
 type {{.Name}}Service struct {
 	zenrpc.Service
 	embedlog.Logger
-	{{$.VarName}}Repo db.{{$.Name}}Repo
-    {{- if .HasRelations }}
+	{{$ns.VarName}}Repo db.{{$ns.Name}}Repo
     {{- range .UniqueRelations }}
-    {{- if ne $.VarName .NameSpace }}
+    {{- if ne $ns.VarName .NameSpace }}
     {{.NameSpace}}Repo db.{{.NameSpace | title}}Repo
     {{- end }}
     {{- end}}
+    {{- if or .HasChildren .HasImport }}
+    dbo db.{{$ns.DBType}}
     {{- end}}
+    {{- if .Audit }}
+    auditRepo db.AuditRepo
+    {{- end}}
+    auth Authorizer
 }
 
-func New{{.Name}}Service(dbo db.DB, logger embedlog.Logger) *{{.Name}}Service {
+func New{{.Name}}Service(dbo db.{{$ns.DBType}}, logger embedlog.Logger) *{{.Name}}Service {
 	return &{{.Name}}Service{
 		Logger:   logger,
-		{{$.VarName}}Repo: db.New{{$.Name}}Repo(dbo),
-        {{- if .HasRelations }}
+		{{$ns.VarName}}Repo: db.New{{$ns.Name}}Repo(dbo),
         {{- range .UniqueRelations }}
-        {{- if ne $.VarName .NameSpace }}
+        {{- if ne $ns.VarName .NameSpace }}
         {{.NameSpace}}Repo: db.New{{.NameSpace | title}}Repo(dbo),
         {{- end }}
         {{- end}}
+        {{- if or .HasChildren .HasImport }}
+        dbo: dbo,
+        {{- end}}
+        {{- if .Audit }}
+        auditRepo: db.NewAuditRepo(dbo),
         {{- end}}
+        auth: DefaultAuthorizer,
 	}
 }
 
 func (s {{.Name}}Service) dbSort(ops *ViewOps) db.OpFunc {
-	v := s.{{$.VarName}}Repo.Default{{.Name}}Sort()
+	v := s.{{$ns.VarName}}Repo.Default{{.Name}}Sort()
 	if ops == nil {
 		return v
 	}{{if .HasSortColumns}}
@@ -179,32 +197,56 @@ func (s {{.Name}}Service) dbSort(ops *ViewOps) db.OpFunc {
 	return v
 }
 
-// Count returns count {{.NamePlural}} according to conditions in search params.
+// scope checks read access to {{.NamePlural}} and applies authorizer filters to search params.
+func (s {{.Name}}Service) scope(ctx context.Context, search *{{.Name}}Search) (*{{.Name}}Search, error) {
+	if !s.auth.CanRead(ctx, "{{.Name}}", "") {
+		return nil, ErrForbidden
+	}
+	if search == nil {
+		search = &{{.Name}}Search{}
+	}
+	if err := s.auth.ScopeSearch(ctx, "{{.Name}}", search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Count returns count {{.NamePlural}} according to conditions in search params.{{template "vt.service.description" .}}
 //
 //zenrpc:search {{.Name}}Search
 //zenrpc:return int
 //zenrpc:500 Internal Error
 func (s {{.Name}}Service) Count(ctx context.Context, search *{{.Name}}Search) (int, error) {
-	count, err := s.{{$.VarName}}Repo.Count{{.NamePlural}}(ctx, search.ToDB())
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.{{$ns.VarName}}Repo.Count{{.NamePlural}}(ctx, search.ToDB())
 	if err != nil {
 		return 0, InternalError(err)
 	}
 	return count, nil
 }
 
-// Get returns а list of {{.NamePlural}} according to conditions in search params.
+// Get returns а list of {{.NamePlural}} according to conditions in search params.{{template "vt.service.description" .}}
 //
 //zenrpc:search {{.Name}}Search
 //zenrpc:viewOps ViewOps
 //zenrpc:return []{{.Name}}Summary
 //zenrpc:500 Internal Error
 func (s {{.Name}}Service) Get(ctx context.Context, search *{{.Name}}Search, viewOps *ViewOps) ([]{{.Name}}Summary, error) {
-	list, err := s.{{$.VarName}}Repo.{{.NamePlural}}ByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.{{$.VarName}}Repo.Full{{.Name}}())
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.{{$ns.VarName}}Repo.{{.NamePlural}}ByFilters(ctx, search.ToDB(), viewOps.Pager(), s.dbSort(viewOps), s.{{$ns.VarName}}Repo.Full{{.Name}}())
 	if err != nil {
 		return nil, InternalError(err)
 	}
 	{{.VarNamePlural}} := make([]{{.Name}}Summary, 0, len(list))
-	for i := 0; i {{$.Raw "<"}} len(list); i++ {
+	for i := 0; i {{$ns.Raw "<"}} len(list); i++ {
 		if {{.VarName}} := New{{.Name}}Summary(&list[i]); {{.VarName}} != nil {
 			{{.VarNamePlural}} = append({{.VarNamePlural}}, *{{.VarName}})
 		}
@@ -212,13 +254,17 @@ func (s {{.Name}}Service) Get(ctx context.Context, search *{{.Name}}Search, view
 	return {{.VarNamePlural}}, nil
 }
 
-// GetByID returns a {{.Name}} by its ID.{{range .PKs}}
+// GetByID returns a {{.Name}} by its ID.{{template "vt.service.description" .}}{{range .PKs}}
 //
 //zenrpc:{{.Arg}} {{.Type}}{{end}}
 //zenrpc:return {{.Name}}
 //zenrpc:500 Internal Error
 //zenrpc:404 Not Found
 func (s {{.Name}}Service) GetByID(ctx context.Context{{range .PKs}}, {{.Arg}} {{.Type}}{{end}}) (*{{.Name}}, error) {
+	if !s.auth.CanRead(ctx, "{{.Name}}", entityID({{range $i, $e := .PKs}}{{if $i}}, {{end}}{{.Arg}}{{end}})) {
+		return nil, ErrForbidden
+	}
+
 	db, err := s.byID(ctx{{range .PKs}}, {{.Arg}}{{end}})
 	if err != nil {
 		return nil, err
@@ -227,34 +273,97 @@ func (s {{.Name}}Service) GetByID(ctx context.Context{{range .PKs}}, {{.Arg}} {{
 }
 
 func (s {{.Name}}Service) byID(ctx context.Context{{range .PKs}}, {{.Arg}} {{.Type}}{{end}}) (*db.{{.Name}}, error) {
-	db, err := s.{{$.VarName}}Repo.{{.Name}}ByID(ctx{{range .PKs}}, {{.Arg}}{{end}}, s.{{$.VarName}}Repo.Full{{.Name}}())
+	db, err := s.{{$ns.VarName}}Repo.{{.Name}}ByID(ctx{{range .PKs}}, {{.Arg}}{{end}}, s.{{$ns.VarName}}Repo.Full{{.Name}}())
 	if err != nil {
 		return nil, InternalError(err)
 	} else if db == nil {
 		return nil, ErrNotFound
 	}
 	return db, nil
-}{{if not .ReadOnly}}
+}{{if .Audit}}
+
+// History returns changes of the {{.Name}} from audit log, newest first.{{template "vt.service.description" .}}{{range .PKs}}
+//
+//zenrpc:{{.Arg}} {{.Type}}{{end}}
+//zenrpc:return []AuditLog
+//zenrpc:500 Internal Error
+func (s {{.Name}}Service) History(ctx context.Context{{range .PKs}}, {{.Arg}} {{.Type}}{{end}}) ([]AuditLog, error) {
+	if !s.auth.CanRead(ctx, "{{.Name}}", entityID({{range $i, $e := .PKs}}{{if $i}}, {{end}}{{.Arg}}{{end}})) {
+		return nil, ErrForbidden
+	}
+
+	list, err := s.auditRepo.AuditLogs(ctx, "{{.Name}}", entityID({{range $i, $e := .PKs}}{{if $i}}, {{end}}{{.Arg}}{{end}}), db.PagerNoLimit)
+	if err != nil {
+		return nil, InternalError(err)
+	}
+	auditLogs := make([]AuditLog, 0, len(list))
+	for i := range list {
+		if auditLog := NewAuditLog(&list[i]); auditLog != nil {
+			auditLogs = append(auditLogs, *auditLog)
+		}
+	}
+	return auditLogs, nil
+}{{end}}{{if .ImportExport}}
+
+// Export returns a csv or xlsx file with {{.NamePlural}} according to conditions in search params.{{template "vt.service.description" .}}
+//
+//zenrpc:search {{.Name}}Search
+//zenrpc:format file format: csv or xlsx
+//zenrpc:return ExportFile
+//zenrpc:500 Internal Error
+//zenrpc:400 Unsupported Format
+func (s {{.Name}}Service) Export(ctx context.Context, search *{{.Name}}Search, format string) (*ExportFile, error) {
+	search, err := s.scope(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.{{$ns.VarName}}Repo.{{.NamePlural}}ByFilters(ctx, search.ToDB(), db.PagerNoLimit, s.dbSort(nil), s.{{$ns.VarName}}Repo.Full{{.Name}}())
+	if err != nil {
+		return nil, InternalError(err)
+	}
+
+	header := []string{ {{range $i, $e := .ExportColumns}}{{if $i}}, {{end}}"{{.JSONName}}"{{end}} }
+	rows := make([][]string, 0, len(list))
+	for i := range list {
+		if {{.VarName}} := New{{.Name}}(&list[i]); {{.VarName}} != nil {
+			rows = append(rows, []string{
+			{{- range .ExportColumns}}
+				exportValue({{$model.VarName}}.{{.Field}}),
+			{{- end}}
+			})
+		}
+	}
+	return NewExportFile("{{.VarNamePlural}}", format, header, rows)
+}{{end}}{{if not .ReadOnly}}
 
-// Add adds a {{.Name}} from the query.
+// Add adds a {{.Name}} from the query.{{template "vt.service.description" .}}
 //
 //zenrpc:{{.VarName}} {{.Name}}
 //zenrpc:return {{.Name}}
 //zenrpc:500 Internal Error
 //zenrpc:400 Validation Error
 func (s {{.Name}}Service) Add(ctx context.Context, {{.VarName}} {{.Name}}) (*{{.Name}}, error) {
+	if !s.auth.CanWrite(ctx, "{{.Name}}", "") {
+		return nil, ErrForbidden
+	}
+
 	if ve := s.isValid(ctx, {{.VarName}}, false); ve.HasErrors() {
 		return nil, ve.Error()
 	}
 
-	db, err := s.{{$.VarName}}Repo.Add{{.Name}}(ctx, {{.VarName}}.ToDB())
+	db, err := s.{{$ns.VarName}}Repo.Add{{.Name}}(ctx, {{.VarName}}.ToDB())
 	if err != nil {
 		return nil, InternalError(err)
-	}
-	return New{{.Name}}(db), nil
+	}{{if .Audit}}
+
+	added := New{{.Name}}(db)
+	s.audit(ctx, AuditActionAdd, nil, added)
+	return added, nil{{else}}
+	return New{{.Name}}(db), nil{{end}}
 }
 
-// Update updates the {{.Name}} data identified by id from the query.
+// Update updates the {{.Name}} data identified by id from the query.{{template "vt.service.description" .}}
 //
 //zenrpc:{{.VarNamePlural}} {{.Name}}
 //zenrpc:return {{.Name}}
@@ -262,22 +371,36 @@ func (s {{.Name}}Service) Add(ctx context.Context, {{.VarName}} {{.Name}}) (*{{.
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s {{.Name}}Service) Update(ctx context.Context, {{.VarName}} {{.Name}}) (bool, error) {
+	if !s.auth.CanWrite(ctx, "{{.Name}}", entityID({{range $i, $e := .PKs}}{{if $i}}, {{end}}{{$model.VarName}}.{{.Field}}{{end}})) {
+		return false, ErrForbidden
+	}
+{{if .Audit}}
+	old, err := s.byID(ctx{{range .PKs}}, {{$model.VarName}}.{{.Field}}{{end}})
+	if err != nil {
+		return false, err
+	}
+	{{- else}}
 	if _, err := s.byID(ctx{{range .PKs}}, {{$model.VarName}}.{{.Field}}{{end}}); err != nil {
 		return false, err
 	}
+	{{- end}}
 
 	if ve := s.isValid(ctx, {{.VarName}}, true); ve.HasErrors() {
 		return false, ve.Error()
 	}
 
-	ok, err := s.{{$.VarName}}Repo.Update{{.Name}}(ctx, {{.VarName}}.ToDB())
+	ok, err := s.{{$ns.VarName}}Repo.Update{{.Name}}(ctx, {{.VarName}}.ToDB())
 	if err != nil {
 		return false, InternalError(err)
-	}
+	}{{if .Audit}}
+
+	if ok {
+		s.audit(ctx, AuditActionUpdate, New{{.Name}}(old), &{{.VarName}})
+	}{{end}}
 	return ok, nil
 }
 
-// Delete deletes the {{.Name}} by its ID.{{range .PKs}}
+// Delete deletes the {{.Name}} by its ID.{{template "vt.service.description" .}}{{range .PKs}}
 //
 //zenrpc:{{.Arg}} {{.Type}}{{end}}
 //zenrpc:return isDeleted
@@ -285,24 +408,46 @@ func (s {{.Name}}Service) Update(ctx context.Context, {{.VarName}} {{.Name}}) (b
 //zenrpc:400 Validation Error
 //zenrpc:404 Not Found
 func (s {{.Name}}Service) Delete(ctx context.Context{{range .PKs}}, {{.Arg}} {{.Type}}{{end}}) (bool, error) {
+	if !s.auth.CanWrite(ctx, "{{.Name}}", entityID({{range $i, $e := .PKs}}{{if $i}}, {{end}}{{.Arg}}{{end}})) {
+		return false, ErrForbidden
+	}
+{{if .Audit}}
+	old, err := s.byID(ctx{{range .PKs}}, {{.Arg}}{{end}})
+	if err != nil {
+		return false, err
+	}
+	{{- else}}
 	if _, err := s.byID(ctx{{range .PKs}}, {{.Arg}}{{end}}); err != nil {
 		return false, err
 	}
+	{{- end}}
 
-	ok, err := s.{{$.VarName}}Repo.Delete{{.Name}}(ctx{{range .PKs}}, {{.Arg}}{{end}})
+	ok, err := s.{{$ns.VarName}}Repo.Delete{{.Name}}(ctx{{range .PKs}}, {{.Arg}}{{end}})
 	if err != nil {
 		return false, InternalError(err)
-	}
+	}{{if .Audit}}
+
+	if ok {
+		s.audit(ctx, AuditActionDelete, New{{.Name}}(old), nil)
+	}{{end}}
 	return ok, err
 }
 
-// Validate verifies that {{.Name}} data is valid.
+// Validate verifies that {{.Name}} data is valid.{{template "vt.service.description" .}}
 //
 //zenrpc:{{.VarName}} {{.Name}}
 //zenrpc:return []FieldError
 //zenrpc:500 Internal Error
 func (s {{.Name}}Service) Validate(ctx context.Context, {{.VarName}} {{.Name}}) ([]FieldError, error) {
 	isUpdate := {{range $i, $e := .PKs}}{{if $i}} && {{end}} {{$model.VarName}}.{{.Field}} != {{.Zero}} {{end}}
+	id := ""
+	if isUpdate {
+		id = entityID({{range $i, $e := .PKs}}{{if $i}}, {{end}}{{$model.VarName}}.{{.Field}}{{end}})
+	}
+	if !s.auth.CanWrite(ctx, "{{.Name}}", id) {
+		return nil, ErrForbidden
+	}
+
 	if isUpdate {
 		_, err := s.byID(ctx{{range .PKs}}, {{$model.VarName}}.{{.Field}}{{end}})
 		if err != nil {
@@ -331,7 +476,7 @@ func (s {{.Name}}Service) isValid(ctx context.Context, {{.VarName}} {{.Name}}, i
 		{{.AliasArg}}: &{{$model.VarName}}.{{.AliasField}},{{range .PKSearches}}
 		{{.Arg}}: &{{$model.VarName}}.{{.Field}},{{end}}
 	}
-	item, err := s.{{$.VarName}}Repo.One{{.Name}}(ctx, search)
+	item, err := s.{{$ns.VarName}}Repo.One{{.Name}}(ctx, search)
 	if err != nil {
 		v.SetInternalError(err)
 	} else if item != nil {
@@ -359,10 +504,742 @@ func (s {{.Name}}Service) isValid(ctx context.Context, {{.VarName}} {{.Name}}, i
 	}
 	{{end}}{{end}}{{end}}
 	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
 	return v
 }
+{{if .Audit}}
+// audit writes changed fields of the {{.Name}} to audit log.
+func (s {{.Name}}Service) audit(ctx context.Context, action string, prev, next *{{.Name}}) {
+	item := next
+	if item == nil {
+		item = prev
+	}
+
+	fields := []string{ {{range $i, $e := .AuditFields}}{{if $i}}, {{end}}"{{.}}"{{end}} }
+	auditLog, err := newAuditLog(ctx, "{{.Name}}", action, entityID({{range $i, $e := .PKs}}{{if $i}}, {{end}}item.{{.Field}}{{end}}), fields, prev, next)
+	if err == nil {
+		_, err = s.auditRepo.AddAuditLog(ctx, auditLog)
+	}
+	if err != nil {
+		s.Error(ctx, "write audit log failed", "entity", "{{.Name}}", "action", action, "err", err)
+	}
+}
+{{end}}{{range .Children}}
+// Get{{.Name}} returns {{.EntityPlural}} of the {{$model.Name}} by its ID.
+//
+//zenrpc:{{.ParentPK.Arg}} {{.ParentPK.Type}}
+//zenrpc:return []{{.Entity}}
+//zenrpc:500 Internal Error
+//zenrpc:404 Not Found
+func (s {{$model.Name}}Service) Get{{.Name}}(ctx context.Context, {{.ParentPK.Arg}} {{.ParentPK.Type}}) ([]{{.Entity}}, error) {
+	if !s.auth.CanRead(ctx, "{{$model.Name}}", entityID({{.ParentPK.Arg}})) {
+		return nil, ErrForbidden
+	}
+
+	if _, err := s.byID(ctx, {{.ParentPK.Arg}}); err != nil {
+		return nil, err
+	}
+
+	_, list, err := s.scope{{.Name}}(ctx, {{.ParentPK.Arg}})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]{{.Entity}}, 0, len(list))
+	for i := range list {
+		if item := New{{.Entity}}(&list[i]); item != nil {
+			items = append(items, *item)
+		}
+	}
+	return items, nil
+}
+
+// Save{{.Name}} replaces {{.EntityPlural}} of the {{$model.Name}}: new items are added, existing are updated, missing are deleted.
+// {{.EntityPlural}} hidden from the current user by {{.Entity}} authorizer scope are kept as is.
+// Primary keys of updated items should be unique and belong to {{.EntityPlural}} of the {{$model.Name}}.
+//
+//zenrpc:{{.ParentPK.Arg}} {{.ParentPK.Type}}
+//zenrpc:items []{{.Entity}}
+//zenrpc:return []{{.Entity}}
+//zenrpc:500 Internal Error
+//zenrpc:400 Validation Error
+//zenrpc:404 Not Found
+func (s {{$model.Name}}Service) Save{{.Name}}(ctx context.Context, {{.ParentPK.Arg}} {{.ParentPK.Type}}, items []{{.Entity}}) ([]{{.Entity}}, error) {
+	if !s.auth.CanWrite(ctx, "{{$model.Name}}", entityID({{.ParentPK.Arg}})) {
+		return nil, ErrForbidden
+	}
+
+	if _, err := s.byID(ctx, {{.ParentPK.Arg}}); err != nil {
+		return nil, err
+	}
+
+	{{.VarName}}Service, list, err := s.scope{{.Name}}(ctx, {{.ParentPK.Arg}})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[{{.PK.Type}}]*db.{{.Entity}}, len(list))
+	for i := range list {
+		existing[list[i].{{.PK.Field}}] = &list[i]
+	}
+
+	// updated items should be unique children of the {{$model.Name}}
+	var v Validator
+	updated := make(map[{{.PK.Type}}]struct{}, len(items))
+	for i := range items {
+		childID := items[i].{{.PK.Field}}
+		if childID == {{.PK.Zero}} {
+			continue
+		}
+		if _, ok := existing[childID]; !ok {
+			v.Append("{{.PK.Arg}}", FieldErrorIncorrect)
+		} else if _, ok := updated[childID]; ok {
+			v.Append("{{.PK.Arg}}", FieldErrorUnique)
+		}
+		updated[childID] = struct{}{}
+	}
+	if v.HasErrors() {
+		return nil, v.Error()
+	}
+
+	// check access and validate all items before saving
+	for i := range items {
+		items[i].{{.FK}} = {{if .Nullable}}&{{end}}{{.ParentPK.Arg}}
+		isUpdate := items[i].{{.PK.Field}} != {{.PK.Zero}}
+		childID := ""
+		if isUpdate {
+			childID = entityID(items[i].{{.PK.Field}})
+		}
+		if !s.auth.CanWrite(ctx, "{{.Entity}}", childID) {
+			return nil, ErrForbidden
+		}
+		if ve := {{.VarName}}Service.isValid(ctx, items[i], isUpdate); ve.HasErrors() {
+			return nil, ve.Error()
+		}
+	}
+
+	// missing items are deleted
+	deleted := make(map[{{.PK.Type}}]struct{}, len(existing))
+	for childID := range existing {
+		deleted[childID] = struct{}{}
+	}
+	for i := range items {
+		delete(deleted, items[i].{{.PK.Field}})
+	}
+	for childID := range deleted {
+		if !s.auth.CanWrite(ctx, "{{.Entity}}", entityID(childID)) {
+			return nil, ErrForbidden
+		}
+	}
+{{if .Audit}}
+	var changes []auditChange[{{.Entity}}]{{end}}
+	err = s.dbo.RunInTransaction({{if .TxContext}}ctx, {{end}}func(tx *pg.Tx) error {
+		repo := s.{{.NameSpace}}Repo.WithTransaction(tx)
+		for i := range items {
+			if {{if .Audit}}old{{else}}_{{end}}, ok := existing[items[i].{{.PK.Field}}]; ok {
+				if _, err := repo.Update{{.Entity}}(ctx, items[i].ToDB()); err != nil {
+					return err
+				}{{if .Audit}}
+				changes = append(changes, auditChange[{{.Entity}}]{action: AuditActionUpdate, prev: New{{.Entity}}(old), next: &items[i]}){{end}}
+				continue
+			}
+			{{if .Audit}}added{{else}}_{{end}}, err := repo.Add{{.Entity}}(ctx, items[i].ToDB())
+			if err != nil {
+				return err
+			}{{if .Audit}}
+			changes = append(changes, auditChange[{{.Entity}}]{action: AuditActionAdd, next: New{{.Entity}}(added)}){{end}}
+		}
+		for childID := range deleted {
+			if _, err := repo.Delete{{.Entity}}(ctx, childID); err != nil {
+				return err
+			}{{if .Audit}}
+			changes = append(changes, auditChange[{{.Entity}}]{action: AuditActionDelete, prev: New{{.Entity}}(existing[childID])}){{end}}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, InternalError(err)
+	}{{if .Audit}}
+
+	for _, change := range changes {
+		{{.VarName}}Service.audit(ctx, change.action, change.prev, change.next)
+	}{{end}}
+
+	return s.Get{{.Name}}(ctx, {{.ParentPK.Arg}})
+}
+
+// scope{{.Name}} returns {{.EntityPlural}} of the {{$model.Name}} available to the current user by {{.Entity}} authorizer
+// and {{.Entity}} service which uses authorizer of the {{$model.Name}} service.
+func (s {{$model.Name}}Service) scope{{.Name}}(ctx context.Context, {{.ParentPK.Arg}} {{.ParentPK.Type}}) (*{{.Entity}}Service, []db.{{.Entity}}, error) {
+	{{.VarName}}Service := New{{.Entity}}Service(s.dbo, s.Logger)
+	{{.VarName}}Service.auth = s.auth
+
+	search, err := {{.VarName}}Service.scope(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbSearch := search.ToDB()
+	dbSearch.{{.FK}} = &{{.ParentPK.Arg}}
+
+	list, err := s.{{.NameSpace}}Repo.{{.EntityPlural}}ByFilters(ctx, dbSearch, db.PagerNoLimit)
+	if err != nil {
+		return nil, nil, InternalError(err)
+	}
+	return {{.VarName}}Service, list, nil
+}
+{{end}}{{if .HasImport}}
+// Import adds or updates {{.NamePlural}} from a csv or xlsx file, rows with filled primary key are updated.
+// Only columns present in the file are updated, other columns of existing {{.NamePlural}} are kept.
+// The file is saved in a transaction if all rows are valid, otherwise nothing is saved and errors of rows are returned.{{template "vt.service.description" .}}
+//
+//zenrpc:file ImportFile
+//zenrpc:return ImportResult
+//zenrpc:500 Internal Error
+//zenrpc:400 Invalid File
+func (s {{.Name}}Service) Import(ctx context.Context, file ImportFile) (*ImportResult, error) {
+	if !s.auth.CanWrite(ctx, "{{.Name}}", "") {
+		return nil, ErrForbidden
+	}
+
+	rows, err := file.Rows()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	items := make([]{{.Name}}, 0, len(rows))
+	olds := make([]*db.{{.Name}}, 0, len(rows))
+	for i, row := range rows {
+		// first line in file is a header
+		line := i + 2
+
+		var {{.VarName}} {{.Name}}
+		if err := decodeRow(row, &{{.VarName}}); err != nil {
+			result.Errors = append(result.Errors, ImportError{Row: line, Message: err.Error()})
+			continue
+		}
+
+		// old is nil for added rows
+		var old *db.{{.Name}}
+		isUpdate := {{range $i, $e := .PKs}}{{if $i}} && {{end}} {{$model.VarName}}.{{.Field}} != {{.Zero}} {{end}}
+		if isUpdate {
+			if !s.auth.CanWrite(ctx, "{{.Name}}", entityID({{range $i, $e := .PKs}}{{if $i}}, {{end}}{{$model.VarName}}.{{.Field}}{{end}})) {
+				result.Errors = append(result.Errors, ImportError{Row: line, Message: ErrForbidden.Error()})
+				continue
+			}
+			if old, err = s.byID(ctx{{range .PKs}}, {{$model.VarName}}.{{.Field}}{{end}}); errors.Is(err, ErrNotFound) {
+				result.Errors = append(result.Errors, ImportError{Row: line, Message: err.Error()})
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			// columns of the file overlay the existing row
+			{{.VarName}} = *New{{.Name}}(old)
+			if err := decodeRow(row, &{{.VarName}}); err != nil {
+				result.Errors = append(result.Errors, ImportError{Row: line, Message: err.Error()})
+				continue
+			}
+		}
+
+		ve := s.isValid(ctx, {{.VarName}}, isUpdate)
+		if ve.HasInternalError() {
+			return nil, ve.Error()
+		} else if ve.HasErrors() {
+			result.Errors = append(result.Errors, ImportError{Row: line, Fields: ve.Fields()})
+			continue
+		}
+
+		items = append(items, {{.VarName}})
+		olds = append(olds, old)
+	}
+
+	// file with invalid rows is not imported
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+{{if .Audit}}
+	var changes []auditChange[{{.Name}}]{{end}}
+	err = s.dbo.RunInTransaction({{if .TxContext}}ctx, {{end}}func(tx *pg.Tx) error {
+		repo := s.{{$ns.VarName}}Repo.WithTransaction(tx)
+		for i := range items {
+			if olds[i] != nil {
+				if _, err := repo.Update{{.Name}}(ctx, items[i].ToDB()); err != nil {
+					return err
+				}{{if .Audit}}
+				changes = append(changes, auditChange[{{.Name}}]{action: AuditActionUpdate, prev: New{{.Name}}(olds[i]), next: &items[i]}){{end}}
+				result.Updated++
+				continue
+			}
+			{{if .Audit}}added{{else}}_{{end}}, err := repo.Add{{.Name}}(ctx, items[i].ToDB())
+			if err != nil {
+				return err
+			}{{if .Audit}}
+			changes = append(changes, auditChange[{{.Name}}]{action: AuditActionAdd, next: New{{.Name}}(added)}){{end}}
+			result.Added++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, InternalError(err)
+	}{{if .Audit}}
+
+	for _, change := range changes {
+		s.audit(ctx, change.action, change.prev, change.next)
+	}{{end}}
+
+	return result, nil
+}
+{{end}}
+{{end}}{{end}}{{end}}
+{{define "vt.service.description"}}{{with .Description}}
+//
+{{comment .}}{{end}}{{end}}`
+
+const exportDefaultTemplate = `package {{.Package}}
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmkteam/zenrpc/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var (
+	ErrUnsupportedFormat = zenrpc.NewStringError(http.StatusBadRequest, "unsupported file format")
+	ErrInvalidFile       = zenrpc.NewStringError(http.StatusBadRequest, "invalid file")
+)
+
+// ExportFile is a file with exported entities.
+type ExportFile struct {
+	Name        string ` + "`json:\"name\"`" + `
+	ContentType string ` + "`json:\"contentType\"`" + `
+	Content     []byte ` + "`json:\"content\"`" + `
+}
+
+// ImportFile is a csv or xlsx file with entities, first row is a header with field names.
+type ImportFile struct {
+	Name    string ` + "`json:\"name\"`" + `
+	Format  string ` + "`json:\"format\" validate:\"required,oneof=csv xlsx\"`" + `
+	Content []byte ` + "`json:\"content\" validate:\"required\"`" + `
+}
+
+// ImportResult stores import stats and errors of rows that were not imported.
+type ImportResult struct {
+	Added   int           ` + "`json:\"added\"`" + `
+	Updated int           ` + "`json:\"updated\"`" + `
+	Errors  []ImportError ` + "`json:\"errors\"`" + `
+}
+
+// ImportError stores error of a row in imported file.
+type ImportError struct {
+	Row     int          ` + "`json:\"row\"`" + `
+	Message string       ` + "`json:\"message,omitempty\"`" + `
+	Fields  []FieldError ` + "`json:\"fields,omitempty\"`" + `
+}
+
+// NewExportFile writes header and rows to csv or xlsx file.
+func NewExportFile(name, format string, header []string, rows [][]string) (*ExportFile, error) {
+	var buf bytes.Buffer
+	file := &ExportFile{Name: name + "." + format}
+
+	switch format {
+	case FormatCSV:
+		file.ContentType = "text/csv"
+		w := csv.NewWriter(&buf)
+		if err := w.Write(header); err != nil {
+			return nil, InternalError(err)
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return nil, InternalError(err)
+		}
+	case FormatXLSX:
+		file.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		f := excelize.NewFile()
+		defer f.Close()
+
+		sheet := f.GetSheetName(0)
+		for i, row := range append([][]string{header}, rows...) {
+			if err := f.SetSheetRow(sheet, "A"+strconv.Itoa(i+1), &row); err != nil {
+				return nil, InternalError(err)
+			}
+		}
+		if _, err := f.WriteTo(&buf); err != nil {
+			return nil, InternalError(err)
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	file.Content = buf.Bytes()
+	return file, nil
+}
+
+// Rows reads file rows as maps with header values as keys.
+func (f ImportFile) Rows() ([]map[string]string, error) {
+	var lines [][]string
+	switch f.Format {
+	case FormatCSV:
+		r := csv.NewReader(bytes.NewReader(f.Content))
+		r.FieldsPerRecord = -1
+
+		var err error
+		if lines, err = r.ReadAll(); err != nil {
+			return nil, ErrInvalidFile
+		}
+	case FormatXLSX:
+		xlsx, err := excelize.OpenReader(bytes.NewReader(f.Content))
+		if err != nil {
+			return nil, ErrInvalidFile
+		}
+		defer xlsx.Close()
+
+		if lines, err = xlsx.GetRows(xlsx.GetSheetName(0)); err != nil {
+			return nil, ErrInvalidFile
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrInvalidFile
+	}
+
+	header, rows := lines[0], make([]map[string]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		row := make(map[string]string, len(header))
+		for i, value := range line {
+			if i {{.Raw "<"}} len(header) {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// exportValue converts model field value to string.
+func exportValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+
+	switch val := rv.Interface().(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339)
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		if rv.IsNil() {
+			return ""
+		}
+		fallthrough
+	case reflect.Struct, reflect.Array:
+		b, err := json.Marshal(rv.Interface())
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+
+	return fmt.Sprint(rv.Interface())
+}
+
+// decodeRow fills model fields from row values by their json names.
+func decodeRow(row map[string]string, v interface{}) error {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for i := 0; i {{.Raw "<"}} rt.NumField(); i++ {
+		name, _, _ := strings.Cut(rt.Field(i).Tag.Get("json"), ",")
+		raw, ok := row[name]
+		if !ok || raw == "" || name == "-" {
+			continue
+		}
+
+		if err := setValue(rv.Field(i), raw); err != nil {
+			return fmt.Errorf("invalid %s value: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// setValue sets string value to field according to its type.
+func setValue(fv reflect.Value, raw string) error {
+	if fv.Kind() == reflect.Ptr {
+		pv := reflect.New(fv.Type().Elem())
+		if err := setValue(pv.Elem(), raw); err != nil {
+			return err
+		}
+		fv.Set(pv)
+		return nil
+	}
+
+	if _, ok := fv.Interface().(time.Time); ok {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	default:
+		return json.Unmarshal([]byte(raw), fv.Addr().Interface())
+	}
+
+	return nil
+}
+`
+
+const authorizerDefaultTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmkteam/zenrpc/v2"
+)
+
+var ErrForbidden = zenrpc.NewStringError(http.StatusForbidden, "forbidden")
+
+// Authorizer checks access of the current user to vt entities.
+type Authorizer interface {
+	// CanRead checks if entity can be read, id is empty for lists.
+	CanRead(ctx context.Context, entity, id string) bool
 
-{{end}}{{end}}`
+	// CanWrite checks if entity can be added, updated or deleted, id is empty for new entities.
+	CanWrite(ctx context.Context, entity, id string) bool
+
+	// ScopeSearch adds filters to entity search params, e.g. *NewsSearch, before querying db.
+	ScopeSearch(ctx context.Context, entity string, search interface{}) error
+}
+
+// DefaultAuthorizer is used by services, replace it on application start before creating services.
+var DefaultAuthorizer Authorizer = AllowAll{}
+
+// AllowAll allows any user to read and write every entity.
+type AllowAll struct{}
+
+func (AllowAll) CanRead(context.Context, string, string) bool { return true }
+
+func (AllowAll) CanWrite(context.Context, string, string) bool { return true }
+
+func (AllowAll) ScopeSearch(context.Context, string, interface{}) error { return nil }
+
+// EntityRoles stores roles required to access entities, entities without roles are available to any user.
+var EntityRoles = map[string][]string{ {{range .Roles}}
+	"{{.Entity}}": { {{range $i, $e := .Roles}}{{if $i}}, {{end}}"{{.}}"{{end}} },{{end}}
+}
+
+// RoleAuthorizer allows access to entities for users with one of EntityRoles.
+type RoleAuthorizer struct {
+	AllowAll
+
+	// UserRoles returns roles of the current user.
+	UserRoles func(ctx context.Context) []string
+}
+
+func (a RoleAuthorizer) CanRead(ctx context.Context, entity, _ string) bool {
+	return a.hasRole(ctx, entity)
+}
+
+func (a RoleAuthorizer) CanWrite(ctx context.Context, entity, _ string) bool {
+	return a.hasRole(ctx, entity)
+}
+
+func (a RoleAuthorizer) hasRole(ctx context.Context, entity string) bool {
+	roles, ok := EntityRoles[entity]
+	if !ok {
+		return true
+	}
+
+	for _, userRole := range a.UserRoles(ctx) {
+		for _, role := range roles {
+			if role == userRole {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// entityID joins primary keys of entity to a single string.
+func entityID(pks ...interface{}) string {
+	ids := make([]string, len(pks))
+	for i, pk := range pks {
+		ids[i] = fmt.Sprint(pk)
+	}
+	return strings.Join(ids, ",")
+}
+`
+
+const auditDefaultTemplate = `package {{.Package}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"{{.ModelPackage}}"
+)
+
+const (
+	AuditActionAdd    = db.AuditActionAdd
+	AuditActionUpdate = db.AuditActionUpdate
+	AuditActionDelete = db.AuditActionDelete
+)
+
+type auditUserKey struct{}
+
+// WithAuditUser returns context with id of the current user, call it in auth middleware of http handler or zenrpc server.
+func WithAuditUser(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, auditUserKey{}, userID)
+}
+
+// AuditUserID returns id of the current user for audit log, nil if unknown.
+// By default user is read from context set by WithAuditUser, replace it on application start to read user from your auth context.
+var AuditUserID = func(ctx context.Context) *int {
+	if userID, ok := ctx.Value(auditUserKey{}).(int); ok {
+		return &userID
+	}
+	return nil
+}
+
+// auditChange is a change of entity made in transaction, it is written to audit log after commit.
+type auditChange[T any] struct {
+	action     string
+	prev, next *T
+}
+
+// AuditLog is a single change of entity.
+type AuditLog struct {
+	ID        int                       ` + "`json:\"id\"`" + `
+	Action    string                    ` + "`json:\"action\"`" + `
+	UserID    *int                      ` + "`json:\"userId\"`" + `
+	Changes   map[string]db.AuditChange ` + "`json:\"changes\"`" + `
+	CreatedAt time.Time                 ` + "`json:\"createdAt\"`" + `
+}
+
+func NewAuditLog(in *db.AuditLog) *AuditLog {
+	if in == nil {
+		return nil
+	}
+
+	auditLog := &AuditLog{
+		ID:        in.ID,
+		Action:    in.Action,
+		UserID:    in.UserID,
+		CreatedAt: in.CreatedAt,
+	}
+	if len(in.Changes) > 0 {
+		_ = json.Unmarshal(in.Changes, &auditLog.Changes)
+	}
+
+	return auditLog
+}
+
+// newAuditLog creates audit log with changed fields of prev and next entities.
+func newAuditLog(ctx context.Context, entity, action, entityID string, fields []string, prev, next interface{}) (*db.AuditLog, error) {
+	prevFields, err := auditFields(prev)
+	if err != nil {
+		return nil, err
+	}
+	nextFields, err := auditFields(next)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]db.AuditChange)
+	for _, field := range fields {
+		oldValue, newValue := prevFields[field], nextFields[field]
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		changes[field] = db.AuditChange{Old: oldValue, New: newValue}
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &db.AuditLog{
+		Entity:   entity,
+		EntityID: entityID,
+		Action:   action,
+		UserID:   AuditUserID(ctx),
+		Changes:  b,
+	}, nil
+}
+
+// auditFields returns json encoded fields of entity by their json names.
+func auditFields(v interface{}) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return fields, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, json.Unmarshal(b, &fields)
+}
+`
 
 const serverDefaultTemplate = `
 	Put this into your server code:
@@ -372,8 +1249,11 @@ const serverDefaultTemplate = `
 		NSUser = "user"
 
 		{{range .Entities}}
-		NS{{.Name}} = "{{.VarName}}"{{end}}
+		NS{{.Name}} = "{{.VarName}}"{{if .RoleNames}} // roles: {{range $i, $e := .RoleNames}}{{if $i}}, {{end}}{{.}}{{end}}{{end}}{{end}}
 	)
+
+	// access checks, RoleAuthorizer uses roles from EntityRoles
+	DefaultAuthorizer = RoleAuthorizer{UserRoles: userRoles}
 	
 	// services
 	rpc.RegisterAll(map[string]zenrpc.Invoker{
@@ -381,9 +1261,16 @@ const serverDefaultTemplate = `
 		NSUser: NewUserService(dbo, logger),
 
 		{{range .Entities}}
-		NS{{.Name}}: New{{.Name}}Service(dbo, logger),{{end}}
+		NS{{.Name}}: New{{.Name}}Service({{.DBVar}}, logger),{{end}}
 	})
 `
 
+// StructPattern matched structs replaced in existing files.
+//
+// Deprecated: declarations are found with go/ast, pattern is not used by generator.
 const StructPattern = `type (\w+) struct {`
+
+// FuncPattern matched functions replaced in existing files.
+//
+// Deprecated: declarations are found with go/ast, pattern is not used by generator.
 const FuncPattern = `^func (\w+)`
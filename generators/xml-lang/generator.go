@@ -1,6 +1,8 @@
 package xmllang
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vmkteam/mfd-generator/mfd"
@@ -24,11 +26,30 @@ func CreateCommand() *cobra.Command {
 // Generator represents mfd generator
 type Generator struct {
 	options Options
+	files   *mfd.Files
 }
 
 // New creates generator
 func New() *Generator {
-	return &Generator{}
+	return &Generator{files: mfd.NewFiles(nil)}
+}
+
+// Runner runs xml-lang generator without cobra command
+type Runner = mfd.Runner[Options]
+
+// NewGenerator creates xml-lang generator for usage as a library
+func NewGenerator(options Options) *Runner {
+	return mfd.NewRunner(options, nil, run)
+}
+
+// run runs generator with files if required options are set
+func run(ctx context.Context, options Options, files *mfd.Files) error {
+	if options.MFDPath == "" {
+		return errors.New("mfd file is required")
+	}
+
+	g := &Generator{options: options, files: files}
+	return g.generate(ctx)
 }
 
 // AddFlags adds flags to command
@@ -74,6 +95,10 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 
 // Generate runs generator
 func (g *Generator) Generate() error {
+	return g.generate(context.Background())
+}
+
+func (g *Generator) generate(ctx context.Context) error {
 	// loading project from file
 	project, err := mfd.LoadProject(g.options.MFDPath, false, 0)
 	if err != nil {
@@ -99,6 +124,10 @@ func (g *Generator) Generate() error {
 
 	for lang, translation := range translations {
 		for _, namespace := range g.options.Namespaces {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			ns := project.VTNamespace(namespace)
 			if ns == nil {
 				return fmt.Errorf("namespace %s not found", namespace)
@@ -111,14 +140,14 @@ func (g *Generator) Generate() error {
 
 			translation = Translate(ns, translation, entities, lang)
 
-			if err := mfd.SaveTranslation(translation, g.options.MFDPath, lang); err != nil {
+			if err := g.files.SaveTranslation(translation, g.options.MFDPath, lang); err != nil {
 				return fmt.Errorf("save translation lang %s, err=%w", lang, err)
 			}
 		}
 	}
 
 	project.Languages = langs
-	return mfd.SaveMFD(g.options.MFDPath, project)
+	return g.files.SaveMFD(g.options.MFDPath, project)
 }
 
 func mergeLangs(project, input []string) []string {
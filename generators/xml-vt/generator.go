@@ -1,6 +1,8 @@
 package xmlvt
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vmkteam/mfd-generator/mfd"
@@ -23,11 +25,30 @@ func CreateCommand() *cobra.Command {
 // Generator represents mfd generator
 type Generator struct {
 	options Options
+	files   *mfd.Files
 }
 
 // New creates generator
 func New() *Generator {
-	return &Generator{}
+	return &Generator{files: mfd.NewFiles(nil)}
+}
+
+// Runner runs xml-vt generator without cobra command
+type Runner = mfd.Runner[Options]
+
+// NewGenerator creates xml-vt generator for usage as a library
+func NewGenerator(options Options) *Runner {
+	return mfd.NewRunner(options, nil, run)
+}
+
+// run runs generator with files if required options are set
+func run(ctx context.Context, options Options, files *mfd.Files) error {
+	if options.MFDPath == "" {
+		return errors.New("mfd file is required")
+	}
+
+	g := &Generator{options: options, files: files}
+	return g.generate(ctx)
 }
 
 // AddFlags adds flags to command
@@ -67,6 +88,10 @@ func (g *Generator) ReadFlags(command *cobra.Command) error {
 
 // Generate runs generator
 func (g *Generator) Generate() error {
+	return g.generate(context.Background())
+}
+
+func (g *Generator) generate(ctx context.Context) error {
 	// loading project from file
 	project, err := mfd.LoadProject(g.options.MFDPath, false, 0)
 	if err != nil {
@@ -84,6 +109,10 @@ func (g *Generator) Generate() error {
 
 	// adding vt entities to project
 	for _, namespace := range g.options.Namespaces {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		ns := project.Namespace(namespace)
 		if ns == nil {
 			return fmt.Errorf("namespace %s not found", namespace)
@@ -102,10 +131,10 @@ func (g *Generator) Generate() error {
 
 			exitsting := project.VTEntity(name)
 
-			project.AddVTEntity(namespace, PackVTEntity(entity, exitsting))
+			project.AddVTEntity(namespace, PackVTEntity(entity, exitsting, ns))
 		}
 	}
 
 	// saving vt xml
-	return mfd.SaveProjectVT(g.options.MFDPath, project)
+	return g.files.SaveProjectVT(g.options.MFDPath, project)
 }
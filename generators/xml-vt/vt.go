@@ -15,7 +15,7 @@ var (
 
 // this code used to convert entities from database to namespace in mfd project file
 
-func PackVTEntity(entity *mfd.Entity, existing *mfd.VTEntity) *mfd.VTEntity {
+func PackVTEntity(entity *mfd.Entity, existing *mfd.VTEntity, namespace *mfd.Namespace) *mfd.VTEntity {
 	// making copy
 	vtEntity := mfd.VTEntity{
 		Name:         entity.Name,
@@ -54,6 +54,11 @@ func PackVTEntity(entity *mfd.Entity, existing *mfd.VTEntity) *mfd.VTEntity {
 	// adding template
 	vtEntity.TmplAttributes = PackTemplate(entity, &vtEntity, existing)
 
+	// adding disabled children tables only for new entities
+	if existing == nil && namespace != nil {
+		vtEntity.TmplAttributes = PackChildren(entity, namespace, vtEntity.TmplAttributes)
+	}
+
 	return &vtEntity
 }
 
@@ -134,8 +139,8 @@ func PackTemplate(entity *mfd.Entity, vt *mfd.VTEntity, existing *mfd.VTEntity)
 				tmpl.List = inSummary(*attr)
 			}
 
-			// foreign key attribute
-			if attr.ForeignEntity != nil {
+			// foreign key attribute, imported entities have no vt services in project
+			if attr.ForeignEntity != nil && !attr.ForeignEntity.IsImported() {
 				if title := attr.ForeignEntity.TitleAttribute(); title != nil {
 					// disable fk in list
 					tmpl.List = false
@@ -184,6 +189,43 @@ func PackTemplate(entity *mfd.Entity, vt *mfd.VTEntity, existing *mfd.VTEntity)
 	return tmplAttributes
 }
 
+// PackChildren adds template attributes for entities from namespace referencing entity by fk (has-many relations).
+// Children tables are disabled by default, set Form to HTML_CHILDREN to edit them in entity form.
+func PackChildren(entity *mfd.Entity, namespace *mfd.Namespace, tmplAttributes mfd.TmplAttributes) mfd.TmplAttributes {
+	pks := entity.PKs()
+	if len(pks) != 1 {
+		return tmplAttributes
+	}
+
+	for _, child := range namespace.Entities {
+		if child.Name == entity.Name || len(child.PKs()) != 1 {
+			continue
+		}
+
+		for _, attr := range child.Attributes {
+			if attr.IsArray || attr.PrimaryKey || attr.ForeignKey != entity.Name {
+				continue
+			}
+
+			name := mfd.MakePlural(child.Name)
+			if tmplAttributes.ByName(name) != nil {
+				name += "By" + util.ReplaceSuffix(attr.Name, util.ID, "")
+			}
+
+			tmplAttributes, _ = tmplAttributes.Merge(&mfd.TmplAttribute{
+				Name:     name,
+				AttrName: pks[0].Name,
+				Children: child.Name + "." + attr.Name,
+
+				Form:   mfd.TypeHTMLNone,
+				Search: mfd.TypeHTMLNone,
+			})
+		}
+	}
+
+	return tmplAttributes
+}
+
 func reorderList(attrs mfd.TmplAttributes) mfd.TmplAttributes {
 	mp := map[int][]int{}
 	for i, attr := range attrs {
@@ -0,0 +1,83 @@
+package xml
+
+import (
+	"fmt"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	genna "github.com/dizzyfool/genna/lib"
+	"github.com/dizzyfool/genna/util"
+)
+
+// commentsQuery reads comments of tables and their columns, table comment has empty column name
+const commentsQuery = `select n.nspname as schema_name, c.relname as table_name, coalesce(a.attname, '') as column_name, d.description
+from pg_catalog.pg_description d
+join pg_catalog.pg_class c on c.oid = d.objoid and d.classoid = 'pg_catalog.pg_class'::regclass
+join pg_catalog.pg_namespace n on n.oid = c.relnamespace
+left join pg_catalog.pg_attribute a on a.attrelid = c.oid and a.attnum = d.objsubid and d.objsubid > 0
+where c.relkind in ('r', 'v', 'm', 'p', 'f')`
+
+type comment struct {
+	Schema      string `pg:"schema_name"`
+	Table       string `pg:"table_name"`
+	Column      string `pg:"column_name"`
+	Description string `pg:"description"`
+}
+
+// TableComments stores comment of table and comments of its columns by column name
+type TableComments struct {
+	Table   string
+	Columns map[string]string
+}
+
+// Comments stores comments of tables by table full name
+type Comments map[string]TableComments
+
+// ReadComments reads COMMENT ON TABLE and COMMENT ON COLUMN descriptions from database
+func ReadComments(g *genna.Genna) (Comments, error) {
+	if err := g.Connect(); err != nil {
+		return nil, err
+	}
+
+	var rows []comment
+	if _, err := g.DB.Query(&rows, commentsQuery); err != nil {
+		return nil, fmt.Errorf("read comments, err=%w", err)
+	}
+
+	comments := Comments{}
+	for _, row := range rows {
+		table := util.JoinF(row.Schema, row.Table)
+		tc, ok := comments[table]
+		if !ok {
+			tc = TableComments{Columns: map[string]string{}}
+		}
+
+		if row.Column == "" {
+			tc.Table = row.Description
+		} else {
+			tc.Columns[row.Column] = row.Description
+		}
+		comments[table] = tc
+	}
+
+	return comments, nil
+}
+
+// Describe fills descriptions of entity and its attributes from comments,
+// descriptions set in project are kept for tables and columns without comments.
+func (c Comments) Describe(entity *mfd.Entity) {
+	tc, ok := c[entity.Table]
+	if !ok {
+		return
+	}
+
+	if tc.Table != "" {
+		entity.Description = tc.Table
+	}
+
+	for _, attribute := range entity.Attributes {
+		if description := tc.Columns[attribute.DBName]; description != "" {
+			attribute.Description = description
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/dizzyfool/genna/generators/base"
 	genna "github.com/dizzyfool/genna/lib"
 	"github.com/dizzyfool/genna/model"
+	"github.com/dizzyfool/genna/util"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 )
@@ -160,15 +161,19 @@ func parseNamespacesFlag(v string) map[string]string {
 	// processing format
 	// namespace1:table1,table2;namespace2:table3
 
+	// tables may contain schema, e.g. billing.invoices or billing.* for all tables of schema
+
 	mp := map[string]string{}
 
 	namespaces := strings.Split(v, ";")
 	for _, namespace := range namespaces {
 		if parts := strings.Split(namespace, ":"); len(parts) == 2 {
-			name := parts[0]
+			name := strings.TrimSpace(parts[0])
 			tables := strings.Split(parts[1], ",")
 			for _, table := range tables {
-				mp[table] = name
+				if table = strings.TrimSpace(table); table != "" {
+					mp[mfd.NormalizeTable(table)] = name
+				}
 			}
 		}
 	}
@@ -223,6 +228,11 @@ func (g *Generator) Generate() (err error) {
 		return fmt.Errorf("read database, err=%w", err)
 	}
 
+	comments, err := ReadComments(&genna)
+	if err != nil {
+		return err
+	}
+
 	set := mfd.NewSet()
 	// filling set
 	for _, namespace := range project.Namespaces {
@@ -230,6 +240,11 @@ func (g *Generator) Generate() (err error) {
 	}
 
 	for _, entity := range entities {
+		// tables of imported namespaces are read-only
+		if project.ImportedEntityByTable(entity.PGFullName) != nil {
+			continue
+		}
+
 		exiting := project.EntityByTable(entity.PGFullName)
 		if exiting != nil {
 			set.Prepend(exiting.Namespace)
@@ -240,7 +255,7 @@ func (g *Generator) Generate() (err error) {
 		if g.options.Packages != nil {
 			// getting namespace from preset
 			var ok bool
-			if namespace, ok = g.options.Packages[entity.PGFullName]; !ok {
+			if namespace, ok = mfd.TableNamespace(g.options.Packages, entity.PGFullName); !ok {
 				continue
 			}
 		} else {
@@ -258,8 +273,14 @@ func (g *Generator) Generate() (err error) {
 				}
 				fallthrough // to default
 			default:
+				// namespace named as schema is suggested first for tables from non-public schemas
+				suggested := set.Elements()
+				if entity.PGSchema != util.PublicSchema && !set.Exists(entity.PGSchema) {
+					suggested = append([]string{entity.PGSchema}, suggested...)
+				}
+
 				// asking namespace from prompt
-				if namespace, err = g.PromptNS(entity.PGFullName, set.Elements()); err != nil {
+				if namespace, err = g.PromptNS(entity.PGFullName, suggested); err != nil {
 					// may happen only in ctrl+c
 					return fmt.Errorf("prompt namespace, err=%w", err)
 				}
@@ -274,7 +295,9 @@ func (g *Generator) Generate() (err error) {
 		set.Prepend(namespace)
 
 		// adding to project
-		project.AddEntity(namespace, PackEntity(namespace, entity, exiting, addedCustomTypes))
+		mfdEntity := PackEntity(namespace, entity, exiting, addedCustomTypes)
+		comments.Describe(mfdEntity)
+		project.AddEntity(namespace, mfdEntity)
 	}
 
 	// suggesting searches && fk links
@@ -316,15 +339,33 @@ func (g *Generator) PromptNS(table string, namespaces []string) (result string,
 	return result, err
 }
 
+// PrintNamespaces returns namespace - tables association in --namespaces flag format.
+// Tables of non-public schema are printed as schema.* if all of them are in one namespace.
 func PrintNamespaces(project *mfd.Project) string {
+	// namespaces of tables by schema
+	schemas := map[string]map[string]struct{}{}
+	for _, namespace := range project.Namespaces {
+		for _, entity := range namespace.Entities {
+			schema := entity.Schema()
+			if schemas[schema] == nil {
+				schemas[schema] = map[string]struct{}{}
+			}
+			schemas[schema][namespace.Name] = struct{}{}
+		}
+	}
+
 	formats := make([]string, len(project.Namespaces))
 	for i := range project.Namespaces {
-		format := make([]string, len(project.Namespaces[i].Entities))
-		for j := range project.Namespaces[i].Entities {
-			format[j] = project.Namespaces[i].Entities[j].Table
+		set := mfd.NewSet()
+		for _, entity := range project.Namespaces[i].Entities {
+			if schema := entity.Schema(); schema != util.PublicSchema && len(schemas[schema]) == 1 {
+				set.Append(util.Join(schema, "*"))
+				continue
+			}
+			set.Append(entity.Table)
 		}
 
-		formats[i] = fmt.Sprintf("%s:%s", project.Namespaces[i].Name, strings.Join(format, ","))
+		formats[i] = fmt.Sprintf("%s:%s", project.Namespaces[i].Name, strings.Join(set.Elements(), ","))
 	}
 
 	return strings.Join(formats, ";")
@@ -117,3 +117,61 @@ func TestGenerator_TableModel(t *testing.T) {
 		})
 	})
 }
+
+func TestParseNamespacesFlag(t *testing.T) {
+	got := parseNamespacesFlag("portal:news,public.tags;billing:billing.*, billing.invoices")
+	want := map[string]string{
+		"news":             "portal",
+		"tags":             "portal",
+		"billing.*":        "billing",
+		"billing.invoices": "billing",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNamespacesFlag() = %v, want %v", got, want)
+	}
+}
+
+func TestPrintNamespaces(t *testing.T) {
+	project := mfd.NewProject("test", mfd.GoPG10)
+	project.AddEntity("portal", &mfd.Entity{Name: "News", Namespace: "portal", Table: "news"})
+	project.AddEntity("portal", &mfd.Entity{Name: "Report", Namespace: "portal", Table: "stats.reports"})
+	project.AddEntity("billing", &mfd.Entity{Name: "Invoice", Namespace: "billing", Table: "billing.invoices"})
+	project.AddEntity("billing", &mfd.Entity{Name: "Payment", Namespace: "billing", Table: "billing.payments"})
+	project.AddEntity("stats", &mfd.Entity{Name: "Event", Namespace: "stats", Table: "stats.events"})
+
+	want := "portal:news,stats.reports;billing:billing.*;stats:stats.events"
+	if got := PrintNamespaces(project); got != want {
+		t.Errorf("PrintNamespaces() = %v, want %v", got, want)
+	}
+}
+
+func TestComments_Describe(t *testing.T) {
+	entity := &mfd.Entity{
+		Name:        "News",
+		Table:       "news",
+		Description: "edited in project",
+		Attributes: mfd.Attributes{
+			{Name: "ID", DBName: "newsId"},
+			{Name: "Title", DBName: "title", Description: "kept"},
+			{Name: "Content", DBName: "content", Description: "edited"},
+		},
+	}
+
+	comments := Comments{
+		"news":         {Table: "Portal news", Columns: map[string]string{"newsId": "News id", "content": "Content of news"}},
+		"stats.events": {Table: "Events"},
+	}
+	comments.Describe(entity)
+
+	if entity.Description != "Portal news" {
+		t.Errorf("Description = %v, want %v", entity.Description, "Portal news")
+	}
+
+	want := []string{"News id", "kept", "Content of news"}
+	for i, attribute := range entity.Attributes {
+		if attribute.Description != want[i] {
+			t.Errorf("%s Description = %v, want %v", attribute.Name, attribute.Description, want[i])
+		}
+	}
+}
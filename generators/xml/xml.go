@@ -1,6 +1,8 @@
 package xml
 
 import (
+	"sort"
+
 	"github.com/vmkteam/mfd-generator/mfd"
 
 	"github.com/dizzyfool/genna/model"
@@ -17,11 +19,13 @@ func PackEntity(namespace string, entity model.Entity, existing *mfd.Entity, new
 	var attributes mfd.Attributes
 	var searches mfd.Searches
 	name := entity.GoName
+	description := ""
 
 	if existing != nil {
 		attributes = existing.Attributes
 		searches = existing.Searches
 		name = existing.Name
+		description = existing.Description
 	}
 
 	hasAlias := false
@@ -56,17 +60,36 @@ func PackEntity(namespace string, entity model.Entity, existing *mfd.Entity, new
 		}
 	}
 
+	sortAttributes(attributes, entity.Columns)
+
 	mfdEntity := &mfd.Entity{
-		Name:       name,
-		Namespace:  namespace,
-		Table:      entity.PGFullName,
-		Attributes: attributes,
-		Searches:   searches,
+		Name:        name,
+		Namespace:   namespace,
+		Table:       entity.PGFullName,
+		Description: description,
+		Attributes:  attributes,
+		Searches:    searches,
 	}
 
 	return mfdEntity
 }
 
+// sortAttributes keeps attributes in order of columns in db, attributes of removed columns go last
+func sortAttributes(attributes mfd.Attributes, columns []model.Column) {
+	ordinal := func(attr *mfd.Attribute) int {
+		for i, column := range columns {
+			if column.PGName == attr.DBName && column.PGType == attr.DBType {
+				return i
+			}
+		}
+		return len(columns)
+	}
+
+	sort.SliceStable(attributes, func(i, j int) bool {
+		return ordinal(attributes[i]) < ordinal(attributes[j])
+	})
+}
+
 func newAttribute(entity model.Entity, column model.Column) *mfd.Attribute {
 	// special behaviour for statusId column
 	if mfd.IsStatus(column.PGName) {
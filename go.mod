@@ -10,6 +10,7 @@ require (
 	github.com/smartystreets/goconvey v1.8.1
 	github.com/spf13/cobra v1.9.1
 	github.com/vmkteam/zenrpc/v2 v2.3.0
+	go.yaml.in/yaml/v3 v3.0.4
 	golang.org/x/text v0.29.0
 )
 
@@ -0,0 +1,176 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	mfdFlag    = "mfd"
+	formatFlag = "format"
+	outputFlag = "output"
+	rulesFlag  = "rules"
+)
+
+// Options stores lint command options
+type Options struct {
+	// MFDPath stores path for mfd project
+	MFDPath string
+
+	// Format of report: text, json or sarif
+	Format string
+
+	// Output file, stdout if empty
+	Output string
+
+	// Rules prints rule catalogue instead of checking project
+	Rules bool
+}
+
+// Linter checks mfd project with rules from catalogue
+type Linter struct {
+	options Options
+}
+
+// New creates linter
+func New() *Linter {
+	return &Linter{}
+}
+
+// CreateCommand creates lint command, it exits with code 1 if errors were found
+func CreateCommand() *cobra.Command {
+	linter := New()
+
+	command := &cobra.Command{
+		Use:   "lint",
+		Short: "Check mfd project with lint rules",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := linter.ReadFlags(command); err != nil {
+				log.Printf("read flags error: %s", err)
+				os.Exit(1)
+			}
+
+			ok, err := linter.Lint()
+			if err != nil {
+				log.Printf("lint error: %s", err)
+				os.Exit(1)
+			}
+			if !ok {
+				os.Exit(1)
+			}
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+	}
+
+	linter.AddFlags(command)
+
+	return command
+}
+
+// AddFlags adds flags to command
+func (l *Linter) AddFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringP(mfdFlag, "m", "", "mfd file path")
+	flags.StringP(formatFlag, "f", FormatText, "report format: text, json or sarif")
+	flags.StringP(outputFlag, "o", "", "report file path, stdout if empty")
+	flags.Bool(rulesFlag, false, "print rule catalogue")
+}
+
+// ReadFlags reads flags from command
+func (l *Linter) ReadFlags(command *cobra.Command) error {
+	var err error
+
+	flags := command.Flags()
+
+	if l.options.MFDPath, err = flags.GetString(mfdFlag); err != nil {
+		return err
+	}
+
+	if l.options.Format, err = flags.GetString(formatFlag); err != nil {
+		return err
+	}
+
+	if l.options.Output, err = flags.GetString(outputFlag); err != nil {
+		return err
+	}
+
+	if l.options.Rules, err = flags.GetBool(rulesFlag); err != nil {
+		return err
+	}
+
+	if !l.options.Rules && l.options.MFDPath == "" {
+		return fmt.Errorf("required flag \"%s\" not set", mfdFlag)
+	}
+
+	return nil
+}
+
+// Lint checks project and writes report, returns false if errors were found
+func (l *Linter) Lint() (bool, error) {
+	var w io.Writer = os.Stdout
+	if l.options.Output != "" {
+		f, err := os.Create(l.options.Output)
+		if err != nil {
+			return false, fmt.Errorf("create report, err=%w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if l.options.Rules {
+		for _, rule := range Rules {
+			if _, err := fmt.Fprintf(w, "%-24s %-8s %s\n", rule.Name, rule.Severity, rule.Description); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	// findings of inconsistent project are written before error
+	findings, lintErr := LintFile(l.options.MFDPath)
+	if findings == nil && lintErr != nil {
+		return false, lintErr
+	}
+
+	if err := Write(w, findings, l.options.Format); err != nil {
+		return false, fmt.Errorf("write report, err=%w", err)
+	}
+
+	return lintErr == nil && Count(findings, SeverityError) == 0, lintErr
+}
+
+// LintFile loads project with translations from mfd file and checks it.
+// Findings are returned with error if project is loaded but inconsistent.
+func LintFile(mfdPath string) ([]Finding, error) {
+	project, loadErr := mfd.LoadProject(mfdPath, false, 0)
+	if project == nil {
+		return nil, loadErr
+	}
+
+	if err := CheckConfig(project.Lint); err != nil {
+		return nil, fmt.Errorf("check lint settings, err=%w", err)
+	}
+
+	translations, err := mfd.LoadTranslations(mfdPath, project.Languages)
+	if err != nil {
+		return nil, fmt.Errorf("read translations, err=%w", err)
+	}
+
+	findings := Run(NewContext(project, mfdPath, translations))
+	if loadErr != nil {
+		return findings, fmt.Errorf("load project, err=%w", loadErr)
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+// Severity is a level of lint finding
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off"
+)
+
+// Location points to the place in project files where problem was found
+type Location struct {
+	File      string `json:"file"`
+	Namespace string `json:"namespace,omitempty"`
+	Entity    string `json:"entity,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+}
+
+// String returns location as namespace.Entity.Attribute
+func (l Location) String() string {
+	var parts []string
+	for _, part := range []string{l.Namespace, l.Entity, l.Attribute} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Finding is a problem found by lint rule
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Location Location `json:"location"`
+}
+
+// Context stores loaded project for lint rules
+type Context struct {
+	Project *mfd.Project
+
+	// ProjectFile is a file name of .mfd project
+	ProjectFile string
+
+	// Translations stores loaded translations by language
+	Translations map[string]mfd.Translation
+}
+
+// NewContext creates lint context for project loaded from mfd file
+func NewContext(project *mfd.Project, mfdPath string, translations map[string]mfd.Translation) *Context {
+	return &Context{
+		Project:      project,
+		ProjectFile:  filepath.Base(mfdPath),
+		Translations: translations,
+	}
+}
+
+// CheckConfig checks that lint settings refer to rules from catalogue and use known severities
+func CheckConfig(config *mfd.LintConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	for _, settings := range config.Rules {
+		if RuleByName(settings.Name) == nil {
+			return fmt.Errorf("unknown lint rule %q", settings.Name)
+		}
+
+		switch Severity(settings.Severity) {
+		case "", SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		default:
+			return fmt.Errorf("unknown severity %q of lint rule %s", settings.Severity, settings.Name)
+		}
+	}
+
+	return nil
+}
+
+// Run checks project with every rule from catalogue,
+// severities and suppressions are taken from project lint settings.
+func Run(c *Context) []Finding {
+	var findings []Finding
+	for _, rule := range Rules {
+		settings := c.Project.Lint.Rule(rule.Name)
+
+		severity := rule.Severity
+		if settings != nil && settings.Severity != "" {
+			severity = Severity(settings.Severity)
+		}
+		if severity == SeverityOff {
+			continue
+		}
+
+		for _, finding := range rule.Check(c) {
+			if settings.IsIgnored(finding.Location.String()) {
+				continue
+			}
+
+			finding.Rule, finding.Severity = rule.Name, severity
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// Count returns count of findings with severity
+func Count(findings []Finding, severity Severity) int {
+	var count int
+	for _, finding := range findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
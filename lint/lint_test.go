@@ -0,0 +1,165 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+func testProject() *mfd.Project {
+	project := mfd.NewProject("test.mfd", mfd.GoPG10)
+	project.NamespaceNames = []string{"portal", "empty"}
+	project.Namespaces = []*mfd.Namespace{{
+		Name: "portal",
+		Entities: []*mfd.Entity{{
+			Name:      "News",
+			Namespace: "portal",
+			Attributes: mfd.Attributes{
+				{Name: "ID", DBName: "newsId", DBType: "int4", GoType: "int", PrimaryKey: true, Null: mfd.NullableNo},
+				{Name: "CategoryID", DBName: "categoryId", DBType: "int4", GoType: "int", ForeignKey: "Category", Null: mfd.NullableNo},
+				{Name: "Title", DBName: "title", DBType: "varchar", GoType: "string", Max: 255, Null: mfd.NullableYes},
+				{Name: "Rating", DBName: "rating", DBType: "int4", GoType: "int", Max: 10, Null: mfd.NullableNo},
+			},
+			Searches: mfd.Searches{
+				{Name: "AuthorID", AttrName: "AuthorID", SearchType: mfd.SearchEquals},
+			},
+		}},
+	}}
+	project.VTNamespaces = []*mfd.VTNamespace{{
+		Name: "portal",
+		Entities: []*mfd.VTEntity{
+			{
+				Name:         "News",
+				TerminalPath: "news",
+				Attributes: mfd.VTAttributes{
+					{Name: "ID", AttrName: "ID"},
+					{Name: "Title", AttrName: "Title", Required: true},
+				},
+				TmplAttributes: mfd.TmplAttributes{
+					{Name: "Title", AttrName: "Title", Form: mfd.TypeHTMLInput},
+					{Name: "Views", AttrName: "Views", Form: mfd.TypeHTMLInput},
+				},
+			},
+			{Name: "Tag", TerminalPath: "news"},
+		},
+	}}
+	project.UpdateLinks()
+
+	return project
+}
+
+func rules(findings []Finding) []string {
+	res := make([]string, len(findings))
+	for i, f := range findings {
+		res[i] = f.Rule + " " + f.Location.String()
+	}
+	return res
+}
+
+func TestRun(t *testing.T) {
+	findings := Run(NewContext(testProject(), "test.mfd", nil))
+
+	want := []string{
+		"empty-namespace empty",
+		"fk-target portal.News.CategoryID",
+		"search-attribute portal.News.AuthorID",
+		"vt-entity portal.Tag",
+		"template-attribute portal.News.Views",
+		"duplicate-terminal-path portal.Tag",
+		"max-non-string portal.News.Rating",
+		"required-nullable portal.News.Title",
+		"missing-translation portal.News",
+		"missing-translation portal.Tag",
+	}
+	if got := rules(findings); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Run() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRun_Config(t *testing.T) {
+	project := testProject()
+	project.Lint = &mfd.LintConfig{Rules: []mfd.LintRule{
+		{Name: "missing-translation", Severity: string(SeverityOff)},
+		{Name: "max-non-string", Severity: string(SeverityError)},
+		{Name: "fk-target", Ignore: []string{"portal.News.CategoryID"}},
+		{Name: "vt-entity", Ignore: []string{"portal.*"}},
+	}}
+
+	findings := Run(NewContext(project, "test.mfd", nil))
+	for _, f := range findings {
+		switch f.Rule {
+		case "missing-translation", "fk-target", "vt-entity":
+			t.Errorf("suppressed finding reported: %+v", f)
+		case "max-non-string":
+			if f.Severity != SeverityError {
+				t.Errorf("severity not overridden: %+v", f)
+			}
+		}
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []mfd.LintRule
+		wantErr bool
+	}{
+		{name: "valid", rules: []mfd.LintRule{{Name: "fk-target", Severity: "warning"}, {Name: "vt-entity", Ignore: []string{"portal.*"}}}},
+		{name: "unknown severity", rules: []mfd.LintRule{{Name: "fk-target", Severity: "warn"}}, wantErr: true},
+		{name: "severity case", rules: []mfd.LintRule{{Name: "fk-target", Severity: "Error"}}, wantErr: true},
+		{name: "unknown rule", rules: []mfd.LintRule{{Name: "fk-targets", Severity: "off"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckConfig(&mfd.LintConfig{Rules: tt.rules})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := CheckConfig(nil); err != nil {
+		t.Errorf("CheckConfig(nil) error = %v", err)
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	findings, err := LintFile("../generators/testdata/expected/newsportal.mfd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	findings := Run(NewContext(testProject(), "test.mfd", nil))
+
+	var buf bytes.Buffer
+	if err := Write(&buf, findings, FormatText); err != nil {
+		t.Fatal(err)
+	}
+	if line := "portal.xml: portal.News.CategoryID: error: fk entity Category not found (fk-target)"; !strings.Contains(buf.String(), line) {
+		t.Errorf("text report has no line %q:\n%s", line, buf.String())
+	}
+
+	buf.Reset()
+	if err := Write(&buf, findings, FormatSARIF); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != len(findings) || len(log.Runs[0].Tool.Driver.Rules) != len(Rules) {
+		t.Errorf("unexpected sarif log: %+v", log)
+	}
+
+	if err := Write(&buf, findings, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
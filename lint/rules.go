@@ -0,0 +1,403 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+// Rule is a named check of the project
+type Rule struct {
+	Name        string
+	Severity    Severity
+	Description string
+
+	Check func(c *Context) []Finding
+}
+
+// Rules is a catalogue of lint rules, findings are reported in this order
+var Rules = []Rule{
+	{Name: "gopg-version", Severity: SeverityError, Description: "go-pg version of the project must be supported", Check: checkGoPGVersion},
+	{Name: "empty-namespace", Severity: SeverityWarning, Description: "namespace listed in the project should have entities in its xml", Check: checkEmptyNamespaces},
+	{Name: "reserved-name", Severity: SeverityError, Description: "namespace and entity names must not be reserved words", Check: checkReservedNames},
+	{Name: "fk-target", Severity: SeverityError, Description: "foreign key must point to an existing entity", Check: checkForeignKeys},
+	{Name: "search-attribute", Severity: SeverityError, Description: "search must point to an existing attribute", Check: checkSearches},
+	{Name: "vt-entity", Severity: SeverityError, Description: "vt entity must have a corresponding entity", Check: checkVTEntities},
+	{Name: "vt-attribute", Severity: SeverityError, Description: "vt attribute must point to an existing attribute or search", Check: checkVTAttributes},
+	{Name: "vt-attribute-template", Severity: SeverityWarning, Description: "vt attribute should have a template entry", Check: checkVTAttributeTemplates},
+	{Name: "template-attribute", Severity: SeverityError, Description: "template attribute must reference an existing vt attribute", Check: checkTemplateAttributes},
+	{Name: "template-children", Severity: SeverityError, Description: "children of template attribute must point to an existing entity and its attribute", Check: checkTemplateChildren},
+	{Name: "duplicate-terminal-path", Severity: SeverityError, Description: "terminal paths of vt entities must be unique", Check: checkTerminalPaths},
+	{Name: "max-non-string", Severity: SeverityWarning, Description: "max length should be set only for string attributes", Check: checkMaxNonString},
+	{Name: "required-nullable", Severity: SeverityWarning, Description: "required vt attribute should not be nullable", Check: checkRequiredNullable},
+	{Name: "missing-translation", Severity: SeverityWarning, Description: "vt entities should be translated to every project language", Check: checkTranslations},
+}
+
+// RuleByName returns rule from catalogue by its name
+func RuleByName(name string) *Rule {
+	for i := range Rules {
+		if Rules[i].Name == name {
+			return &Rules[i]
+		}
+	}
+
+	return nil
+}
+
+func (c *Context) nsLocation(namespace string) Location {
+	return Location{File: mfd.NamespaceFile(c.ProjectFile, namespace), Namespace: namespace}
+}
+
+func (c *Context) vtLocation(namespace string) Location {
+	return Location{File: mfd.VTNamespaceFile(c.ProjectFile, namespace), Namespace: namespace}
+}
+
+func (l Location) with(entity, attribute string) Location {
+	l.Entity, l.Attribute = entity, attribute
+	return l
+}
+
+func checkGoPGVersion(c *Context) []Finding {
+	if c.Project.GoPGVer >= mfd.GoPG8 && c.Project.GoPGVer <= mfd.GoPG10 {
+		return nil
+	}
+
+	return []Finding{{
+		Message:  fmt.Sprintf("unsupported go-pg version: %d", c.Project.GoPGVer),
+		Location: Location{File: c.ProjectFile},
+	}}
+}
+
+func checkEmptyNamespaces(c *Context) []Finding {
+	var findings []Finding
+	for _, name := range c.Project.NamespaceNames {
+		if c.Project.Namespace(name) == nil {
+			findings = append(findings, Finding{
+				Message:  fmt.Sprintf("namespace %s has no entities", name),
+				Location: c.nsLocation(name),
+			})
+		}
+	}
+
+	return findings
+}
+
+func checkReservedNames(c *Context) []Finding {
+	isReserved := func(name string) bool {
+		return mfd.IsReserved(name) || mfd.IsReservedByMFD(name)
+	}
+
+	var findings []Finding
+	for _, namespace := range c.Project.Namespaces {
+		if isReserved(namespace.Name) {
+			findings = append(findings, Finding{
+				Message:  fmt.Sprintf("namespace name %s is reserved", namespace.Name),
+				Location: c.nsLocation(namespace.Name),
+			})
+		}
+
+		for _, entity := range namespace.Entities {
+			if isReserved(entity.Name) {
+				findings = append(findings, Finding{
+					Message:  fmt.Sprintf("entity name %s is reserved", entity.Name),
+					Location: c.nsLocation(namespace.Name).with(entity.Name, ""),
+				})
+			}
+		}
+	}
+
+	for _, namespace := range c.Project.VTNamespaces {
+		for _, entity := range namespace.Entities {
+			// read only entities have no generated services
+			if entity.Mode == mfd.ModeReadOnly || entity.Mode == mfd.ModeNone {
+				continue
+			}
+
+			if isReserved(entity.Name) {
+				findings = append(findings, Finding{
+					Message:  fmt.Sprintf("vt entity name %s is reserved", entity.Name),
+					Location: c.vtLocation(namespace.Name).with(entity.Name, ""),
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkForeignKeys(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.Namespaces {
+		for _, entity := range namespace.Entities {
+			for _, attr := range entity.Attributes {
+				if attr.ForeignKey != "" && attr.ForeignEntity == nil {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("fk entity %s not found", attr.ForeignKey),
+						Location: c.nsLocation(namespace.Name).with(entity.Name, attr.Name),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkSearches(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.Namespaces {
+		for _, entity := range namespace.Entities {
+			for _, search := range entity.Searches {
+				if search.Attribute == nil || search.Entity == nil {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("attribute %s not found for search %s", search.AttrName, search.Name),
+						Location: c.nsLocation(namespace.Name).with(entity.Name, search.Name),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkVTEntities(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.VTNamespaces {
+		for _, vtEntity := range namespace.Entities {
+			if vtEntity.Entity == nil {
+				findings = append(findings, Finding{
+					Message:  fmt.Sprintf("entity %s not found", vtEntity.Name),
+					Location: c.vtLocation(namespace.Name).with(vtEntity.Name, ""),
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkVTAttributes(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.VTNamespaces {
+		for _, vtEntity := range namespace.Entities {
+			if vtEntity.Entity == nil {
+				continue
+			}
+
+			for _, vtAttr := range vtEntity.Attributes {
+				if vtAttr.Attribute != nil {
+					continue
+				}
+
+				location := c.vtLocation(namespace.Name).with(vtEntity.Name, vtAttr.Name)
+				if vtAttr.AttrName != "" {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("attribute %s not found", vtAttr.AttrName),
+						Location: location,
+					})
+				} else if vtAttr.SearchName != "" {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("search %s not found", vtAttr.SearchName),
+						Location: location,
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkVTAttributeTemplates(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.VTNamespaces {
+		for _, vtEntity := range namespace.Entities {
+			// templates are not generated
+			if vtEntity.Mode == mfd.ModeReadOnly || vtEntity.Mode == mfd.ModeNone {
+				continue
+			}
+
+			used := mfd.NewSet()
+			for _, tmpl := range vtEntity.TmplAttributes {
+				used.Add(tmpl.AttrName)
+			}
+
+			for _, vtAttr := range vtEntity.Attributes {
+				// primary keys are not shown in templates
+				if vtAttr.AttrName != "" && vtAttr.Attribute != nil && vtAttr.Attribute.PrimaryKey {
+					continue
+				}
+
+				if !used.Exists(vtAttr.Name) {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("vt attribute %s has no template entry", vtAttr.Name),
+						Location: c.vtLocation(namespace.Name).with(vtEntity.Name, vtAttr.Name),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkTemplateAttributes(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.VTNamespaces {
+		for _, vtEntity := range namespace.Entities {
+			for _, tmpl := range vtEntity.TmplAttributes {
+				if tmpl.VTAttribute == nil && tmpl.AttrName != "" {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("vt attribute %s not found", tmpl.AttrName),
+						Location: c.vtLocation(namespace.Name).with(vtEntity.Name, tmpl.Name),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkTemplateChildren(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.VTNamespaces {
+		for _, vtEntity := range namespace.Entities {
+			for _, tmpl := range vtEntity.TmplAttributes {
+				if tmpl.Children != "" && (tmpl.ChildEntity == nil || tmpl.ChildAttribute == nil) {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("children %s not found", tmpl.Children),
+						Location: c.vtLocation(namespace.Name).with(vtEntity.Name, tmpl.Name),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkTerminalPaths(c *Context) []Finding {
+	var findings []Finding
+	paths := make(map[string]string)
+	for _, namespace := range c.Project.VTNamespaces {
+		for _, vtEntity := range namespace.Entities {
+			if vtEntity.Mode == mfd.ModeNone || vtEntity.TerminalPath == "" {
+				continue
+			}
+
+			location := c.vtLocation(namespace.Name).with(vtEntity.Name, "")
+			if existing, ok := paths[vtEntity.TerminalPath]; ok {
+				findings = append(findings, Finding{
+					Message:  fmt.Sprintf("terminal path %s is already used by %s", vtEntity.TerminalPath, existing),
+					Location: location,
+				})
+				continue
+			}
+
+			paths[vtEntity.TerminalPath] = location.String()
+		}
+	}
+
+	return findings
+}
+
+func checkMaxNonString(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.Namespaces {
+		for _, entity := range namespace.Entities {
+			for _, attr := range entity.Attributes {
+				if attr.Max > 0 && !attr.IsString() {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("max length %d is set for %s attribute", attr.Max, attr.DBType),
+						Location: c.nsLocation(namespace.Name).with(entity.Name, attr.Name),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkRequiredNullable(c *Context) []Finding {
+	var findings []Finding
+	for _, namespace := range c.Project.VTNamespaces {
+		for _, vtEntity := range namespace.Entities {
+			for _, vtAttr := range vtEntity.Attributes {
+				if vtAttr.Required && vtAttr.AttrName != "" && vtAttr.Attribute != nil && vtAttr.Attribute.Nullable() {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("required vt attribute %s is nullable", vtAttr.Name),
+						Location: c.vtLocation(namespace.Name).with(vtEntity.Name, vtAttr.Name),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkTranslations(c *Context) []Finding {
+	var findings []Finding
+	for _, lang := range c.Project.Languages {
+		translation := c.Translations[lang]
+
+		for _, namespace := range c.Project.VTNamespaces {
+			for _, vtEntity := range namespace.Entities {
+				// translations are used only in templates
+				if vtEntity.Mode != mfd.ModeFull && vtEntity.Mode != mfd.ModeReadOnlyWithTemplates {
+					continue
+				}
+
+				location := Location{File: mfd.TranslationFile(c.ProjectFile, lang), Namespace: namespace.Name, Entity: vtEntity.Name}
+
+				actual := translation.Entity(namespace.Name, vtEntity.Name)
+				if actual == nil {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("%s translation not found", lang),
+						Location: location,
+					})
+					continue
+				}
+
+				if missing := missingKeys(vtEntity, actual, lang); len(missing) > 0 {
+					findings = append(findings, Finding{
+						Message:  fmt.Sprintf("%s translation keys not found: %s", lang, strings.Join(missing, ", ")),
+						Location: location,
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// missingKeys compares translation with keys generated by xml-lang for vt entity
+func missingKeys(vtEntity *mfd.VTEntity, actual *mfd.TranslationEntity, lang string) []string {
+	expected := mfd.NewTranslationEntity(vtEntity.Name, lang)
+	expected.FillByVTEntity(vtEntity, lang)
+
+	var missing []string
+	compare := func(prefix string, expected, actual *mfd.XMLMap) {
+		for _, key := range expected.Keys() {
+			if actual == nil || actual.Index(key) == -1 {
+				missing = append(missing, prefix+key)
+			}
+		}
+	}
+
+	compare("crumbs.", expected.Crumbs, actual.Crumbs)
+	compare("form.", expected.Form, actual.Form)
+	list := actual.List
+	if list == nil {
+		list = &mfd.TranslationList{}
+	}
+	compare("list.filter.", expected.List.Filter, list.Filter)
+	compare("list.headers.", expected.List.Headers, list.Headers)
+
+	return missing
+}
@@ -0,0 +1,200 @@
+package mfd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dizzyfool/genna/util"
+)
+
+// Database is xml element, declares named database with its namespaces.
+// Namespaces not listed in any database belong to default database.
+type Database struct {
+	Name string `xml:"Name,attr" json:"name"`
+
+	// comma separated namespaces stored in database
+	Namespaces string `xml:"Namespaces,attr" json:"namespaces"`
+
+	// comma separated schemas of database search_path, tables from these schemas are referenced without schema
+	SearchPath string `xml:"SearchPath,attr,omitempty" json:"searchPath"`
+}
+
+// NamespaceNames returns namespaces stored in database
+func (d Database) NamespaceNames() []string {
+	return splitList(d.Namespaces)
+}
+
+// Schemas returns search_path schemas of database
+func (d Database) Schemas() []string {
+	return splitList(d.SearchPath)
+}
+
+// GoName returns name of go db wrapper, e.g. AnalyticsDB, DB for default database without name
+func (d Database) GoName() string {
+	if d.Name == "" {
+		return "DB"
+	}
+
+	return util.CamelCased(util.Sanitize(d.Name)) + "DB"
+}
+
+// VarName returns name of go variable with connection to database, e.g. analyticsDB, dbo for default database
+func (d Database) VarName() string {
+	if d.Name == "" {
+		return "dbo"
+	}
+
+	return util.LowerFirst(d.GoName())
+}
+
+// NamedDatabases returns named databases declared in project, Databases is a pointer to omit empty element in xml
+func (p *Project) NamedDatabases() []Database {
+	if p.Databases == nil {
+		return nil
+	}
+
+	return *p.Databases
+}
+
+// Database returns named database of namespace, nil for default database
+func (p *Project) Database(namespace string) *Database {
+	databases := p.NamedDatabases()
+	for i, database := range databases {
+		for _, name := range database.NamespaceNames() {
+			if strings.EqualFold(name, namespace) {
+				return &databases[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// DatabasesByNamespace returns named databases by their namespaces
+func (p *Project) DatabasesByNamespace() map[string]Database {
+	databases := map[string]Database{}
+	for _, database := range p.NamedDatabases() {
+		for _, namespace := range database.NamespaceNames() {
+			databases[namespace] = database
+		}
+	}
+
+	return databases
+}
+
+// SchemaSearchPath returns search_path of database storing namespace, public schema is used by default
+func (p *Project) SchemaSearchPath(namespace string) []string {
+	schemas := splitList(p.SearchPath)
+	if database := p.Database(namespace); database != nil {
+		schemas = database.Schemas()
+	}
+
+	if len(schemas) == 0 {
+		return []string{util.PublicSchema}
+	}
+
+	return schemas
+}
+
+// IsConsistentDatabases checks that databases have unique names and existing namespaces,
+// and that entities do not refer to entities from another database
+func (p *Project) IsConsistentDatabases() error {
+	names := map[string]struct{}{}
+	namespaces := map[string]string{}
+	for _, database := range p.NamedDatabases() {
+		if database.Name == "" {
+			return fmt.Errorf("database name is empty")
+		}
+		if _, ok := names[database.GoName()]; ok {
+			return fmt.Errorf("database %s is declared twice", database.Name)
+		}
+		names[database.GoName()] = struct{}{}
+
+		for _, namespace := range database.NamespaceNames() {
+			// namespaces which are not loaded in partial project are skipped
+			if p.Namespace(namespace) == nil && !p.partial {
+				return fmt.Errorf("namespace %s of database %s not found", namespace, database.Name)
+			}
+			if other, ok := namespaces[strings.ToLower(namespace)]; ok {
+				return fmt.Errorf("namespace %s is listed in databases %s and %s", namespace, other, database.Name)
+			}
+			namespaces[strings.ToLower(namespace)] = database.Name
+		}
+	}
+
+	if len(p.NamedDatabases()) == 0 {
+		return nil
+	}
+
+	for _, namespace := range p.Namespaces {
+		for _, entity := range namespace.Entities {
+			for _, attr := range entity.Attributes {
+				if attr.ForeignEntity != nil && p.Database(entity.Namespace) != p.Database(attr.ForeignEntity.Namespace) {
+					return fmt.Errorf("fk entity %s for %s column in %s entity is stored in another database", attr.ForeignKey, attr.Name, entity.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Schema returns schema of entity table
+func (e *Entity) Schema() string {
+	schema, _ := util.Split(e.Table)
+	return schema
+}
+
+// TableName returns table name as it is used in queries to database with search_path, schema is omitted for tables from search_path
+func (e *Entity) TableName(searchPath []string) string {
+	schema, table := util.Split(e.Table)
+	for _, s := range searchPath {
+		if s == schema {
+			return table
+		}
+	}
+
+	return util.Join(schema, table)
+}
+
+// NormalizeTable returns table name as it is stored in project: public schema is omitted, e.g. public.news -> news
+func NormalizeTable(table string) string {
+	schema, name := util.Split(strings.TrimSpace(table))
+	if name == "*" {
+		return util.Join(schema, name)
+	}
+
+	return util.JoinF(schema, name)
+}
+
+// TableNamespace finds namespace of table in table -> namespace mapping.
+// Mapping keys may contain schema, public schema is optional, "schema.*" maps all tables of schema.
+func TableNamespace(mapping map[string]string, table string) (string, bool) {
+	table = NormalizeTable(table)
+	if namespace, ok := mapping[table]; ok {
+		return namespace, true
+	}
+
+	for key, namespace := range mapping {
+		if NormalizeTable(key) == table {
+			return namespace, true
+		}
+	}
+
+	schema, _ := util.Split(table)
+	namespace, ok := mapping[util.Join(schema, "*")]
+
+	return namespace, ok
+}
+
+// splitList splits comma separated list skipping empty elements
+func splitList(list string) []string {
+	var result []string
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,110 @@
+package mfd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTableNamespace(t *testing.T) {
+	mapping := map[string]string{
+		"news":             "portal",
+		"public.tags":      "portal",
+		"billing.invoices": "orders",
+		"billing.*":        "billing",
+	}
+
+	tests := []struct {
+		table string
+		want  string
+		found bool
+	}{
+		{table: "news", want: "portal", found: true},
+		{table: "public.news", want: "portal", found: true},
+		{table: "tags", want: "portal", found: true},
+		{table: "billing.invoices", want: "orders", found: true},
+		{table: "billing.payments", want: "billing", found: true},
+		{table: "users", found: false},
+		{table: "stats.events", found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			got, found := TableNamespace(mapping, tt.table)
+			if got != tt.want || found != tt.found {
+				t.Errorf("TableNamespace() = %v, %v, want %v, %v", got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestEntity_TableName(t *testing.T) {
+	tests := []struct {
+		table      string
+		searchPath []string
+		want       string
+	}{
+		{table: "news", searchPath: []string{"public"}, want: "news"},
+		{table: "billing.invoices", searchPath: []string{"public"}, want: "billing.invoices"},
+		{table: "billing.invoices", searchPath: []string{"billing", "public"}, want: "invoices"},
+		{table: "news", searchPath: []string{"billing"}, want: "public.news"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			e := &Entity{Table: tt.table}
+			if got := e.TableName(tt.searchPath); got != tt.want {
+				t.Errorf("TableName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProject_Databases(t *testing.T) {
+	project := &Project{
+		SearchPath: "public,common",
+		Databases:  &[]Database{{Name: "stats", Namespaces: "events, metrics", SearchPath: "stats"}},
+		Namespaces: []*Namespace{
+			{Name: "portal", Entities: []*Entity{{Name: "News", Namespace: "portal"}}},
+			{Name: "events", Entities: []*Entity{{Name: "Event", Namespace: "events"}}},
+			{Name: "metrics", Entities: []*Entity{{Name: "Metric", Namespace: "metrics"}}},
+		},
+	}
+
+	if project.Database("portal") != nil || project.Database("events").Name != "stats" {
+		t.Errorf("Database() found wrong database")
+	}
+	if got := project.SchemaSearchPath("portal"); !reflect.DeepEqual(got, []string{"public", "common"}) {
+		t.Errorf("SchemaSearchPath() = %v", got)
+	}
+	if got := project.SchemaSearchPath("metrics"); !reflect.DeepEqual(got, []string{"stats"}) {
+		t.Errorf("SchemaSearchPath() = %v", got)
+	}
+	if db := project.NamedDatabases()[0]; db.GoName() != "StatsDB" || db.VarName() != "statsDB" || (Database{}).VarName() != "dbo" {
+		t.Errorf("wrong go names of database")
+	}
+	if err := project.IsConsistentDatabases(); err != nil {
+		t.Errorf("IsConsistentDatabases() error = %v", err)
+	}
+
+	// fk to entity from another database
+	project.Namespaces[1].Entities[0].Attributes = Attributes{{Name: "NewsID", ForeignKey: "News", ForeignEntity: project.Namespaces[0].Entities[0]}}
+	if err := project.IsConsistentDatabases(); err == nil {
+		t.Errorf("IsConsistentDatabases() should fail for fk to another database")
+	}
+
+	project.Namespaces[1].Entities[0].Attributes = nil
+
+	// namespace of database is not loaded
+	namespaces := project.Namespaces
+	project.Namespaces = namespaces[:2]
+	if err := project.IsConsistentDatabases(); err == nil {
+		t.Errorf("IsConsistentDatabases() should fail for missing namespace")
+	}
+	project.partial = true
+	if err := project.IsConsistentDatabases(); err != nil {
+		t.Errorf("IsConsistentDatabases() error = %v for partial project", err)
+	}
+	project.Namespaces, project.partial = namespaces, false
+	*project.Databases = append(*project.Databases, Database{Name: "other", Namespaces: "metrics"})
+	if err := project.IsConsistentDatabases(); err == nil {
+		t.Errorf("IsConsistentDatabases() should fail for namespace in two databases")
+	}
+}
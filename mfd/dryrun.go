@@ -0,0 +1,226 @@
+package mfd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/dizzyfool/genna/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DryRunFlag is a global flag, generated and project files are not written, unified diff of changes is printed
+	DryRunFlag = "dry-run"
+	// DryRunFormatFlag is a format of dry run output: diff or json
+	DryRunFormatFlag = "dry-run-format"
+	// CheckFlag is a global flag, files are not written, command exits with code 1 if any of them is not up to date
+	CheckFlag = "check"
+
+	DryRunDiff = "diff"
+	DryRunJSON = "json"
+)
+
+// FileStatus is a status of file written in dry run mode
+type FileStatus string
+
+const (
+	FileCreated   FileStatus = "created"
+	FileModified  FileStatus = "modified"
+	FileUnchanged FileStatus = "unchanged"
+)
+
+// FileChange is a file written in dry run mode
+type FileChange struct {
+	Filename string     `json:"filename"`
+	Status   FileStatus `json:"status"`
+	Diff     string     `json:"diff,omitempty"`
+}
+
+// dryRun keeps files written in dry run mode in memory, reads of such files return written content
+var dryRun struct {
+	sync.Mutex
+	enabled bool
+	files   map[string][]byte
+	order   []string
+}
+
+// StartDryRun turns on dry run mode: files are not written, their content is kept in memory
+func StartDryRun() {
+	dryRun.Lock()
+	defer dryRun.Unlock()
+
+	dryRun.enabled, dryRun.files, dryRun.order = true, map[string][]byte{}, nil
+}
+
+// StopDryRun turns off dry run mode and returns written files in order of first write compared with files on disk
+func StopDryRun() []FileChange {
+	dryRun.Lock()
+	defer dryRun.Unlock()
+
+	changes := make([]FileChange, 0, len(dryRun.order))
+	for _, filename := range dryRun.order {
+		content := dryRun.files[filename]
+
+		old, err := os.ReadFile(filename)
+		change := FileChange{Filename: filename, Status: FileModified}
+		switch {
+		case err != nil:
+			change.Status = FileCreated
+		case bytes.Equal(old, content):
+			change.Status = FileUnchanged
+		}
+		if change.Status != FileUnchanged {
+			change.Diff = UnifiedDiff(filename, old, content, change.Status == FileCreated)
+		}
+
+		changes = append(changes, change)
+	}
+	dryRun.enabled, dryRun.files, dryRun.order = false, nil, nil
+
+	return changes
+}
+
+// ReadFile reads file, in dry run mode content written before is returned
+func ReadFile(filename string) ([]byte, error) {
+	dryRun.Lock()
+	content, ok := dryRun.files[filename]
+	dryRun.Unlock()
+
+	if ok {
+		return content, nil
+	}
+
+	return os.ReadFile(filename)
+}
+
+// FileExists checks if file exists on disk or was written in dry run mode
+func FileExists(filename string) bool {
+	dryRun.Lock()
+	_, ok := dryRun.files[filename]
+	dryRun.Unlock()
+
+	if ok {
+		return true
+	}
+
+	_, err := os.Stat(filename)
+	return err == nil || !os.IsNotExist(err)
+}
+
+// WriteFile creates dirs and writes file, in dry run mode content is kept in memory
+func WriteFile(filename string, content []byte) error {
+	dryRun.Lock()
+	if dryRun.enabled {
+		defer dryRun.Unlock()
+
+		if _, ok := dryRun.files[filename]; !ok {
+			dryRun.order = append(dryRun.order, filename)
+		}
+		dryRun.files[filename] = bytes.Clone(content)
+
+		return nil
+	}
+	dryRun.Unlock()
+
+	file, err := util.File(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(content)
+	return err
+}
+
+// FmtAndSave formats go source and saves it, source with syntax errors is saved as is with format error returned
+func FmtAndSave(unformatted []byte, filename string) (bool, error) {
+	return NewFiles(nil).FmtAndSave(unformatted, filename)
+}
+
+// WithDryRun adds global dry run flags to root command.
+// In dry run mode files are not written, diff or json summary of files is printed to stdout.
+// With check flag command exits with code 1 if any of written files is not up to date, it can be used in CI.
+func WithDryRun(command *cobra.Command) *cobra.Command {
+	flags := command.PersistentFlags()
+	flags.Bool(DryRunFlag, false, "do not write files, print unified diff of changes")
+	flags.String(DryRunFormatFlag, DryRunDiff, "dry run output format: diff or json")
+	flags.Bool(CheckFlag, false, "do not write files, exit with code 1 if any of them is not up to date")
+
+	command.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		dry, _ := cmd.Flags().GetBool(DryRunFlag)
+		check, _ := cmd.Flags().GetBool(CheckFlag)
+		if format, _ := cmd.Flags().GetString(DryRunFormatFlag); format != DryRunDiff && format != DryRunJSON {
+			return fmt.Errorf("invalid %s value: %s", DryRunFormatFlag, format)
+		}
+
+		if dry || check {
+			StartDryRun()
+		}
+
+		return nil
+	}
+
+	command.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		dry, _ := cmd.Flags().GetBool(DryRunFlag)
+		check, _ := cmd.Flags().GetBool(CheckFlag)
+		if !dry && !check {
+			return
+		}
+
+		changes := StopDryRun()
+		if dry {
+			format, _ := cmd.Flags().GetString(DryRunFormatFlag)
+			if err := PrintDryRun(os.Stdout, changes, format); err != nil {
+				log.Printf("print dry run error: %s", err)
+				os.Exit(1)
+			}
+		}
+
+		if check && !IsUpToDate(changes) {
+			for _, change := range changes {
+				if change.Status != FileUnchanged {
+					log.Printf("%s is not up to date", change.Filename)
+				}
+			}
+			os.Exit(1)
+		}
+	}
+
+	return command
+}
+
+// IsUpToDate checks if none of files written in dry run mode was changed
+func IsUpToDate(changes []FileChange) bool {
+	for _, change := range changes {
+		if change.Status != FileUnchanged {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PrintDryRun prints unified diffs with summary or json list of files written in dry run mode
+func PrintDryRun(w io.Writer, changes []FileChange, format string) error {
+	if format == DryRunJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(changes)
+	}
+
+	counts := map[FileStatus]int{}
+	for _, change := range changes {
+		counts[change.Status]++
+		if _, err := io.WriteString(w, change.Diff); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d created, %d modified, %d unchanged\n", counts[FileCreated], counts[FileModified], counts[FileUnchanged])
+	return err
+}
@@ -0,0 +1,96 @@
+package mfd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		created bool
+		want    string
+	}{
+		{
+			name: "equal",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name:    "created",
+			new:     "a\nb\n",
+			created: true,
+			want:    "--- /dev/null\n+++ f.go\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "changed line with context",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			new:  "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- f.go\n+++ f.go\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:  "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			want: "--- f.go\n+++ f.go\n@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n@@ -7,4 +8,3 @@\n 7\n 8\n 9\n-10\n",
+		},
+		{
+			name: "insert and delete",
+			old:  "a\nb\nc\n",
+			new:  "a\nc\nd\n",
+			want: "--- f.go\n+++ f.go\n@@ -1,3 +1,3 @@\n a\n-b\n c\n+d\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("f.go", []byte(tt.old), []byte(tt.new), tt.created); got != tt.want {
+				t.Errorf("UnifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	existing, unchanged, created := filepath.Join(dir, "existing.txt"), filepath.Join(dir, "unchanged.txt"), filepath.Join(dir, "new", "created.txt")
+	for _, filename := range []string{existing, unchanged} {
+		if err := os.WriteFile(filename, []byte("old\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	StartDryRun()
+	for filename, content := range map[string]string{existing: "new\n", unchanged: "old\n", created: "created\n"} {
+		if _, err := Save([]byte(content), filename); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	if content, err := ReadFile(existing); err != nil || string(content) != "new\n" {
+		t.Errorf("ReadFile() = %q, %v, want written content", content, err)
+	}
+	if !FileExists(created) {
+		t.Errorf("FileExists() should be true for written file")
+	}
+
+	statuses := map[string]FileStatus{}
+	for _, change := range StopDryRun() {
+		statuses[change.Filename] = change.Status
+	}
+	want := map[string]FileStatus{existing: FileModified, unchanged: FileUnchanged, created: FileCreated}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("StopDryRun() = %v, want %v", statuses, want)
+	}
+
+	if content, _ := os.ReadFile(existing); string(content) != "old\n" {
+		t.Errorf("file was written in dry run mode")
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("file was created in dry run mode")
+	}
+}
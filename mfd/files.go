@@ -1,53 +1,69 @@
 package mfd
 
 import (
-	"bytes"
-	"encoding/json"
-	"encoding/xml"
-	"errors"
 	"fmt"
 	"html/template"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
-	"regexp"
 	"strings"
 	textTemplate "text/template"
-
-	"github.com/dizzyfool/genna/util"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
-func raw(str string) template.HTML {
-	return template.HTML(str)
-}
+type Packer func(*Namespace) (interface{}, error)
 
-func title(str string) template.HTML {
-	c := cases.Title(language.Und, cases.NoLower)
-	return template.HTML(c.String(str))
+// LoadProject Loads MFD Project from File
+func LoadProject(filename string, create bool, goPGVer int) (*Project, error) {
+	if create && !FileExists(filename) {
+		return NewProject(filepath.Base(filename), goPGVer), nil
+	}
+
+	project, err := readProject(filename, goPGVer, map[string]struct{}{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return project, project.IsConsistent()
 }
 
-var TemplateFunctions = template.FuncMap{
-	"raw":     raw,
-	"ToLower": strings.ToLower,
-	"title":   title,
-	"notLast": func(index int, length int) bool {
-		return index+1 != length
-	},
-	"isLast": func(index int, length int) bool {
-		return index+1 == length
-	},
+// LoadProjectNamespaces loads MFD Project from file with given namespaces and namespaces of entities they depend on:
+// fk and foreign search targets and vt children. Other namespaces are not read and project is partial.
+// All namespaces are loaded if namespaces are empty.
+func LoadProjectNamespaces(filename string, goPGVer int, namespaces []string) (*Project, error) {
+	if len(namespaces) == 0 {
+		return LoadProject(filename, false, goPGVer)
+	}
+
+	project, err := readProject(filename, goPGVer, map[string]struct{}{}, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	return project, project.IsConsistent()
 }
 
-type Packer func(*Namespace) (interface{}, error)
+// loadProject loads imported project, it should be consistent
+func loadProject(filename string, goPGVer int, loading map[string]struct{}) (*Project, error) {
+	project, err := readProject(filename, goPGVer, loading, nil)
+	if err != nil {
+		return nil, err
+	}
 
-// LoadProject Loads MFD Project from File
-func LoadProject(filename string, create bool, goPGVer int) (*Project, error) {
-	if _, err := os.Stat(filename); create && os.IsNotExist(err) {
-		return NewProject(filepath.Base(filename), goPGVer), nil
+	return project, project.IsConsistent()
+}
+
+// readProject reads project with namespaces and imports and makes links, only given namespaces with dependencies are read if set
+func readProject(filename string, goPGVer int, loading map[string]struct{}, namespaces []string) (*Project, error) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := loading[abs]; ok {
+		return nil, fmt.Errorf("import cycle detected for %s", filename)
 	}
+	loading[abs] = struct{}{}
+	defer delete(loading, abs)
 
 	project := &Project{}
 	if err := UnmarshalFile(filename, project); err != nil {
@@ -57,28 +73,6 @@ func LoadProject(filename string, create bool, goPGVer int) (*Project, error) {
 	project.Namespaces = []*Namespace{}
 	project.VTNamespaces = []*VTNamespace{}
 
-	dir := filepath.Dir(filename)
-	for _, pf := range project.NamespaceNames {
-		ns, err := LoadNamespace(path.Join(dir, pf+".xml"))
-		//todo: maybe create xmls if not exists
-		if err != nil {
-			return nil, fmt.Errorf("read namespace, err=%w", err)
-		}
-
-		vtns, err := LoadVTNamespace(path.Join(dir, pf+".vt.xml"))
-		if err != nil {
-			return nil, fmt.Errorf("read vt vtns, err=%w", err)
-		}
-
-		if len(ns.Entities) != 0 {
-			project.Namespaces = append(project.Namespaces, ns)
-		}
-
-		if len(vtns.Entities) != 0 {
-			project.VTNamespaces = append(project.VTNamespaces, vtns)
-		}
-	}
-
 	// backward compatibility
 	if len(project.Languages) == 0 {
 		project.Languages = []string{EnLang}
@@ -87,9 +81,22 @@ func LoadProject(filename string, create bool, goPGVer int) (*Project, error) {
 		project.GoPGVer = goPGVer
 	}
 
+	if err := project.loadImports(filename, project.GoPGVer, loading); err != nil {
+		return nil, err
+	}
+
+	if len(namespaces) == 0 {
+		err = project.readNamespaces(filename)
+	} else {
+		err = project.readPartial(filename, namespaces)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	project.UpdateLinks()
 
-	return project, project.IsConsistent()
+	return project, nil
 }
 
 func LoadNamespace(filename string) (*Namespace, error) {
@@ -102,10 +109,11 @@ func LoadNamespace(filename string) (*Namespace, error) {
 }
 
 func LoadVTNamespace(filename string) (*VTNamespace, error) {
+	name := strings.TrimSuffix(path.Base(filename), ".vt"+filepath.Ext(filename))
 	namespace := &VTNamespace{
-		Name: strings.TrimSuffix(path.Base(filename), ".vt.xml"),
+		Name: name,
 	}
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
+	if !FileExists(filename) {
 		return namespace, nil
 	}
 
@@ -115,19 +123,26 @@ func LoadVTNamespace(filename string) (*VTNamespace, error) {
 
 	// backward comp
 	if namespace.Name == "" {
-		namespace.Name = strings.TrimSuffix(path.Base(filename), ".vt.xml")
+		namespace.Name = name
 	}
 
 	return namespace, nil
 }
 
+// UnmarshalFile reads file with storage chosen by file extension
 func UnmarshalFile(filename string, v interface{}) (err error) {
 	var bytes []byte
-	if bytes, err = os.ReadFile(filename); err != nil {
+	if bytes, err = ReadFile(filename); err != nil {
 		return fmt.Errorf("read file, err=%w", err)
 	}
 
-	if err := xml.Unmarshal(bytes, v); err != nil {
+	if Format(filename) == FormatXML {
+		if err := ValidateXML(bytes); err != nil {
+			return fmt.Errorf("validate, err=%w", err)
+		}
+	}
+
+	if err := StorageByFile(filename).Unmarshal(bytes, v); err != nil {
 		return fmt.Errorf("unmarshal, err=%w", err)
 	}
 
@@ -135,318 +150,48 @@ func UnmarshalFile(filename string, v interface{}) (err error) {
 }
 
 func SaveMFD(filename string, p *Project) error {
-	if err := MarshalToFile(filename, p); err != nil {
-		return fmt.Errorf("save project, err=%w", err)
-	}
-
-	return nil
+	return NewFiles(nil).SaveMFD(filename, p)
 }
 
 func SaveProjectXML(filename string, p *Project) error {
-	for _, namespace := range p.Namespaces {
-		file := path.Join(filepath.Dir(filename), namespace.Name+".xml")
-		if err := MarshalToFile(file, namespace); err != nil {
-			return fmt.Errorf("save namespace %s, err=%w", namespace.Name, err)
-		}
-	}
-
-	return nil
+	return NewFiles(nil).SaveProjectXML(filename, p)
 }
 
 func SaveProjectVT(filename string, p *Project) error {
-	for _, namespace := range p.VTNamespaces {
-		file := path.Join(filepath.Dir(filename), namespace.Name+".vt.xml")
-		if err := MarshalToFile(file, namespace); err != nil {
-			return fmt.Errorf("save namespace vt entites %s, err=%w", namespace.Name, err)
-		}
-	}
-
-	return nil
+	return NewFiles(nil).SaveProjectVT(filename, p)
 }
 
+// MarshalToFile writes file with storage chosen by file extension,
+// comments and unknown elements of existing xml file are kept
 func MarshalToFile(filename string, v interface{}) error {
-	b, err := xml.MarshalIndent(v, "", "    ")
-	if err != nil {
-		return fmt.Errorf("marshal data, err=%w", err)
-	}
-
-	// need for json searching rules
-	b = bytes.ReplaceAll(b, []byte("-&gt;"), []byte("->"))
-
-	// append line break at end of file, if not exists
-	if !bytes.HasSuffix(b, []byte("\n")) {
-		b = append(b, []byte("\n")...)
-	}
-
-	if _, err = Save(b, filename); err != nil {
-		return fmt.Errorf("write file, err=%w", err)
-	}
-
-	return nil
+	return NewFiles(nil).MarshalToFile(filename, v)
 }
 
-func FormatAndSave(data any, output, tmpl string, format bool) (bool, error) {
-	buf := new(bytes.Buffer)
-	if err := Render(buf, tmpl, data); err != nil {
-		return false, fmt.Errorf("render template, err=%w", err)
-	}
-
-	if format {
-		return util.FmtAndSave(buf.Bytes(), output)
-	}
-
-	return Save(buf.Bytes(), output)
+func FormatAndSave(data any, output, tmpl string, format bool, partials ...string) (bool, error) {
+	return NewFiles(nil).FormatAndSave(data, output, tmpl, format, partials...)
 }
 
-// Render renders text/template to Writer.
-func Render(wr io.Writer, tmpl string, data any) error {
+// Render renders text/template to Writer, partials are parsed after template and redefine its sub-templates.
+func Render(wr io.Writer, tmpl string, data any, partials ...string) error {
 	t := template.Must(template.New("base").Funcs(TemplateFunctions).Parse(tmpl))
-	return t.Execute(wr, data)
-}
-
-func RenderText(wr io.Writer, tmpl string, data any) error {
-	t := textTemplate.Must(textTemplate.New("base").Funcs(TemplateFunctions).Parse(tmpl))
-	return t.Execute(wr, data)
-}
-
-// replaceFragmentInFile replaces fragments of text in a file with new data if they match a given regular expression.
-//
-// Parameters:
-// - output: the path to the file where the replacement will take place.
-// - findData: the string to search for within the fragment to be replaced.
-// - newData: the string to replace the found fragment with.
-// - pattern: a pointer to a compiled regular expression used to find the fragments.
-//
-// Returns:
-//
-// - bool: true if the replacement was successful, and false otherwise.
-//
-// - error: an error if there was an issue reading the file or replacing the fragments.
-//
-// Example usage:
-//
-//		output := "path/to/file.txt"
-//		findData := "old text"
-//		newData := "new text"
-//		pattern := regexp.MustCompile(`pattern`)
-//		success, err := replaceFragmentInFile(output, findData, newData, pattern)
-//		if err != nil {
-//	    log.Fatal(err)
-//		}
-//
-// fmt.Println("Replacement successful:", success)
-func replaceFragmentInFile(output, findData, newData, openingToken, closeningToken string, pattern *regexp.Regexp, force bool) (bool, error) {
-	content, err := os.ReadFile(output)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return false, fmt.Errorf("read file err: %w", err)
-		}
-
-		if _, err := os.Create(output); err != nil {
-			return false, fmt.Errorf("output file was not found, attemtion to create it is failed, output=%s, err=%w", output, err)
-		}
-	}
-
-	lines := strings.Split(string(content), "\n")
-	ff := extractFragments(pattern, lines, openingToken, closeningToken)
-	if len(ff) == 0 {
-		lines = append(lines, strings.Split(newData, "\n")...)
-		newContent := strings.Join(lines, "\n")
-		return util.FmtAndSave([]byte(newContent), output)
-	}
-
-	var found, changed bool
-	for _, fragment := range ff {
-		s, end := fragment[0], fragment[1]
-		extractedFragment := lines[s:end]
-		for _, extline := range extractedFragment {
-			if strings.Contains(extline, findData) {
-				found = true
-				if force {
-					var resultLines []string
-					resultLines = append(resultLines, lines[:s]...)
-					resultLines = append(resultLines, strings.Split(newData, "\n")...)
-					resultLines = append(resultLines, lines[end:]...)
-
-					lines = resultLines
-					changed = true
-				}
-				break
-			}
-		}
-		if found {
-			break
+	for _, partial := range partials {
+		if _, err := t.Parse(partial); err != nil {
+			return fmt.Errorf("parse partial, err=%w", err)
 		}
 	}
 
-	if !found {
-		lines = append(lines, strings.Split(newData, "\n")...)
-		changed = true
-	}
-
-	if !changed {
-		return false, nil
-	}
-
-	newContent := strings.Join(lines, "\n")
-	return util.FmtAndSave([]byte(newContent), output)
-}
-
-// extractFragments searches for fragments of text in the provided lines that match the given regular expression.
-// It returns an array of tuples, each containing the start and end coordinates of the text fragments found.
-//
-// Parameters:
-//
-// - re: a pointer to a compiled regular expression used to find matches in the lines.
-//
-// - lines: an array of strings to search for matching text fragments.
-//
-// Returns:
-//
-// - [][2]int: an array of tuples, each containing the start and end indices of the found text fragments.
-//
-// - error: an error if no matching fragments are found.
-//
-// Example usage:
-//
-//		re := regexp.MustCompile(`pattern`)
-//		lines := []string{"text1", "text2", "pattern", "text3", "}"}
-//		fragments, err := extractFragments(re, lines)
-//		if err != nil {
-//	    log.Fatal(err)
-//		}
-//
-// fmt.Println(fragments) // [[2, 5]]
-func extractFragments(re *regexp.Regexp, lines []string, openingToken, closeningToken string) [][2]int {
-	var (
-		reFragments [][2]int
-		start       = -1
-	)
-
-	for i, line := range lines {
-		if re.MatchString(line) {
-			if start != -1 {
-				// added fragment in slice
-				reFragments = append(reFragments, [2]int{start, i})
-			}
-			// first coordinate in fragment
-			start = i
-		}
-	}
-
-	// if the last fragment is not closed in the loop
-	// think that this fragment is to the end of the lines
-	if start != -1 {
-		reFragments = append(reFragments, [2]int{start, len(lines)})
-	}
-
-	if len(reFragments) == 0 {
-		return nil
-	}
-
-	var ff [][2]int
-
-	checkOpeningCloseninigTokens := openingToken != closeningToken
-
-	reInlineOpeningAndClosing := regexp.MustCompile(`\{.*}$`)
-	// split big fragment
-	for _, fragment := range reFragments {
-		ll := lines[fragment[0]:fragment[1]]
-		var subStart = fragment[0]
-
-		// How many closening tokens we need to ignore
-		var openTokenCnt int
-
-		for i, line := range ll {
-			if !checkOpeningCloseninigTokens {
-				if line == "" {
-					continue
-				}
-				ff = append(ff, [2]int{subStart, fragment[0] + i + 1})
-				subStart = fragment[0] + i + 1
-				continue
-			}
-
-			if reInlineOpeningAndClosing.MatchString(line) {
-				continue
-			}
-
-			if strings.HasSuffix(line, openingToken) {
-				openTokenCnt++
-				continue
-			}
-			if strings.HasSuffix(line, closeningToken) {
-				openTokenCnt--
-				if openTokenCnt > 0 {
-					continue
-				}
-
-				ff = append(ff, [2]int{subStart, fragment[0] + i + 1})
-				subStart = fragment[0] + i + 1
-			}
-		}
-	}
-
-	return ff
+	return t.Execute(wr, data)
 }
 
-// UpdateFile updates a file by replacing specific fragments with new data generated from a template.
-//
-// Parameters:
-//   - buffer: consists a rendered template.
-//   - output: the path to the file where the replacement will take place.
-//   - pattern: a pointer to a compiled regular expression used to find the fragments.
-//
-// Returns:
-//   - bool: true if the update was successful, and false otherwise.
-//   - error: an error if there was an issue generating the data, replacing the fragments, or at other stages.
-//
-// Example usage:
-//
-//		buffer := ... // data for the template
-//		output := "path/to/file.txt"
-//		pattern := regexp.MustCompile(`pattern`)
-//		success, err := UpdateFile(data, output, tmpl, pattern)
-//		if err != nil {
-//	    log.Fatal(err)
-//		}
-//
-// fmt.Println("Update successful:", success)
-func UpdateFile(buffer *bytes.Buffer, output, openingToken, closeningToken string, pattern *regexp.Regexp, force bool) (bool, error) {
-	// get []string from generate template
-	lines := strings.Split(buffer.String(), "\n")
-
-	// search fragment from our template
-	fragments := extractFragments(pattern, lines, openingToken, closeningToken)
-	if len(fragments) == 0 {
-		return false, errors.New("no reFragments found with pattern in the new content")
-	}
-
-	for _, fragment := range fragments {
-		var filePart []string
-		var findRow string
-
-		filePart = append(filePart, lines[fragment[0]:fragment[1]]...)
-		for _, part := range filePart {
-			if isStartedRow(part) {
-				findRow = part
-				break
-			}
-		}
-		if _, err := replaceFragmentInFile(output, strings.TrimSuffix(findRow, " "), strings.Join(filePart, "\n"), openingToken, closeningToken, pattern, force); err != nil {
-			return false, fmt.Errorf("replace fragment error: %w", err)
+func RenderText(wr io.Writer, tmpl string, data any, partials ...string) error {
+	t := textTemplate.Must(textTemplate.New("base").Funcs(TemplateFunctions).Parse(tmpl))
+	for _, partial := range partials {
+		if _, err := t.Parse(partial); err != nil {
+			return fmt.Errorf("parse partial, err=%w", err)
 		}
 	}
 
-	return true, nil
-}
-
-// isStartedRow find row where start function or structure
-func isStartedRow(row string) bool {
-	if (strings.Contains(row, "func") || strings.Contains(row, "struct")) && !strings.Contains(row, "//") {
-		return true
-	}
-	return false
+	return t.Execute(wr, data)
 }
 
 func GoFileName(namespace string) string {
@@ -457,17 +202,9 @@ func GoFileName(namespace string) string {
 	return strings.ToLower(namespace)
 }
 
+// Save writes content to file, in dry run mode file is kept in memory
 func Save(content []byte, filename string) (bool, error) {
-	file, err := util.File(filename)
-	if err != nil {
-		return false, fmt.Errorf("open model file, err=%w", err)
-	}
-
-	if _, err := file.Write(content); err != nil {
-		return false, fmt.Errorf("writing content to file, err=%w", err)
-	}
-
-	return true, nil
+	return NewFiles(nil).Save(content, filename)
 }
 
 func LoadTranslations(project string, languages []string) (map[string]Translation, error) {
@@ -478,8 +215,8 @@ func LoadTranslations(project string, languages []string) (map[string]Translatio
 			Language: lang,
 		}
 
-		filename := path.Join(filepath.Dir(project), lang+".xml")
-		if _, err := os.Stat(filename); os.IsNotExist(err) {
+		filename := TranslationFile(project, lang)
+		if !FileExists(filename) {
 			translations[lang] = translation
 			continue
 		}
@@ -495,20 +232,11 @@ func LoadTranslations(project string, languages []string) (map[string]Translatio
 }
 
 func SaveTranslation(translation Translation, project, language string) error {
-	filename := path.Join(filepath.Dir(project), language+".xml")
-	return MarshalToFile(filename, translation)
+	return NewFiles(nil).SaveTranslation(translation, project, language)
 }
 
 func MarshalJSONToFile(filename string, v interface{}) error {
-	bytes, err := json.MarshalIndent(v, "", "    ")
-	if err != nil {
-		return fmt.Errorf("marshal data, err=%w", err)
-	}
-
-	if _, err := Save(bytes, filename); err != nil {
-		return err
-	}
-	return nil
+	return NewFiles(nil).MarshalJSONToFile(filename, v)
 }
 
 func LoadTemplate(path, def string) (string, error) {
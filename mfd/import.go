@@ -0,0 +1,170 @@
+package mfd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dizzyfool/genna/util"
+)
+
+// ProjectImport is xml element, declares namespaces imported from another mfd project.
+// Imported namespaces are read-only: they are neither saved nor generated,
+// entities of project may refer to them by fks and foreign searches.
+type ProjectImport struct {
+	// path to imported mfd project, relative to project file
+	Path string `xml:"Path,attr" json:"path"`
+
+	// comma separated imported namespaces
+	Namespaces string `xml:"Namespaces,attr" json:"namespaces"`
+
+	// go import path of model package generated for imported project
+	Package string `xml:"Package,attr,omitempty" json:"package,omitempty"`
+
+	// go package alias used for imported model package, mfd file name is used by default
+	Alias string `xml:"Alias,attr,omitempty" json:"alias,omitempty"`
+}
+
+// NamespaceNames returns imported namespaces
+func (i ProjectImport) NamespaceNames() []string {
+	return splitList(i.Namespaces)
+}
+
+// GoAlias returns alias of imported go package, e.g. common for ../common/common.mfd
+func (i ProjectImport) GoAlias() string {
+	if i.Alias != "" {
+		return i.Alias
+	}
+
+	name := strings.TrimSuffix(path.Base(filepath.ToSlash(i.Path)), path.Ext(i.Path))
+	return strings.ToLower(util.Sanitize(name))
+}
+
+// GoType returns go type of imported entity with package alias, e.g. common.City
+func (i ProjectImport) GoType(entity string) string {
+	return i.GoAlias() + "." + entity
+}
+
+// ProjectImports returns imports declared in project
+func (p *Project) ProjectImports() []ProjectImport {
+	if p.Imports == nil {
+		return nil
+	}
+
+	return *p.Imports
+}
+
+// ImportedNamespace returns imported mfd.Namespace by its name
+func (p *Project) ImportedNamespace(namespace string) *Namespace {
+	for _, ns := range p.ImportedNamespaces {
+		if strings.EqualFold(ns.Name, namespace) {
+			return ns
+		}
+	}
+
+	return nil
+}
+
+// ImportedEntityByTable returns imported mfd.Entity by its table
+func (p *Project) ImportedEntityByTable(table string) *Entity {
+	for _, n := range p.ImportedNamespaces {
+		if e := n.EntityByTable(table); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// IsImported checks if entity is loaded from imported namespace
+func (e *Entity) IsImported() bool {
+	return e != nil && e.Import != nil
+}
+
+// loadImports loads imported namespaces of project, projects being loaded are used to detect import cycles
+func (p *Project) loadImports(filename string, goPGVer int, loading map[string]struct{}) error {
+	p.ImportedNamespaces = []*Namespace{}
+
+	imports := p.ProjectImports()
+	for i := range imports {
+		imp := &imports[i]
+
+		importPath := imp.Path
+		if !filepath.IsAbs(importPath) {
+			importPath = filepath.Join(filepath.Dir(filename), importPath)
+		}
+
+		imported, err := loadProject(importPath, goPGVer, loading)
+		if err != nil {
+			return fmt.Errorf("import %s, err=%w", imp.Path, err)
+		}
+
+		for _, name := range imp.NamespaceNames() {
+			ns := imported.Namespace(name)
+			if ns == nil {
+				ns = imported.ImportedNamespace(name)
+			}
+			if ns == nil {
+				return fmt.Errorf("namespace %s not found in imported project %s", name, imp.Path)
+			}
+
+			for _, entity := range ns.Entities {
+				// entities imported transitively keep their own import
+				if entity.Import == nil {
+					entity.Import = imp
+				}
+			}
+			p.ImportedNamespaces = append(p.ImportedNamespaces, ns)
+		}
+	}
+
+	return nil
+}
+
+// IsConsistentImports checks that imported namespaces and entities do not clash with project ones
+// and that go package of imported model is set
+func (p *Project) IsConsistentImports() error {
+	for _, imp := range p.ProjectImports() {
+		if imp.Path == "" || len(imp.NamespaceNames()) == 0 {
+			return fmt.Errorf("import %s: path and namespaces should be set", imp.Path)
+		}
+		if imp.Package == "" {
+			return fmt.Errorf("import %s: go package of imported model is not set", imp.Path)
+		}
+	}
+
+	for _, ns := range p.ImportedNamespaces {
+		if p.Namespace(ns.Name) != nil {
+			return fmt.Errorf("imported namespace %s is declared in project", ns.Name)
+		}
+
+		for _, entity := range ns.Entities {
+			if own := p.ownEntity(entity.Name); own != nil {
+				return fmt.Errorf("entity %s from imported namespace %s is declared in %s namespace", entity.Name, ns.Name, own.Namespace)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ownEntity returns mfd.Entity by its name skipping imported namespaces
+func (p *Project) ownEntity(entity string) *Entity {
+	for _, n := range p.Namespaces {
+		if e := n.Entity(entity); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// readOnlyError returns error if entity is imported from another project
+func readOnlyError(entity *Entity) error {
+	if entity.IsImported() {
+		return fmt.Errorf("entity %s is imported from %s and is read-only", entity.Name, entity.Import.Path)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package mfd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func saveImportProject(t *testing.T, filename string, project *Project) {
+	t.Helper()
+
+	if err := SaveMFD(filename, project); err != nil {
+		t.Fatalf("save project: %v", err)
+	}
+	if err := SaveProjectXML(filename, project); err != nil {
+		t.Fatalf("save namespaces: %v", err)
+	}
+}
+
+func importProjects(t *testing.T) (string, *Project) {
+	t.Helper()
+	dir := t.TempDir()
+
+	common := NewProject("common", GoPG10)
+	common.AddEntity("geo", &Entity{Name: "City", Namespace: "geo", Table: "cities", Attributes: Attributes{
+		{Name: "ID", DBName: "cityId", DBType: "int4", GoType: "int", PrimaryKey: true, Null: NullableNo},
+		{Name: "Title", DBName: "title", DBType: "varchar", GoType: "string", Null: NullableNo},
+	}})
+	saveImportProject(t, filepath.Join(dir, "common", "common.mfd"), common)
+
+	project := NewProject("portal", GoPG10)
+	project.Imports = &[]ProjectImport{{Path: "../common/common.mfd", Namespaces: "geo", Package: "example.com/common/pkg/db"}}
+	project.AddEntity("portal", &Entity{Name: "Place", Namespace: "portal", Table: "places",
+		Attributes: Attributes{
+			{Name: "ID", DBName: "placeId", DBType: "int4", GoType: "int", PrimaryKey: true, Null: NullableNo},
+			{Name: "CityID", DBName: "cityId", DBType: "int4", GoType: "int", ForeignKey: "City", Null: NullableNo},
+		},
+		Searches: Searches{{Name: "CityTitle", AttrName: "City.Title", SearchType: SearchILike}},
+	})
+	filename := filepath.Join(dir, "portal", "portal.mfd")
+	saveImportProject(t, filename, project)
+
+	return filename, project
+}
+
+func TestLoadProject_Imports(t *testing.T) {
+	filename, _ := importProjects(t)
+
+	project, err := LoadProject(filename, false, GoPG10)
+	if err != nil {
+		t.Fatalf("LoadProject() error = %v", err)
+	}
+
+	if project.Namespace("geo") != nil || project.ImportedNamespace("geo") == nil {
+		t.Fatalf("imported namespace should be loaded read-only")
+	}
+
+	city := project.Entity("City")
+	if !city.IsImported() || city.Import.GoType(city.Name) != "common.City" {
+		t.Errorf("imported entity = %+v", city)
+	}
+
+	place := project.Entity("Place")
+	if place.IsImported() || place.AttributeByName("CityID").ForeignEntity != city {
+		t.Errorf("fk to imported entity is not linked")
+	}
+	if search := place.SearchByName("CityTitle"); search.Entity != city || search.Attribute == nil {
+		t.Errorf("foreign search to imported entity is not linked")
+	}
+
+	if err := project.RenameEntity("City", "Town"); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("RenameEntity() of imported entity error = %v", err)
+	}
+	if err := project.MoveEntity("Place", "geo"); err == nil {
+		t.Errorf("MoveEntity() to imported namespace should fail")
+	}
+}
+
+func TestProject_IsConsistentImports(t *testing.T) {
+	filename, project := importProjects(t)
+
+	// entity declared in both projects
+	project.AddEntity("portal", &Entity{Name: "City", Namespace: "portal", Table: "cities"})
+	saveImportProject(t, filename, project)
+	if _, err := LoadProject(filename, false, GoPG10); err == nil {
+		t.Errorf("LoadProject() should fail for entity declared in imported namespace")
+	}
+
+	// namespace not found in imported project
+	project.Namespace("portal").Entities = project.Namespace("portal").Entities[:1]
+	project.Imports = &[]ProjectImport{{Path: "../common/common.mfd", Namespaces: "geo,users", Package: "example.com/common/pkg/db"}}
+	saveImportProject(t, filename, project)
+	if _, err := LoadProject(filename, false, GoPG10); err == nil {
+		t.Errorf("LoadProject() should fail for unknown imported namespace")
+	}
+
+	// import cycle
+	project.Imports = &[]ProjectImport{{Path: "portal.mfd", Namespaces: "portal", Package: "example.com/portal/pkg/db"}}
+	saveImportProject(t, filename, project)
+	if _, err := LoadProject(filename, false, GoPG10); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("LoadProject() error = %v, want import cycle", err)
+	}
+}
@@ -0,0 +1,61 @@
+package mfd
+
+import "strings"
+
+// entityIndex stores entities of namespace by lower case name and by table
+type entityIndex struct {
+	names  map[string]*Entity
+	tables map[string]*Entity
+
+	// count of indexed entities, index is rebuilt when entities are added or removed
+	count int
+}
+
+// newEntityIndex indexes entities, the first entity wins for duplicated names and tables as in linear search
+func newEntityIndex(entities []*Entity) *entityIndex {
+	index := &entityIndex{
+		names:  make(map[string]*Entity, len(entities)),
+		tables: make(map[string]*Entity, len(entities)),
+		count:  len(entities),
+	}
+
+	for _, entity := range entities {
+		name := strings.ToLower(entity.Name)
+		if _, ok := index.names[name]; !ok {
+			index.names[name] = entity
+		}
+		if _, ok := index.tables[entity.Table]; !ok {
+			index.tables[entity.Table] = entity
+		}
+	}
+
+	return index
+}
+
+// entities returns index of namespace entities, it is built on first lookup and rebuilt if entities were added or removed
+func (n *Namespace) entities() *entityIndex {
+	if n.index == nil || n.index.count != len(n.Entities) {
+		n.index = newEntityIndex(n.Entities)
+	}
+
+	return n.index
+}
+
+// lookup returns indexed entity if it still matches. Index is rebuilt if indexed entity was renamed or replaced in place,
+// entities renamed in place are found by new name only after reindex.
+func (n *Namespace) lookup(indexed *Entity, match func(entity *Entity) bool) *Entity {
+	if indexed == nil {
+		return nil
+	}
+	if !match(indexed) {
+		n.reindex()
+		return nil
+	}
+
+	return indexed
+}
+
+// reindex drops index of namespace entities, it should be called when entities are replaced or renamed in place
+func (n *Namespace) reindex() {
+	n.index = nil
+}
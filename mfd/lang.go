@@ -8,6 +8,7 @@ type Translation struct {
 	XMLName    xml.Name                `xml:"Translation" json:"-"`
 	XMLxsi     string                  `xml:"xmlns:xsi,attr" json:"-"`
 	XMLxsd     string                  `xml:"xmlns:xsd,attr" json:"-"`
+	XMLSchema  string                  `xml:"xsi:noNamespaceSchemaLocation,attr,omitempty" json:"-"`
 	Language   string                  `xml:"Language" json:"language"`
 	Namespaces []*TranslationNamespace `xml:"Namespaces>Namespace" json:"namespaces"`
 }
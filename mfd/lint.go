@@ -0,0 +1,49 @@
+package mfd
+
+import "strings"
+
+// LintConfig is xml element, stores project settings for lint command
+type LintConfig struct {
+	Rules []LintRule `xml:"Rule" json:"rules"`
+}
+
+// LintRule is xml element, overrides rule severity and suppresses its findings
+type LintRule struct {
+	Name     string `xml:"Name,attr" json:"name"`
+	Severity string `xml:"Severity,attr,omitempty" json:"severity"`
+
+	// locations like portal.News.Title, trailing * matches any location with the prefix
+	Ignore []string `xml:"Ignore,omitempty" json:"ignore"`
+}
+
+// Rule returns settings of lint rule by its name
+func (c *LintConfig) Rule(name string) *LintRule {
+	if c == nil {
+		return nil
+	}
+
+	for i := range c.Rules {
+		if c.Rules[i].Name == name {
+			return &c.Rules[i]
+		}
+	}
+
+	return nil
+}
+
+// IsIgnored checks if findings of the rule in location are suppressed
+func (r *LintRule) IsIgnored(location string) bool {
+	if r == nil {
+		return false
+	}
+
+	for _, pattern := range r.Ignore {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(location, prefix) {
+			return true
+		} else if pattern == location {
+			return true
+		}
+	}
+
+	return false
+}
@@ -66,6 +66,20 @@ func (m *XMLMap) Delete(key string) {
 	}
 }
 
+// Keys returns map keys in order of appending
+func (m *XMLMap) Keys() []string {
+	if m == nil {
+		return nil
+	}
+
+	keys := make([]string, len(m.elements))
+	for i, el := range m.elements {
+		keys[i] = el.XMLName.Local
+	}
+
+	return keys
+}
+
 func (m *XMLMap) Index(key string) int {
 	for i, el := range m.elements {
 		if el.XMLName.Local == key {
@@ -122,3 +136,14 @@ func (m *XMLMap) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	}
 	return nil
 }
+
+// Rename renames key keeping its position and value
+func (m *XMLMap) Rename(key, newKey string) {
+	if m == nil || key == newKey {
+		return
+	}
+
+	if i := m.Index(key); i != -1 && m.Index(newKey) == -1 {
+		m.elements[i].XMLName.Local = newKey
+	}
+}
@@ -0,0 +1,560 @@
+package mfd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// KeepMarker marks declaration or struct field which is not overwritten by generators
+	KeepMarker = "mfd:keep"
+	// KeepBeginMarker starts protected block, its content is not overwritten by generators
+	KeepBeginMarker = "mfd:keep:begin"
+	// KeepEndMarker ends protected block
+	KeepEndMarker = "mfd:keep:end"
+
+	// KeepHelp describes protected code in help of generators which overwrite go files
+	KeepHelp = "Generated declarations in go files are overwritten. Declarations and struct fields marked with // mfd:keep comment\n" +
+		"and code between // mfd:keep:begin and // mfd:keep:end comments are kept, declarations and struct fields added by hand are kept too.\n" +
+		"Declarations which are no longer generated stay in the file and should be removed by hand."
+)
+
+// goSource is a parsed go file or rendered fragment of declarations
+type goSource struct {
+	src  []byte
+	fset *token.FileSet
+	file *ast.File
+
+	// keys are declaration keys in source order, e.g. "func (News) ToDB", "type News"
+	keys  []string
+	decls map[string]ast.Decl
+
+	// blocks are contents of protected blocks by declaration key and index
+	blocks map[string]span
+}
+
+// span is a range of bytes in source
+type span struct {
+	start, end int
+}
+
+func (s span) contains(o span) bool {
+	return s.start <= o.start && o.end <= s.end
+}
+
+// edit replaces span of source with text
+type edit struct {
+	span
+	text string
+}
+
+var (
+	packageHeader   = []byte("package mfd\n\n")
+	packageClauseRe = regexp.MustCompile(`(?m)^package .*$`)
+)
+
+// parseGoSource parses go source, rendered fragment may have no package clause
+func parseGoSource(src []byte, fragment bool) (*goSource, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil && fragment {
+		// package clause of rendered code may be missing or have package name which is not valid go identifier
+		body := src
+		if loc := packageClauseRe.FindIndex(src); loc != nil {
+			body = append(append([]byte{}, src[:loc[0]]...), src[loc[1]:]...)
+		}
+		withPackage := append(append([]byte{}, packageHeader...), body...)
+		fset = token.NewFileSet()
+		if f, ferr := parser.ParseFile(fset, "", withPackage, parser.ParseComments); ferr == nil {
+			file, src, err = f, withPackage, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &goSource{src: src, fset: fset, file: file, decls: map[string]ast.Decl{}, blocks: map[string]span{}}
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+
+		key := declKey(decl)
+		for i := 2; s.decls[key] != nil; i++ {
+			key = fmt.Sprintf("%s#%d", declKey(decl), i)
+		}
+		s.keys = append(s.keys, key)
+		s.decls[key] = decl
+	}
+	s.readBlocks()
+
+	return s, nil
+}
+
+// readBlocks finds protected blocks, blocks are numbered inside its declaration
+func (s *goSource) readBlocks() {
+	count := map[string]int{}
+	start := -1
+	for _, group := range s.file.Comments {
+		for _, c := range group.List {
+			switch {
+			case isMarker(c.Text, KeepBeginMarker):
+				start = s.lineEnd(s.offset(c.End()))
+			case isMarker(c.Text, KeepEndMarker) && start != -1:
+				key := s.enclosingKey(c.Pos())
+				s.blocks[key+"#"+strconv.Itoa(count[key])] = span{start: start, end: s.lineStart(s.offset(c.Pos()))}
+				count[key]++
+				start = -1
+			}
+		}
+	}
+}
+
+func (s *goSource) offset(pos token.Pos) int {
+	return s.fset.Position(pos).Offset
+}
+
+func (s *goSource) text(sp span) string {
+	return string(s.src[sp.start:sp.end])
+}
+
+func (s *goSource) lineStart(offset int) int {
+	return bytes.LastIndexByte(s.src[:offset], '\n') + 1
+}
+
+func (s *goSource) lineEnd(offset int) int {
+	if i := bytes.IndexByte(s.src[offset:], '\n'); i != -1 {
+		return offset + i + 1
+	}
+
+	return len(s.src)
+}
+
+// declSpan returns span of declaration with its doc comment
+func (s *goSource) declSpan(decl ast.Decl) span {
+	start := decl.Pos()
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Doc != nil {
+			start = d.Doc.Pos()
+		}
+	case *ast.GenDecl:
+		if d.Doc != nil {
+			start = d.Doc.Pos()
+		}
+	}
+
+	return span{start: s.offset(start), end: s.offset(decl.End())}
+}
+
+// fieldSpan returns span of struct field with its doc and line comments
+func (s *goSource) fieldSpan(field *ast.Field) span {
+	start, end := field.Pos(), field.End()
+	if field.Doc != nil {
+		start = field.Doc.Pos()
+	}
+	if field.Comment != nil {
+		end = field.Comment.End()
+	}
+
+	return span{start: s.offset(start), end: s.offset(end)}
+}
+
+func (s *goSource) enclosingKey(pos token.Pos) string {
+	offset := s.offset(pos)
+	for _, key := range s.keys {
+		if sp := s.declSpan(s.decls[key]); sp.start <= offset && offset < sp.end {
+			return key
+		}
+	}
+
+	return ""
+}
+
+// MergeGo merges existing go file into newly generated code:
+// declarations and struct fields marked with mfd:keep and contents of protected blocks are taken from existing file,
+// declarations and struct fields missing in generated code are kept, declarations are appended with imports they use.
+func MergeGo(existing, generated []byte) ([]byte, error) {
+	gen, err := parseGoSource(generated, false)
+	if err != nil {
+		// generated code with syntax errors is saved as is
+		return generated, nil
+	}
+
+	old, err := parseGoSource(existing, false)
+	if err != nil {
+		return nil, fmt.Errorf("parse existing file, err=%w", err)
+	}
+
+	var edits []edit
+	var kept []span
+	for _, key := range gen.keys {
+		decl := gen.decls[key]
+		if od, ok := old.decls[key]; ok && isKept(od) {
+			sp := old.declSpan(od)
+			edits = append(edits, edit{span: gen.declSpan(decl), text: old.text(sp)})
+			kept = append(kept, sp)
+			continue
+		}
+
+		e, k := gen.mergeDecl(key, old)
+		edits, kept = append(edits, e...), append(kept, k...)
+	}
+
+	// protected blocks outside of declarations
+	for key, sp := range gen.blocks {
+		if osp, ok := old.blocks[key]; ok && strings.HasPrefix(key, "#") {
+			edits = append(edits, edit{span: sp, text: old.text(osp)})
+			kept = append(kept, osp)
+		}
+	}
+
+	var tail []string
+	for _, key := range old.keys {
+		if od := old.decls[key]; gen.decls[key] == nil {
+			sp := old.declSpan(od)
+			tail = append(tail, old.text(sp))
+			kept = append(kept, sp)
+		}
+	}
+
+	edits = append(edits, gen.importEdit(old.usedImports(gen, kept)))
+
+	return appendDecls(applyEdits(gen.src, edits), tail), nil
+}
+
+// updateGo merges rendered declarations into existing file:
+// missing declarations are added, existing are replaced if force is set.
+// Existing declarations marked with mfd:keep are not replaced, contents of protected blocks are kept.
+func updateGo(existing, generated []byte, force bool) ([]byte, bool, error) {
+	old, err := parseGoSource(existing, false)
+	if err != nil {
+		return nil, false, fmt.Errorf("parse existing file, err=%w", err)
+	}
+
+	gen, err := parseGoSource(generated, true)
+	if err != nil {
+		return nil, false, fmt.Errorf("parse generated code, err=%w", err)
+	}
+	if len(gen.keys) == 0 {
+		return nil, false, errors.New("no declarations found in the new content")
+	}
+
+	var edits []edit
+	var added []span
+	var tail []string
+	for _, key := range gen.keys {
+		decl := gen.decls[key]
+		sp := gen.declSpan(decl)
+
+		od, ok := old.decls[key]
+		if !ok {
+			tail = append(tail, gen.text(sp))
+			added = append(added, sp)
+			continue
+		}
+		if !force || isKept(od) {
+			continue
+		}
+
+		// protected blocks and fields of existing declaration are merged into generated one
+		e, _ := gen.mergeDecl(key, old)
+		for i := range e {
+			e[i].start, e[i].end = e[i].start-sp.start, e[i].end-sp.start
+		}
+		edits = append(edits, edit{span: old.declSpan(od), text: string(applyEdits(gen.src[sp.start:sp.end], e))})
+		added = append(added, sp)
+	}
+
+	if len(edits) == 0 && len(tail) == 0 {
+		return existing, false, nil
+	}
+
+	edits = append(edits, old.importEdit(gen.usedImports(old, added)))
+
+	return appendDecls(applyEdits(old.src, edits), tail), true, nil
+}
+
+// mergeDecl returns edits of generated declaration restoring protected blocks, marked struct fields
+// and struct fields missing in generated declaration of existing one,
+// spans of existing source used by edits are returned too
+func (s *goSource) mergeDecl(key string, old *goSource) ([]edit, []span) {
+	var edits []edit
+	var kept []span
+
+	sp := s.declSpan(s.decls[key])
+	for blockKey, bsp := range s.blocks {
+		if !strings.HasPrefix(blockKey, key+"#") || !sp.contains(bsp) {
+			continue
+		}
+		if osp, ok := old.blocks[blockKey]; ok {
+			edits = append(edits, edit{span: bsp, text: old.text(osp)})
+			kept = append(kept, osp)
+		}
+	}
+
+	gd, ok := s.decls[key].(*ast.GenDecl)
+	od, ok2 := old.decls[key].(*ast.GenDecl)
+	if !ok || !ok2 || gd.Tok != token.TYPE {
+		return edits, kept
+	}
+
+	oldStructs := map[string]*ast.StructType{}
+	for _, spec := range od.Specs {
+		if ts, ok := spec.(*ast.TypeSpec); ok {
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				oldStructs[ts.Name.Name] = st
+			}
+		}
+	}
+
+	for _, spec := range gd.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok || oldStructs[ts.Name.Name] == nil {
+			continue
+		}
+
+		fields := map[string]*ast.Field{}
+		for _, field := range st.Fields.List {
+			fields[fieldName(field)] = field
+		}
+
+		// fields missing in generated struct are appended in order of existing struct
+		var appended []string
+		for _, field := range oldStructs[ts.Name.Name].Fields.List {
+			gf, ok := fields[fieldName(field)]
+			if ok && !hasMarker(KeepMarker, field.Doc, field.Comment) {
+				continue
+			}
+
+			fsp := old.fieldSpan(field)
+			if ok {
+				edits = append(edits, edit{span: s.fieldSpan(gf), text: old.text(fsp)})
+			} else {
+				appended = append(appended, old.text(fsp))
+			}
+			kept = append(kept, fsp)
+		}
+		if len(appended) == 0 {
+			continue
+		}
+
+		text := strings.Join(appended, "\n") + "\n"
+		closing := s.lineStart(s.offset(st.Fields.Closing))
+		if closing <= s.offset(st.Fields.Opening) {
+			closing, text = s.offset(st.Fields.Closing), "\n"+text
+		}
+		edits = append(edits, edit{span: span{start: closing, end: closing}, text: text})
+	}
+
+	return edits, kept
+}
+
+// usedImports returns imports of source missing in target which are used in spans,
+// imports with names already used in target are skipped
+func (s *goSource) usedImports(target *goSource, spans []span) []string {
+	paths, names := map[string]struct{}{}, map[string]struct{}{}
+	for _, spec := range target.file.Imports {
+		paths[spec.Path.Value] = struct{}{}
+		names[importName(spec)] = struct{}{}
+	}
+
+	used := map[string]struct{}{}
+	ast.Inspect(s.file, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			pos := span{start: s.offset(ident.Pos()), end: s.offset(ident.End())}
+			for _, sp := range spans {
+				if sp.contains(pos) {
+					used[ident.Name] = struct{}{}
+					break
+				}
+			}
+		}
+		return true
+	})
+
+	var imports []string
+	for _, spec := range s.file.Imports {
+		if _, ok := paths[spec.Path.Value]; ok {
+			continue
+		}
+		if _, ok := names[importName(spec)]; ok {
+			continue
+		}
+		if _, ok := used[importName(spec)]; ok {
+			sp := span{start: s.offset(spec.Pos()), end: s.offset(spec.End())}
+			imports = append(imports, s.text(sp))
+		}
+	}
+
+	return imports
+}
+
+// importEdit returns edit adding imports to source
+func (s *goSource) importEdit(imports []string) edit {
+	if len(imports) == 0 {
+		return edit{span: span{start: -1}}
+	}
+
+	for _, decl := range s.file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && gen.Lparen.IsValid() {
+			offset := s.lineStart(s.offset(gen.Rparen))
+			if offset <= s.offset(gen.Lparen) {
+				offset = s.offset(gen.Rparen)
+				return edit{span: span{start: offset, end: offset}, text: "\n" + strings.Join(imports, "\n") + "\n"}
+			}
+			return edit{span: span{start: offset, end: offset}, text: "\t" + strings.Join(imports, "\n\t") + "\n"}
+		}
+	}
+
+	offset := s.offset(s.file.Name.End())
+	return edit{span: span{start: offset, end: offset}, text: "\n\nimport (\n" + strings.Join(imports, "\n") + "\n)\n"}
+}
+
+// applyEdits applies not overlapping edits to source, edits with negative start are skipped
+func applyEdits(src []byte, edits []edit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+
+	result := append([]byte{}, src...)
+	for _, e := range edits {
+		if e.start < 0 {
+			continue
+		}
+		result = append(result[:e.start], append([]byte(e.text), result[e.end:]...)...)
+	}
+
+	return result
+}
+
+func appendDecls(src []byte, decls []string) []byte {
+	if len(decls) == 0 {
+		return src
+	}
+
+	return append(bytes.TrimRight(src, "\n"), []byte("\n\n"+strings.Join(decls, "\n\n")+"\n")...)
+}
+
+// declKey returns name of declaration with kind, methods are prefixed by receiver type
+func declKey(decl ast.Decl) string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			return fmt.Sprintf("func (%s) %s", recvType(d.Recv.List[0].Type), d.Name.Name)
+		}
+		return "func " + d.Name.Name
+	case *ast.GenDecl:
+		var names []string
+		for _, spec := range d.Specs {
+			switch sp := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, sp.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range sp.Names {
+					names = append(names, name.Name)
+				}
+			}
+		}
+		return d.Tok.String() + " " + strings.Join(names, ",")
+	}
+
+	return ""
+}
+
+func recvType(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return recvType(e.X)
+	case *ast.IndexExpr:
+		return recvType(e.X)
+	case *ast.IndexListExpr:
+		return recvType(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+
+	return types.ExprString(expr)
+}
+
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+
+	return types.ExprString(field.Type)
+}
+
+var versionSuffixRe = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns name of imported package, it is guessed from path if import has no alias
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	p, _ := strconv.Unquote(spec.Path.Value)
+	name := path.Base(p)
+	if versionSuffixRe.MatchString(name) && path.Dir(p) != "." {
+		name = path.Base(path.Dir(p))
+	}
+	name, _, _ = strings.Cut(name, ".")
+
+	return strings.TrimPrefix(name, "go-")
+}
+
+func isKept(decl ast.Decl) bool {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return hasMarker(KeepMarker, d.Doc)
+	case *ast.GenDecl:
+		return hasMarker(KeepMarker, d.Doc)
+	}
+
+	return false
+}
+
+func hasMarker(marker string, groups ...*ast.CommentGroup) bool {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, c := range group.List {
+			if isMarker(c.Text, marker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isMarker checks if comment is a marker, text after marker is allowed, e.g. "// mfd:keep custom sort"
+func isMarker(comment, marker string) bool {
+	text := strings.TrimSpace(strings.TrimPrefix(comment, "//"))
+	return text == marker || strings.HasPrefix(text, marker+" ")
+}
+
+// UpdateFile updates go file with rendered declarations: missing declarations are added,
+// existing are replaced if force is set. Declarations marked with mfd:keep and protected blocks are kept.
+//
+// Deprecated: declarations are found with go/ast, tokens and pattern are ignored. Use Files.UpdateFile.
+func UpdateFile(buffer *bytes.Buffer, output, openingToken, closeningToken string, pattern *regexp.Regexp, force bool) (bool, error) {
+	return NewFiles(nil).UpdateFile(buffer, output, force)
+}
@@ -0,0 +1,284 @@
+package mfd
+
+import (
+	"go/format"
+	"testing"
+)
+
+func formatted(t *testing.T, src []byte) string {
+	t.Helper()
+
+	b, err := format.Source(src)
+	if err != nil {
+		t.Fatalf("format source: %v\n%s", err, src)
+	}
+
+	return string(b)
+}
+
+func TestMergeGo(t *testing.T) {
+	existing := `package vt
+
+import (
+	"context"
+	"strings"
+)
+
+type News struct {
+	ID    int
+	Title string
+	// mfd:keep
+	Slug  string
+	Old   int
+	Extra bool
+}
+
+func (s NewsService) isValid(ctx context.Context, news News) Validator {
+	var v Validator
+	// custom validation starts here
+	// mfd:keep:begin
+	if strings.TrimSpace(news.Title) == "" {
+		v.Append("title", FieldErrorRequired)
+	}
+	// mfd:keep:end
+	return v
+}
+
+// mfd:keep
+func (s NewsService) dbSort(ops *ViewOps) db.OpFunc {
+	return db.WithSort(db.NewSortField("title", false))
+}
+
+// Publish publishes news.
+// mfd:keep
+func (s NewsService) Publish(ctx context.Context, id int) (bool, error) {
+	return strings.HasPrefix("", ""), nil
+}
+
+func (s NewsService) Removed() {}
+`
+
+	generated := `package vt
+
+import (
+	"context"
+)
+
+type News struct {
+	ID    int
+	Title string
+	Slug  *string
+}
+
+func (s NewsService) dbSort(ops *ViewOps) db.OpFunc {
+	return s.portalRepo.DefaultNewsSort()
+}
+
+func (s NewsService) isValid(ctx context.Context, news News) Validator {
+	var v Validator
+	if news.ID == 0 {
+		v.Append("id", FieldErrorRequired)
+	}
+	// custom validation starts here
+	// mfd:keep:begin
+	// mfd:keep:end
+	return v
+}
+`
+
+	want := `package vt
+
+import (
+	"context"
+	"strings"
+)
+
+type News struct {
+	ID    int
+	Title string
+	// mfd:keep
+	Slug  string
+	Old   int
+	Extra bool
+}
+
+// mfd:keep
+func (s NewsService) dbSort(ops *ViewOps) db.OpFunc {
+	return db.WithSort(db.NewSortField("title", false))
+}
+
+func (s NewsService) isValid(ctx context.Context, news News) Validator {
+	var v Validator
+	if news.ID == 0 {
+		v.Append("id", FieldErrorRequired)
+	}
+	// custom validation starts here
+	// mfd:keep:begin
+	if strings.TrimSpace(news.Title) == "" {
+		v.Append("title", FieldErrorRequired)
+	}
+	// mfd:keep:end
+	return v
+}
+
+// Publish publishes news.
+// mfd:keep
+func (s NewsService) Publish(ctx context.Context, id int) (bool, error) {
+	return strings.HasPrefix("", ""), nil
+}
+
+func (s NewsService) Removed() {}
+`
+
+	got, err := MergeGo([]byte(existing), []byte(generated))
+	if err != nil {
+		t.Fatalf("MergeGo() error = %v", err)
+	}
+	if formatted(t, got) != want {
+		t.Errorf("MergeGo() = \n%s\nwant\n%s", formatted(t, got), want)
+	}
+
+	if _, err := MergeGo([]byte("package vt\n// mfd:keep\nfunc {"), []byte(generated)); err == nil {
+		t.Errorf("MergeGo() should fail for broken existing file")
+	}
+
+	// hand-added declarations are kept without markers
+	got, err = MergeGo([]byte("package vt\n\nfunc Custom() {}\n"), []byte(generated))
+	if err != nil || string(got) != generated+"\nfunc Custom() {}\n" {
+		t.Errorf("MergeGo() without markers = %q, %v", got, err)
+	}
+}
+
+func TestFiles_SaveGo(t *testing.T) {
+	files := NewFiles(NewMemoryOutput())
+	broken := "package vt\n\n// mfd:keep\nfunc {"
+	if _, err := files.Save([]byte(broken), "vt/news.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	// broken file with protected code is not overwritten
+	if _, err := files.SaveGo([]byte("package vt\n\nvar a = 1\n"), "vt/news.go"); err == nil {
+		t.Errorf("SaveGo() should fail for broken file with markers")
+	}
+	if content, err := files.ReadFile("vt/news.go"); err != nil || string(content) != broken {
+		t.Errorf("SaveGo() saved %q, %v", content, err)
+	}
+
+	// broken file without markers is overwritten with generated code
+	if _, err := files.Save([]byte("package vt\n\nfunc {"), "vt/news.go"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := files.SaveGo([]byte("package vt\n\nvar a = 1\n"), "vt/news.go"); err != nil {
+		t.Fatalf("SaveGo() error = %v", err)
+	}
+	if content, err := files.ReadFile("vt/news.go"); err != nil || string(content) != "package vt\n\nvar a = 1\n" {
+		t.Errorf("SaveGo() saved %q, %v", content, err)
+	}
+}
+
+func TestUpdateGo(t *testing.T) {
+	existing := `package test
+
+type NewsOpFunc func(t *testing.T, dbo orm.DB, in *db.News) Cleaner
+
+func News(t *testing.T) {
+	// old
+	// mfd:keep:begin
+	custom()
+	// mfd:keep:end
+}
+
+// mfd:keep
+func Tag(t *testing.T) {
+	// kept
+}
+`
+
+	fragment := `type NewsOpFunc func(t *testing.T, dbo orm.DB, in *db.News) Cleaner
+
+func News(t *testing.T) {
+	// new
+	// mfd:keep:begin
+	// mfd:keep:end
+}
+
+func Tag(t *testing.T) {
+	// new
+}
+
+func Category(t *testing.T) {
+}
+`
+
+	tests := []struct {
+		name  string
+		force bool
+		want  string
+	}{
+		{
+			name:  "add missing",
+			force: false,
+			want: `package test
+
+type NewsOpFunc func(t *testing.T, dbo orm.DB, in *db.News) Cleaner
+
+func News(t *testing.T) {
+	// old
+	// mfd:keep:begin
+	custom()
+	// mfd:keep:end
+}
+
+// mfd:keep
+func Tag(t *testing.T) {
+	// kept
+}
+
+func Category(t *testing.T) {
+}
+`,
+		},
+		{
+			name:  "force",
+			force: true,
+			want: `package test
+
+type NewsOpFunc func(t *testing.T, dbo orm.DB, in *db.News) Cleaner
+
+func News(t *testing.T) {
+	// new
+	// mfd:keep:begin
+	custom()
+	// mfd:keep:end
+}
+
+// mfd:keep
+func Tag(t *testing.T) {
+	// kept
+}
+
+func Category(t *testing.T) {
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := updateGo([]byte(existing), []byte(fragment), tt.force)
+			if err != nil {
+				t.Fatalf("updateGo() error = %v", err)
+			}
+			if !changed || formatted(t, got) != tt.want {
+				t.Errorf("updateGo() = %v\n%s\nwant\n%s", changed, formatted(t, got), tt.want)
+			}
+		})
+	}
+
+	if _, changed, err := updateGo([]byte(existing), []byte("type NewsOpFunc func(t *testing.T, dbo orm.DB, in *db.News) Cleaner\n"), false); err != nil || changed {
+		t.Errorf("updateGo() = %v, %v, want not changed", changed, err)
+	}
+	// rendered package name may be not valid go identifier, e.g. name of output dir
+	if _, changed, err := updateGo([]byte(existing), []byte("package vt-updated\n\nfunc Category(t *testing.T) {}\n"), false); err != nil || !changed {
+		t.Errorf("updateGo() = %v, %v, want changed", changed, err)
+	}
+}
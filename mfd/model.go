@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/dizzyfool/genna/model"
@@ -58,6 +60,14 @@ const (
 	SearchTypeJsonbPath        SearchType = "SEARCHTYPE_JSONB_PATH"
 )
 
+// SearchTypes lists all search types, searches of attribute are saved in this order
+var SearchTypes = []SearchType{
+	SearchEquals, SearchNotEquals, SearchNull, SearchNotNull, SearchGE, SearchLE, SearchG, SearchL,
+	SearchLeftLike, SearchLeftILike, SearchRightLike, SearchRightILike, SearchLike, SearchILike,
+	SearchArray, SearchNotArray, SearchTypeArrayContains, SearchTypeArrayNotContains,
+	SearchTypeArrayContained, SearchTypeArrayIntersect, SearchTypeJsonbPath,
+}
+
 func (si SearchType) String() string {
 	return string(si)
 }
@@ -127,20 +137,29 @@ type NSMapping struct {
 
 // Project is xml element
 type Project struct {
-	XMLName        xml.Name     `xml:"Project" json:"-"`
-	XMLxsi         string       `xml:"xmlns:xsi,attr" json:"-"`
-	XMLxsd         string       `xml:"xmlns:xsd,attr" json:"-"`
-	Name           string       `json:"name"`
-	NamespaceNames []string     `xml:"PackageNames>string" json:"-"`
-	Languages      []string     `xml:"Languages>string" json:"languages"`
-	GoPGVer        int          `xml:"GoPGVer" json:"goPGVer"`
-	CustomTypes    CustomTypes  `xml:"CustomTypes>CustomType,omitempty" json:"customTypes,omitempty"`
-	Dictionary     *Dictionary  `xml:"Dictionary" json:"dict,omitempty"`
-	TableMapping   TableMapping `xml:"TableMapping" json:"tableMapping,omitempty"`
-
-	Namespaces   []*Namespace   `xml:"-" json:"-"`
-	VTNamespaces []*VTNamespace `xml:"-" json:"-"`
-	NSMapping    []NSMapping    `xml:"-" json:"namespaces"`
+	XMLName        xml.Name         `xml:"Project" json:"-"`
+	XMLxsi         string           `xml:"xmlns:xsi,attr" json:"-"`
+	XMLxsd         string           `xml:"xmlns:xsd,attr" json:"-"`
+	XMLSchema      string           `xml:"xsi:noNamespaceSchemaLocation,attr,omitempty" json:"-"`
+	Name           string           `json:"name"`
+	NamespaceNames []string         `xml:"PackageNames>string" json:"-"`
+	Languages      []string         `xml:"Languages>string" json:"languages"`
+	GoPGVer        int              `xml:"GoPGVer" json:"goPGVer"`
+	CustomTypes    CustomTypes      `xml:"CustomTypes>CustomType,omitempty" json:"customTypes,omitempty"`
+	Dictionary     *Dictionary      `xml:"Dictionary" json:"dict,omitempty"`
+	TableMapping   TableMapping     `xml:"TableMapping" json:"tableMapping,omitempty"`
+	Lint           *LintConfig      `xml:"Lint,omitempty" json:"lint,omitempty"`
+	SearchPath     string           `xml:"SearchPath,omitempty" json:"searchPath,omitempty"`
+	Databases      *[]Database      `xml:"Databases>Database,omitempty" json:"databases,omitempty"` // named databases of namespaces
+	Imports        *[]ProjectImport `xml:"Imports>Import,omitempty" json:"imports,omitempty"`       // namespaces imported from other projects
+	Outputs        *[]OutputRule    `xml:"Outputs>Output,omitempty" json:"outputs,omitempty"`       // additional files rendered for each entity
+
+	Namespaces         []*Namespace   `xml:"-" json:"-"`
+	VTNamespaces       []*VTNamespace `xml:"-" json:"-"`
+	ImportedNamespaces []*Namespace   `xml:"-" json:"-"` // read-only namespaces loaded from imported projects
+	NSMapping          []NSMapping    `xml:"-" json:"namespaces"`
+
+	partial bool // only some namespaces are loaded, see LoadProjectNamespaces
 }
 
 type TableMapping struct {
@@ -163,9 +182,8 @@ func (tm *TableMapping) Packages() map[string]string {
 
 	packages := make(map[string]string, len(tm.Entries))
 	for _, entry := range tm.Entries {
-		ss := strings.Split(entry.Value, ",")
-		for _, s := range ss {
-			packages[s] = entry.XMLName.Local
+		for _, s := range splitList(entry.Value) {
+			packages[NormalizeTable(s)] = entry.XMLName.Local
 		}
 	}
 	return packages
@@ -205,9 +223,13 @@ func (p *Project) AddNamespace(namespace string) *Namespace {
 	return ns
 }
 
-// Entity returns mfd.Entity by its name
+// Entity returns mfd.Entity by its name, entities of imported namespaces are also returned
 func (p *Project) Entity(entity string) *Entity {
-	for _, n := range p.Namespaces {
+	if e := p.ownEntity(entity); e != nil {
+		return e
+	}
+
+	for _, n := range p.ImportedNamespaces {
 		if e := n.Entity(entity); e != nil {
 			return e
 		}
@@ -252,6 +274,9 @@ func (p *Project) IsConsistent() error {
 
 	for _, nsName := range p.NamespaceNames {
 		ns := p.Namespace(nsName)
+		if ns == nil && p.partial {
+			continue
+		}
 		if ns == nil {
 			return fmt.Errorf("namespace %s listed in names but not found", nsName)
 		}
@@ -263,6 +288,18 @@ func (p *Project) IsConsistent() error {
 		}
 	}
 
+	if err := p.IsConsistentImports(); err != nil {
+		return err
+	}
+
+	if err := p.IsConsistentDatabases(); err != nil {
+		return err
+	}
+
+	if err := p.IsConsistentOutputs(); err != nil {
+		return err
+	}
+
 	for _, vtNamespace := range p.VTNamespaces {
 		ns := p.Namespace(vtNamespace.Name)
 		if ns == nil {
@@ -309,6 +346,12 @@ func (p *Project) IsConsistentVTEntity(vtEntity *VTEntity, namespace string) err
 			}
 		}
 	}
+
+	for _, tmpl := range vtEntity.TmplAttributes {
+		if tmpl.Children != "" && (tmpl.ChildEntity == nil || tmpl.ChildAttribute == nil) {
+			return fmt.Errorf("children %s not found for template attribute %s in vtEntity %s in %s namespace", tmpl.Children, tmpl.Name, vtEntity.Name, namespace)
+		}
+	}
 	return nil
 }
 
@@ -371,6 +414,14 @@ func (p *Project) SuggestArrayLinks() {
 }
 
 func (p *Project) UpdateLinks() {
+	// entities could be replaced or renamed since last lookup
+	for _, namespace := range p.Namespaces {
+		namespace.reindex()
+	}
+	for _, namespace := range p.ImportedNamespaces {
+		namespace.reindex()
+	}
+
 	// making links
 	for _, namespace := range p.Namespaces {
 		for _, entity := range namespace.Entities {
@@ -401,6 +452,10 @@ func (p *Project) UpdateLinks() {
 
 			for _, tmpl := range vtEntity.TmplAttributes {
 				tmpl.VTAttribute = vtEntity.Attribute(tmpl.AttrName)
+
+				if tmpl.Children != "" {
+					p.updateChildLinks(tmpl)
+				}
 			}
 		}
 	}
@@ -414,6 +469,16 @@ func (p *Project) updateForeignAttr(entity *Entity) {
 	}
 }
 
+func (p *Project) updateChildLinks(tmpl *TmplAttribute) {
+	tmpl.ChildEntity, tmpl.ChildAttribute = nil, nil
+
+	childName, attrName := tmpl.ChildRelation()
+	if child := p.Entity(childName); child != nil {
+		tmpl.ChildEntity = child
+		tmpl.ChildAttribute = child.AttributeByName(attrName)
+	}
+}
+
 func (p *Project) updateSearchLinks(entity *Entity) {
 	for _, search := range entity.Searches {
 		// attach own attribute and entity
@@ -435,6 +500,17 @@ func (p *Project) updateSearchLinks(entity *Entity) {
 	}
 }
 
+// SortCustomTypes orders custom types by db type and go type
+func (p *Project) SortCustomTypes() {
+	sort.SliceStable(p.CustomTypes, func(i, j int) bool {
+		a, b := p.CustomTypes[i], p.CustomTypes[j]
+		if a.DBType != b.DBType {
+			return a.DBType < b.DBType
+		}
+		return a.GoType < b.GoType
+	})
+}
+
 func (p *Project) AddCustomTypes(mapping model.CustomTypeMapping) (newCustomTypes CustomTypes) {
 	for _, customType := range mapping {
 		if customType.GoType == "" {
@@ -517,6 +593,8 @@ func (p *Project) UpdateByNSMapping() {
 		target.Entities = append(target.Entities, entity)
 		index := source.EntityIndex(entity.Name)
 		source.Entities = append(source.Entities[:index], source.Entities[index+1:]...)
+		source.reindex()
+		target.reindex()
 	}
 }
 
@@ -530,12 +608,15 @@ func (p *Project) MarshalJSON() ([]byte, error) {
 
 // Namespace is xml element
 type Namespace struct {
-	XMLName xml.Name `xml:"Package" json:"-"`
-	XMLxsi  string   `xml:"xmlns:xsi,attr"`
-	XMLxsd  string   `xml:"xmlns:xsd,attr"`
-	Name    string
+	XMLName   xml.Name `xml:"Package" json:"-"`
+	XMLxsi    string   `xml:"xmlns:xsi,attr"`
+	XMLxsd    string   `xml:"xmlns:xsd,attr"`
+	XMLSchema string   `xml:"xsi:noNamespaceSchemaLocation,attr,omitempty" json:"-"`
+	Name      string
 
 	Entities []*Entity `xml:"Entities>Entity"`
+
+	index *entityIndex
 }
 
 // NewNamespace creates Namespace
@@ -550,24 +631,16 @@ func NewNamespace(name string) *Namespace {
 
 // Entity returns mfd.Entity by its name
 func (n *Namespace) Entity(entity string) *Entity {
-	for _, e := range n.Entities {
-		if strings.EqualFold(e.Name, entity) {
-			return e
-		}
-	}
-
-	return nil
+	return n.lookup(n.entities().names[strings.ToLower(entity)], func(e *Entity) bool {
+		return strings.EqualFold(e.Name, entity)
+	})
 }
 
 // EntityByTable returns mfd.Entity by table name
 func (n *Namespace) EntityByTable(table string) *Entity {
-	for _, e := range n.Entities {
-		if e.Table == table {
-			return e
-		}
-	}
-
-	return nil
+	return n.lookup(n.entities().tables[table], func(e *Entity) bool {
+		return e.Table == table
+	})
 }
 
 // EntityIndex returns mfd.Entity index by its name
@@ -591,10 +664,22 @@ func (n *Namespace) EntityNames() []string {
 	return result
 }
 
+// Sort orders entities by name and their searches, so saved xml does not depend on db read order
+func (n *Namespace) Sort() {
+	sort.SliceStable(n.Entities, func(i, j int) bool {
+		return n.Entities[i].Name < n.Entities[j].Name
+	})
+
+	for _, entity := range n.Entities {
+		entity.SortSearches()
+	}
+}
+
 // AddEntity adds entity to namespace
 func (n *Namespace) AddEntity(entity *Entity) *Entity {
 	if index := n.EntityIndex(entity.Name); index != -1 {
 		n.Entities[index] = entity
+		n.reindex()
 		return entity
 	}
 
@@ -608,8 +693,14 @@ type Entity struct {
 	Namespace string `xml:"Namespace,attr" json:"namespace"`
 	Table     string `xml:"Table,attr" json:"table"`
 
+	// Description is a table comment, it is used in docs of generated code
+	Description string `xml:"Description,omitempty" json:"description,omitempty"`
+
 	Attributes Attributes `xml:"Attributes>Attribute,omitempty" json:"attributes"`
 	Searches   Searches   `xml:"Searches>Search,omitempty" json:"searches"`
+
+	// Import is set for entities of imported namespaces
+	Import *ProjectImport `xml:"-" json:"-"`
 }
 
 // AttributeByName gets mfd.Attribute by its name
@@ -731,6 +822,9 @@ type Attribute struct {
 	Max        int    `xml:"Max,attr" json:"max"`
 	Default    string `xml:"Default,attr,omitempty" json:"defaultVal"`
 	HasDefault bool   `xml:"HasDefault,attr,omitempty" json:"hasDefaultVal"`
+
+	// Description is a column comment, it is used in docs of generated code
+	Description string `xml:"Description,omitempty" json:"description,omitempty"`
 }
 
 // Merge fills attribute (from file) values from db
@@ -832,6 +926,27 @@ func IsStatus(name string) bool {
 
 type Searches []*Search
 
+// SortSearches orders searches by attribute order, then by search type, foreign searches go last
+func (e *Entity) SortSearches() {
+	index := func(s *Search) int {
+		if i := slices.Index(e.Attributes, e.AttributeByName(s.AttrName)); !s.IsForeignSearch() && i != -1 {
+			return i
+		}
+		return len(e.Attributes)
+	}
+
+	sort.SliceStable(e.Searches, func(i, j int) bool {
+		a, b := e.Searches[i], e.Searches[j]
+		if ia, ib := index(a), index(b); ia != ib {
+			return ia < ib
+		}
+		if a.AttrName != b.AttrName {
+			return a.AttrName < b.AttrName
+		}
+		return slices.Index(SearchTypes, a.SearchType) < slices.Index(SearchTypes, b.SearchType)
+	})
+}
+
 // Append adds search to collection if not exists
 func (s Searches) Append(search *Search) Searches {
 	for _, existing := range s {
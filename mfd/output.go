@@ -0,0 +1,298 @@
+package mfd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing/fstest"
+)
+
+// Output is a sink of generated files.
+// Generators read files back from output to merge protected code and to skip already existing files.
+type Output interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte) error
+}
+
+// DiskOutput reads and writes files on disk, in dry run mode written files are kept in memory
+var DiskOutput Output = diskOutput{}
+
+type diskOutput struct{}
+
+func (diskOutput) ReadFile(name string) ([]byte, error) {
+	return ReadFile(name)
+}
+
+func (diskOutput) WriteFile(name string, data []byte) error {
+	return WriteFile(name, data)
+}
+
+// MemoryOutput keeps written files in memory, files which were not written are read from disk.
+// Written files are available by fs.FS interface by slash separated paths, e.g. "pkg/db/model.go".
+type MemoryOutput struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+var _ fs.FS = &MemoryOutput{}
+
+// NewMemoryOutput creates empty memory output
+func NewMemoryOutput() *MemoryOutput {
+	return &MemoryOutput{files: map[string][]byte{}}
+}
+
+// ReadFile returns written file or reads it from disk
+func (m *MemoryOutput) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	data, ok := m.files[filepath.Clean(name)]
+	m.mu.RUnlock()
+
+	if ok {
+		return bytes.Clone(data), nil
+	}
+
+	return os.ReadFile(name)
+}
+
+// WriteFile keeps file in memory
+func (m *MemoryOutput) WriteFile(name string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.files[filepath.Clean(name)] = bytes.Clone(data)
+
+	return nil
+}
+
+// Open opens written file, absolute paths and paths outside of working dir can't be opened
+func (m *MemoryOutput) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	files := fstest.MapFS{}
+	for filename, data := range m.files {
+		if slashed := filepath.ToSlash(filename); fs.ValidPath(slashed) {
+			files[slashed] = &fstest.MapFile{Data: data, Mode: 0644}
+		}
+	}
+
+	return files.Open(name)
+}
+
+// Files returns sorted names of written files
+func (m *MemoryOutput) Files() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Files writes generated and project files to output and remembers written files
+type Files struct {
+	output Output
+
+	mu      sync.Mutex
+	written []string
+}
+
+// NewFiles creates files writer, files are written to disk if output is nil
+func NewFiles(output Output) *Files {
+	if output == nil {
+		output = DiskOutput
+	}
+
+	return &Files{output: output}
+}
+
+// Written returns written files in order of first write
+func (f *Files) Written() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string{}, f.written...)
+}
+
+// ReadFile reads file from output
+func (f *Files) ReadFile(filename string) ([]byte, error) {
+	return f.output.ReadFile(filename)
+}
+
+// Exists checks if file exists in output
+func (f *Files) Exists(filename string) bool {
+	_, err := f.output.ReadFile(filename)
+	return err == nil || !errors.Is(err, fs.ErrNotExist)
+}
+
+// Save writes content to file
+func (f *Files) Save(content []byte, filename string) (bool, error) {
+	if err := f.output.WriteFile(filename, content); err != nil {
+		return false, fmt.Errorf("writing content to file, err=%w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, written := range f.written {
+		if written == filename {
+			return true, nil
+		}
+	}
+	f.written = append(f.written, filename)
+
+	return true, nil
+}
+
+// FmtAndSave formats go source and saves it, source with syntax errors is saved as is with format error returned
+func (f *Files) FmtAndSave(unformatted []byte, filename string) (bool, error) {
+	content, fmtErr := format.Source(unformatted)
+	if fmtErr != nil {
+		content = unformatted
+	}
+
+	if _, err := f.Save(content, filename); err != nil {
+		return false, err
+	}
+
+	return true, fmtErr
+}
+
+// FormatAndSave renders template with partials and saves it, go code is merged with existing file and formatted
+func (f *Files) FormatAndSave(data any, output, tmpl string, format bool, partials ...string) (bool, error) {
+	buf := new(bytes.Buffer)
+	if err := Render(buf, tmpl, data, partials...); err != nil {
+		return false, fmt.Errorf("render template, err=%w", err)
+	}
+
+	if format {
+		return f.SaveGo(buf.Bytes(), output)
+	}
+
+	return f.Save(buf.Bytes(), output)
+}
+
+// SaveGo merges go code with existing file keeping protected and hand-added declarations and blocks, formats and saves it.
+// Existing file which can't be parsed is overwritten with generated code if it has no markers, warning is logged,
+// otherwise error is returned and file is not changed.
+func (f *Files) SaveGo(content []byte, filename string) (bool, error) {
+	if old, err := f.ReadFile(filename); err == nil {
+		merged, err := MergeGo(old, content)
+		switch {
+		case err == nil:
+			content = merged
+		case bytes.Contains(old, []byte(KeepMarker)):
+			// protected code can't be found in broken file, it is not overwritten
+			return false, fmt.Errorf("merge existing file %s, err=%w", filename, err)
+		default:
+			log.Printf("merge existing file %s, generated code is saved, err=%s", filename, err)
+		}
+	}
+
+	return f.FmtAndSave(content, filename)
+}
+
+// UpdateFile updates go file with rendered declarations: missing declarations are added,
+// existing are replaced if force is set. Declarations marked with mfd:keep and protected blocks are kept.
+func (f *Files) UpdateFile(buffer *bytes.Buffer, output string, force bool) (bool, error) {
+	content, err := f.ReadFile(output)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(bytes.TrimSpace(content)) == 0) {
+		return f.FmtAndSave(buffer.Bytes(), output)
+	} else if err != nil {
+		return false, fmt.Errorf("read file, err=%w", err)
+	}
+
+	merged, changed, err := updateGo(content, buffer.Bytes(), force)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	return f.FmtAndSave(merged, output)
+}
+
+// MarshalToFile writes file with storage chosen by file extension,
+// comments and unknown elements of existing xml file are kept
+func (f *Files) MarshalToFile(filename string, v interface{}) error {
+	b, err := StorageByFile(filename).Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal data, err=%w", err)
+	}
+
+	if old, err := f.ReadFile(filename); err == nil && Format(filename) == FormatXML {
+		if b, err = PreserveXML(old, b); err != nil {
+			return fmt.Errorf("preserve comments, err=%w", err)
+		}
+	}
+
+	if _, err = f.Save(b, filename); err != nil {
+		return fmt.Errorf("write file, err=%w", err)
+	}
+
+	return nil
+}
+
+// MarshalJSONToFile writes indented json to file
+func (f *Files) MarshalJSONToFile(filename string, v interface{}) error {
+	bytes, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal data, err=%w", err)
+	}
+
+	if _, err := f.Save(bytes, filename); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SaveMFD saves project file
+func (f *Files) SaveMFD(filename string, p *Project) error {
+	p.SortCustomTypes()
+	if err := f.MarshalToFile(filename, p); err != nil {
+		return fmt.Errorf("save project, err=%w", err)
+	}
+
+	return nil
+}
+
+// SaveProjectXML saves namespace files of project
+func (f *Files) SaveProjectXML(filename string, p *Project) error {
+	for _, namespace := range p.Namespaces {
+		namespace.Sort()
+		file := NamespaceFile(filename, namespace.Name)
+		if err := f.MarshalToFile(file, namespace); err != nil {
+			return fmt.Errorf("save namespace %s, err=%w", namespace.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// SaveProjectVT saves vt namespace files of project
+func (f *Files) SaveProjectVT(filename string, p *Project) error {
+	for _, namespace := range p.VTNamespaces {
+		file := VTNamespaceFile(filename, namespace.Name)
+		if err := f.MarshalToFile(file, namespace); err != nil {
+			return fmt.Errorf("save namespace vt entites %s, err=%w", namespace.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// SaveTranslation saves translation file of project
+func (f *Files) SaveTranslation(translation Translation, project, language string) error {
+	filename := TranslationFile(project, language)
+	return f.MarshalToFile(filename, &translation)
+}
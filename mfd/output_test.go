@@ -0,0 +1,40 @@
+package mfd
+
+import (
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFiles(t *testing.T) {
+	out := NewMemoryOutput()
+	files := NewFiles(out)
+
+	for _, content := range []string{"package db\n", "package db\n\nvar a = 1\n"} {
+		if _, err := files.FmtAndSave([]byte(content), filepath.Join("db", "model.go")); err != nil {
+			t.Fatalf("FmtAndSave() error = %v", err)
+		}
+	}
+	if _, err := files.Save([]byte("{}"), "../project.json"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	want := []string{filepath.Join("db", "model.go"), filepath.Join("..", "project.json")}
+	if got := files.Written(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Written() = %v, want %v", got, want)
+	}
+
+	if !files.Exists("db/model.go") || files.Exists("db/none.go") {
+		t.Errorf("Exists() should check written files")
+	}
+
+	content, err := fs.ReadFile(out, "db/model.go")
+	if err != nil || string(content) != "package db\n\nvar a = 1\n" {
+		t.Errorf("fs.ReadFile() = %q, %v", content, err)
+	}
+
+	if _, err := out.Open("../project.json"); err == nil {
+		t.Errorf("Open() should fail for path outside of working dir")
+	}
+}
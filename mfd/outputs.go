@@ -0,0 +1,148 @@
+package mfd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// OutputGenerators are generators which render output rules with their packed entity data
+var OutputGenerators = []string{"model", "repo", "vt"}
+
+// OutputRule is xml element, renders template to additional file for each entity of generator
+type OutputRule struct {
+	// Generator packs entity data for template: model, repo or vt
+	Generator string `xml:"Generator,attr" json:"generator"`
+
+	// path to template, relative to project file
+	Template string `xml:"Template,attr" json:"template"`
+
+	// output file path template relative to generator output dir, e.g. cache/{{.Namespace}}/{{.Name}}_cache.go
+	Path string `xml:"Path,attr" json:"path"`
+
+	// comma separated namespaces, all namespaces if empty
+	Namespaces string `xml:"Namespaces,attr,omitempty" json:"namespaces,omitempty"`
+}
+
+// NamespaceNames returns namespaces of rule
+func (r OutputRule) NamespaceNames() []string {
+	return splitList(r.Namespaces)
+}
+
+// HasNamespace checks if rule is applied to namespace
+func (r OutputRule) HasNamespace(namespace string) bool {
+	names := r.NamespaceNames()
+	return len(names) == 0 || slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(name, namespace) })
+}
+
+// TemplatePath returns path of rule template, relative path is resolved from project file dir
+func (r OutputRule) TemplatePath(mfdPath string) string {
+	if filepath.IsAbs(r.Template) {
+		return r.Template
+	}
+
+	return filepath.Join(filepath.Dir(mfdPath), r.Template)
+}
+
+// OutputRules returns output rules of generator declared in project, Outputs is a pointer to omit empty element in xml
+func (p *Project) OutputRules(generator string) []OutputRule {
+	if p.Outputs == nil {
+		return nil
+	}
+
+	var rules []OutputRule
+	for _, rule := range *p.Outputs {
+		if rule.Generator == generator {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// IsConsistentOutputs checks output rules generators, templates and namespaces
+func (p *Project) IsConsistentOutputs() error {
+	if p.Outputs == nil {
+		return nil
+	}
+
+	for _, rule := range *p.Outputs {
+		if !slices.Contains(OutputGenerators, rule.Generator) {
+			return fmt.Errorf("output %s has unknown generator %s, use one of: %s", rule.Path, rule.Generator, strings.Join(OutputGenerators, ", "))
+		}
+		if rule.Template == "" || rule.Path == "" {
+			return fmt.Errorf("output of %s generator should have template and path", rule.Generator)
+		}
+
+		for _, namespace := range rule.NamespaceNames() {
+			if p.Namespace(namespace) == nil {
+				return fmt.Errorf("namespace %s of output %s not found", namespace, rule.Path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// OutputEntity is an entity with data packed by generator, it is passed to output rule templates
+type OutputEntity struct {
+	// Namespace is a name of entity namespace
+	Namespace string
+
+	// Name is a name of entity
+	Name string
+
+	// Package is a package of generated files
+	Package string
+
+	// Entity is packed entity data of generator, e.g. model.EntityData
+	Entity any
+}
+
+// SaveOutputs renders output rules for each entity of rule namespaces,
+// templates are read relative to project file, files are written relative to generator output dir.
+func (f *Files) SaveOutputs(rules []OutputRule, mfdPath, output string, entities []OutputEntity) error {
+	for _, rule := range rules {
+		tmpl, err := os.ReadFile(rule.TemplatePath(mfdPath))
+		if err != nil {
+			return fmt.Errorf("read output template %s, err=%w", rule.Template, err)
+		}
+
+		for _, entity := range entities {
+			if !rule.HasNamespace(entity.Namespace) {
+				continue
+			}
+
+			if err := f.saveOutput(rule, string(tmpl), output, entity); err != nil {
+				return fmt.Errorf("generate output %s for entity %s, err=%w", rule.Path, entity.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// saveOutput renders output file of entity, go files are merged with existing files and formatted
+func (f *Files) saveOutput(rule OutputRule, tmpl, output string, entity OutputEntity) error {
+	var path bytes.Buffer
+	if err := RenderText(&path, rule.Path, entity); err != nil {
+		return fmt.Errorf("render path, err=%w", err)
+	}
+
+	var content bytes.Buffer
+	if err := RenderText(&content, tmpl, entity); err != nil {
+		return fmt.Errorf("render template, err=%w", err)
+	}
+
+	filename := filepath.Join(output, path.String())
+	if filepath.Ext(filename) == ".go" {
+		_, err := f.SaveGo(content.Bytes(), filename)
+		return err
+	}
+
+	_, err := f.Save(content.Bytes(), filename)
+	return err
+}
@@ -0,0 +1,69 @@
+package mfd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFiles_SaveOutputs(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := "package {{.Package}}\n\n// {{.Name}}Cache caches {{.Entity.Title | ToLower}}\ntype {{.Name}}Cache struct{}\n"
+	if err := os.WriteFile(filepath.Join(dir, "cache.tmpl"), []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []OutputRule{{Generator: "repo", Template: "cache.tmpl", Path: "cache/{{.Namespace}}/{{varName .Name}}.go", Namespaces: "portal"}}
+	entities := []OutputEntity{
+		{Namespace: "portal", Name: "News", Package: "db", Entity: map[string]string{"Title": "News"}},
+		{Namespace: "geo", Name: "City", Package: "db", Entity: map[string]string{"Title": "City"}},
+	}
+
+	out := NewMemoryOutput()
+	files := NewFiles(out)
+	if err := files.SaveOutputs(rules, filepath.Join(dir, "project.mfd"), "pkg/db", entities); err != nil {
+		t.Fatalf("SaveOutputs() error = %v", err)
+	}
+
+	filename := filepath.Join("pkg", "db", "cache", "portal", "news.go")
+	if written := files.Written(); len(written) != 1 || written[0] != filename {
+		t.Errorf("Written() = %v, want %s", written, filename)
+	}
+
+	content, err := out.ReadFile(filename)
+	if want := "package db\n\n// NewsCache caches news\ntype NewsCache struct{}\n"; err != nil || string(content) != want {
+		t.Errorf("output = %q, %v, want %q", content, err, want)
+	}
+
+	rules[0].Template = "none.tmpl"
+	if err := files.SaveOutputs(rules, filepath.Join(dir, "project.mfd"), "pkg/db", entities); err == nil {
+		t.Errorf("SaveOutputs() should fail for missing template")
+	}
+}
+
+func TestProject_IsConsistentOutputs(t *testing.T) {
+	project := &Project{Namespaces: []*Namespace{{Name: "portal"}}}
+
+	tests := []struct {
+		name  string
+		rules []OutputRule
+		ok    bool
+	}{
+		{name: "valid", rules: []OutputRule{{Generator: "vt", Template: "event.tmpl", Path: "{{.Name}}.go", Namespaces: "portal"}}, ok: true},
+		{name: "unknown generator", rules: []OutputRule{{Generator: "xml", Template: "event.tmpl", Path: "{{.Name}}.go"}}},
+		{name: "empty path", rules: []OutputRule{{Generator: "model", Template: "event.tmpl"}}},
+		{name: "unknown namespace", rules: []OutputRule{{Generator: "repo", Template: "event.tmpl", Path: "{{.Name}}.go", Namespaces: "geo"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project.Outputs = &tt.rules
+			if err := project.IsConsistentOutputs(); (err == nil) != tt.ok {
+				t.Errorf("IsConsistentOutputs() error = %v", err)
+			}
+		})
+	}
+
+	if rules := project.OutputRules("repo"); len(rules) != 1 {
+		t.Errorf("OutputRules() = %v", rules)
+	}
+}
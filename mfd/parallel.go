@@ -0,0 +1,92 @@
+package mfd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// Task generates files, files are written to files passed to task
+type Task func(files *Files) error
+
+// Parallel runs tasks in pool of jobs workers, count of CPUs is used if jobs < 1.
+// Files written by each task are buffered and saved in order of tasks after all tasks are finished,
+// so written files and their order do not depend on scheduling. Tasks should write different files,
+// files written by other tasks are not visible to task. Files of failed tasks are saved as well,
+// errors of all tasks are joined in order of tasks. Tasks which are not started when ctx is done are skipped.
+func (f *Files) Parallel(ctx context.Context, jobs int, tasks []Task) error {
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+
+	buffers := make([]*bufferOutput, len(tasks))
+	errs := make([]error, len(tasks), len(tasks)+1)
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(tasks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				buffers[i] = newBufferOutput(f.output)
+				errs[i] = tasks[i](NewFiles(buffers[i]))
+			}
+		}()
+	}
+
+schedule:
+	for i := range tasks {
+		select {
+		case queue <- i:
+		case <-ctx.Done():
+			break schedule
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	for i, buffer := range buffers {
+		if buffer == nil {
+			continue
+		}
+
+		for _, filename := range buffer.order {
+			if _, err := f.Save(buffer.files[filename], filename); err != nil {
+				errs[i] = errors.Join(errs[i], err)
+			}
+		}
+	}
+
+	return errors.Join(append(errs, ctx.Err())...)
+}
+
+// bufferOutput keeps files written by task in order of first write, files which were not written are read from base output
+type bufferOutput struct {
+	base  Output
+	files map[string][]byte
+	order []string
+}
+
+func newBufferOutput(base Output) *bufferOutput {
+	return &bufferOutput{base: base, files: map[string][]byte{}}
+}
+
+func (b *bufferOutput) ReadFile(name string) ([]byte, error) {
+	if data, ok := b.files[name]; ok {
+		return bytes.Clone(data), nil
+	}
+
+	return b.base.ReadFile(name)
+}
+
+func (b *bufferOutput) WriteFile(name string, data []byte) error {
+	if _, ok := b.files[name]; !ok {
+		b.order = append(b.order, name)
+	}
+	b.files[name] = bytes.Clone(data)
+
+	return nil
+}
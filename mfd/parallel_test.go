@@ -0,0 +1,65 @@
+package mfd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFiles_Parallel(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	var tasks []Task
+	for i := range 5 {
+		tasks = append(tasks, func(files *Files) error {
+			// later tasks finish first
+			time.Sleep(time.Duration(5-i) * time.Millisecond)
+
+			filename := fmt.Sprintf("db/file%d.go", i)
+			if _, err := files.Save([]byte("package db\n"), filename); err != nil {
+				return err
+			}
+
+			// task reads its own files
+			if _, err := files.SaveGo([]byte("package db\n\nvar a = 1\n"), filename); err != nil {
+				return err
+			}
+
+			if i%2 == 1 {
+				return fmt.Errorf("task %d %w", i, errFailed)
+			}
+			return nil
+		})
+	}
+
+	for _, jobs := range []int{0, 1, 3} {
+		out := NewMemoryOutput()
+		files := NewFiles(out)
+
+		err := files.Parallel(context.Background(), jobs, tasks)
+		if !errors.Is(err, errFailed) || err.Error() != "task 1 failed\ntask 3 failed" {
+			t.Errorf("Parallel(%d) error = %v", jobs, err)
+		}
+
+		want := []string{"db/file0.go", "db/file1.go", "db/file2.go", "db/file3.go", "db/file4.go"}
+		if got := files.Written(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Parallel(%d) Written() = %v, want %v", jobs, got, want)
+		}
+
+		if content, err := out.ReadFile("db/file3.go"); err != nil || !strings.Contains(string(content), "var a = 1") {
+			t.Errorf("Parallel(%d) file of failed task = %q, %v", jobs, content, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	files := NewFiles(NewMemoryOutput())
+	if err := files.Parallel(ctx, 1, tasks); !errors.Is(err, context.Canceled) {
+		t.Errorf("Parallel() with canceled context error = %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package mfd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// readNamespaces reads all namespaces and vt namespaces of project
+func (p *Project) readNamespaces(filename string) error {
+	for _, name := range p.NamespaceNames {
+		ns, err := LoadNamespace(NamespaceFile(filename, name))
+		//todo: maybe create xmls if not exists
+		if err != nil {
+			return fmt.Errorf("read namespace, err=%w", err)
+		}
+
+		vtns, err := LoadVTNamespace(VTNamespaceFile(filename, name))
+		if err != nil {
+			return fmt.Errorf("read vt vtns, err=%w", err)
+		}
+
+		p.appendNamespace(ns, vtns)
+	}
+
+	return nil
+}
+
+// readPartial reads given namespaces and namespaces of entities they depend on.
+// Other namespaces are read in project order only until all dependencies are found, their vt namespaces are not read.
+func (p *Project) readPartial(filename string, namespaces []string) error {
+	p.partial = true
+
+	// entities found in kept and imported namespaces and entities required by kept namespaces, by lower case name
+	found, required := map[string]struct{}{}, map[string]struct{}{}
+	for _, ns := range p.ImportedNamespaces {
+		for _, entity := range ns.Entities {
+			found[strings.ToLower(entity.Name)] = struct{}{}
+		}
+	}
+
+	read := map[string]*Namespace{}
+	kept := map[string]*VTNamespace{}
+
+	readNamespace := func(name string) (*Namespace, error) {
+		if ns, ok := read[name]; ok {
+			return ns, nil
+		}
+
+		ns, err := LoadNamespace(NamespaceFile(filename, name))
+		if err != nil {
+			return nil, fmt.Errorf("read namespace, err=%w", err)
+		}
+		read[name] = ns
+
+		return ns, nil
+	}
+
+	keep := func(name string, ns *Namespace) error {
+		vtns, err := LoadVTNamespace(VTNamespaceFile(filename, name))
+		if err != nil {
+			return fmt.Errorf("read vt vtns, err=%w", err)
+		}
+		kept[name] = vtns
+
+		for _, entity := range ns.Entities {
+			found[strings.ToLower(entity.Name)] = struct{}{}
+			for _, dependency := range entity.dependencies() {
+				required[strings.ToLower(dependency)] = struct{}{}
+			}
+		}
+
+		for _, vtEntity := range vtns.Entities {
+			for _, tmpl := range vtEntity.TmplAttributes {
+				if tmpl.Children != "" {
+					child, _ := tmpl.ChildRelation()
+					required[strings.ToLower(child)] = struct{}{}
+				}
+			}
+		}
+
+		return nil
+	}
+
+	for _, name := range p.NamespaceNames {
+		if !slices.ContainsFunc(namespaces, func(ns string) bool { return strings.EqualFold(ns, name) }) {
+			continue
+		}
+
+		ns, err := readNamespace(name)
+		if err != nil {
+			return err
+		}
+		if err := keep(name, ns); err != nil {
+			return err
+		}
+	}
+
+	for {
+		missing := map[string]struct{}{}
+		for entity := range required {
+			if _, ok := found[entity]; !ok {
+				missing[entity] = struct{}{}
+			}
+		}
+		if len(missing) == 0 {
+			break
+		}
+
+		// namespace with missing entity is kept, next namespace is read if it is not found in read ones
+		next, unread := "", ""
+		for _, name := range p.NamespaceNames {
+			ns, ok := read[name]
+			if !ok {
+				if unread == "" {
+					unread = name
+				}
+				continue
+			}
+
+			if _, ok := kept[name]; !ok && ns.hasAny(missing) {
+				next = name
+				break
+			}
+		}
+
+		if next == "" && unread == "" {
+			// missing entities are reported by consistency check
+			break
+		}
+
+		var err error
+		if next != "" {
+			err = keep(next, read[next])
+		} else {
+			_, err = readNamespace(unread)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// kept namespaces are appended in project order
+	for _, name := range p.NamespaceNames {
+		if vtns, ok := kept[name]; ok {
+			p.appendNamespace(read[name], vtns)
+		}
+	}
+
+	return nil
+}
+
+// appendNamespace appends non-empty namespace and vt namespace to project
+func (p *Project) appendNamespace(ns *Namespace, vtns *VTNamespace) {
+	if len(ns.Entities) != 0 {
+		p.Namespaces = append(p.Namespaces, ns)
+	}
+
+	if len(vtns.Entities) != 0 {
+		p.VTNamespaces = append(p.VTNamespaces, vtns)
+	}
+}
+
+// IsPartial returns true if project is loaded with only some of its namespaces, see LoadProjectNamespaces
+func (p *Project) IsPartial() bool {
+	return p.partial
+}
+
+// hasAny checks if namespace has any of entities given by lower case names
+func (n *Namespace) hasAny(entities map[string]struct{}) bool {
+	for _, entity := range n.Entities {
+		if _, ok := entities[strings.ToLower(entity.Name)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dependencies returns names of entities referenced by fks and foreign searches of entity
+func (e *Entity) dependencies() []string {
+	var dependencies []string
+	for _, attr := range e.Attributes {
+		if attr.ForeignKey != "" {
+			dependencies = append(dependencies, attr.ForeignKey)
+		}
+	}
+
+	for _, search := range e.Searches {
+		if search.IsForeignSearch() {
+			entity, _ := search.ForeignAttribute()
+			dependencies = append(dependencies, entity)
+		}
+	}
+
+	return dependencies
+}
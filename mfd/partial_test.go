@@ -0,0 +1,145 @@
+package mfd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func partialEntity(namespace, name string, fks ...string) *Entity {
+	entity := &Entity{Name: name, Namespace: namespace, Table: namespace + "." + name, Attributes: Attributes{
+		{Name: "ID", DBName: "id", DBType: "int4", GoType: "int", PrimaryKey: true, Null: NullableNo},
+	}}
+	for _, fk := range fks {
+		entity.Attributes = append(entity.Attributes, &Attribute{Name: fk + "ID", DBName: fk + "Id", DBType: "int4", GoType: "int", ForeignKey: fk, Null: NullableNo})
+	}
+
+	return entity
+}
+
+// partialProject saves project where shop depends on crm, crm depends on catalog, report and misc are not used by them
+func partialProject(t *testing.T) string {
+	t.Helper()
+
+	project := NewProject("partial", GoPG10)
+	project.AddEntity("shop", partialEntity("shop", "Order", "Customer"))
+	project.AddEntity("catalog", partialEntity("catalog", "Product"))
+	project.AddEntity("report", partialEntity("report", "Stat", "Product"))
+	project.AddEntity("crm", partialEntity("crm", "Customer", "Product"))
+	project.AddEntity("misc", partialEntity("misc", "Note"))
+	project.VTNamespaces = []*VTNamespace{
+		{Name: "catalog", Entities: []*VTEntity{{Name: "Product", Mode: ModeFull}}},
+		{Name: "report", Entities: []*VTEntity{{Name: "Stat", Mode: ModeFull}}},
+	}
+
+	filename := filepath.Join(t.TempDir(), "partial.mfd")
+	saveImportProject(t, filename, project)
+	if err := SaveProjectVT(filename, project); err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+func namespaceNames(namespaces []*Namespace) []string {
+	names := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		names[i] = ns.Name
+	}
+
+	return names
+}
+
+func TestLoadProjectNamespaces(t *testing.T) {
+	filename := partialProject(t)
+
+	// files of namespaces that are not needed are not read
+	for _, broken := range []string{"misc.xml", "report.vt.xml"} {
+		if err := os.WriteFile(filepath.Join(filepath.Dir(filename), broken), []byte("broken"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tc := []struct {
+		namespaces []string
+		want       []string
+	}{
+		{namespaces: []string{"shop"}, want: []string{"shop", "catalog", "crm"}},
+		{namespaces: []string{"CRM"}, want: []string{"catalog", "crm"}},
+		{namespaces: []string{"catalog"}, want: []string{"catalog"}},
+		{namespaces: []string{"unknown"}, want: []string{}},
+	}
+
+	for _, c := range tc {
+		project, err := LoadProjectNamespaces(filename, 0, c.namespaces)
+		if err != nil {
+			t.Fatalf("load %v: %v", c.namespaces, err)
+		}
+
+		if got := namespaceNames(project.Namespaces); !slices.Equal(got, c.want) {
+			t.Errorf("load %v: got namespaces %v, want %v", c.namespaces, got, c.want)
+		}
+		if !project.IsPartial() {
+			t.Errorf("load %v: project is not partial", c.namespaces)
+		}
+		if len(project.NamespaceNames) != 5 {
+			t.Errorf("load %v: namespace names are changed: %v", c.namespaces, project.NamespaceNames)
+		}
+	}
+
+	project, err := LoadProjectNamespaces(filename, 0, []string{"shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer := project.Entity("Order").AttributeByName("CustomerID").ForeignEntity; customer == nil || customer.AttributeByName("ProductID").ForeignEntity == nil {
+		t.Error("fk links of dependencies are not made")
+	}
+	if project.VTEntity("Product") == nil {
+		t.Error("vt namespace of dependency is not loaded")
+	}
+
+	if _, err := LoadProject(filename, false, 0); err == nil {
+		t.Error("expected error for broken namespace on full load")
+	}
+}
+
+func TestNamespace_Entity(t *testing.T) {
+	ns := NewNamespace("portal")
+	ns.AddEntity(&Entity{Name: "News", Table: "news"})
+	ns.AddEntity(&Entity{Name: "Tag", Table: "tags"})
+
+	if e := ns.Entity("news"); e == nil || e.Name != "News" {
+		t.Fatalf("entity News not found: %v", e)
+	}
+
+	// replaced entity
+	category := ns.AddEntity(&Entity{Name: "Tag", Table: "categories"})
+	if ns.EntityByTable("categories") != category || ns.Entity("Tag") != category {
+		t.Error("replaced entity not found")
+	}
+
+	// entity renamed in place is not found by old name, stale index is rebuilt
+	category.Name = "Category"
+	if ns.Entity("Tag") != nil || ns.Entity("Category") != category {
+		t.Error("renamed entity not found")
+	}
+
+	// entity renamed in place is found by new name after reindex
+	category.Name = "Section"
+	ns.reindex()
+	if ns.Entity("Category") != nil || ns.Entity("Section") != category {
+		t.Error("reindexed entity not found")
+	}
+	category.Table = "sections"
+	ns.reindex()
+	if ns.EntityByTable("sections") != category {
+		t.Error("entity with changed table not found")
+	}
+
+	// entity removed from slice
+	ns.Entities = ns.Entities[:1]
+	if ns.Entity("Section") != nil || ns.EntityByTable("sections") != nil {
+		t.Error("removed entity found")
+	}
+}
@@ -0,0 +1,327 @@
+package mfd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// xmlElement is an element of xml file identified by its path,
+// path consists of element names with Name attribute or index among siblings with the same name, e.g. /Package[0]/Entities[0]/Entity[News]
+type xmlElement struct {
+	path   string
+	parent string
+
+	// offsets of element start, end of start tag, start of end tag and element end
+	start, startEnd, endStart, end int64
+
+	indent string
+	st     *schemaType
+	known  bool
+
+	counts   map[string]int
+	lastPath string
+	skipped  bool
+}
+
+// preserved stores comments, unknown elements and unknown attributes of old xml file
+type preserved struct {
+	before map[string][]string
+	after  map[string][]string
+	first  map[string][]string
+	last   map[string][]string
+	attrs  map[string][]string
+}
+
+// walkXML reads xml file and calls fn on every token with current element stack
+func walkXML(data []byte, fn func(token xml.Token, stack []*xmlElement, start, end int64) (skip bool)) error {
+	roots, _ := cachedSchema()
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlElement
+	for {
+		start := dec.InputOffset()
+		token, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		end := dec.InputOffset()
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			el := &xmlElement{start: start, startEnd: end, counts: map[string]int{}}
+			if len(stack) == 0 {
+				el.known = true
+				for i, root := range roots {
+					if rootElement(reflect.TypeOf(schemaRoots[i]).Elem()) == t.Name.Local {
+						el.st = root
+					}
+				}
+			} else {
+				parent := stack[len(stack)-1]
+				el.parent = parent.path
+				if parent.st != nil {
+					var text *schemaNode
+					el.st, text = parent.st.child(t.Name.Local)
+					el.known = el.st != nil || text != nil || parent.st.any
+				}
+			}
+
+			key := ""
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "" && attr.Name.Local == "Name" {
+					key = attr.Value
+				}
+			}
+			if key == "" {
+				counts := map[string]int{}
+				if len(stack) != 0 {
+					counts = stack[len(stack)-1].counts
+				}
+				key = strconv.Itoa(counts[t.Name.Local])
+				counts[t.Name.Local]++
+			}
+			el.path = el.parent + "/" + t.Name.Local + "[" + key + "]"
+
+			// indent is whitespace between line start and element start
+			if i := bytes.LastIndexByte(data[:start], '\n'); i != -1 && len(bytes.TrimSpace(data[i+1:start])) == 0 {
+				el.indent = string(data[i+1 : start])
+			}
+
+			stack = append(stack, el)
+			if fn(t, stack, start, end) {
+				// skipping unknown element, decoder Skip can't be mixed with raw tokens
+				for depth := 1; depth > 0; {
+					token, err := dec.RawToken()
+					if err != nil {
+						return err
+					}
+					switch token.(type) {
+					case xml.StartElement:
+						depth++
+					case xml.EndElement:
+						depth--
+					}
+				}
+				el.end = dec.InputOffset()
+				stack = stack[:len(stack)-1]
+				fn(xml.EndElement{Name: t.Name}, append(stack, el), start, el.end)
+			}
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return fmt.Errorf("unexpected end element %s", t.Name.Local)
+			}
+			el := stack[len(stack)-1]
+			el.endStart, el.end = start, end
+			fn(t, stack, start, end)
+			stack = stack[:len(stack)-1]
+		default:
+			fn(t, stack, start, end)
+		}
+	}
+}
+
+// collectPreserved finds comments, unknown elements and unknown attributes in xml file and remembers their place
+func collectPreserved(data []byte) (*preserved, bool, error) {
+	p := &preserved{
+		before: map[string][]string{},
+		after:  map[string][]string{},
+		first:  map[string][]string{},
+		last:   map[string][]string{},
+		attrs:  map[string][]string{},
+	}
+
+	found := false
+	// comments waiting for next element by parent path
+	pending := map[string][]string{}
+
+	err := walkXML(data, func(token xml.Token, stack []*xmlElement, start, end int64) bool {
+		var parent *xmlElement
+		if len(stack) > 1 {
+			parent = stack[len(stack)-2]
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			el := stack[len(stack)-1]
+			if !el.known {
+				if parent == nil || parent.st == nil {
+					return false
+				}
+				// unknown element is placed after previous known sibling
+				el.lastPath = parent.lastPath
+				el.skipped = true
+				return true
+			}
+
+			parentPath := el.parent
+			if comments := pending[parentPath]; len(comments) != 0 {
+				p.before[el.path] = append(p.before[el.path], comments...)
+				delete(pending, parentPath)
+			}
+			if parent != nil {
+				parent.lastPath = el.path
+			}
+
+			if el.st != nil {
+				for _, attr := range t.Attr {
+					if attr.Name.Space != "" || attr.Name.Local == "xmlns" || el.st.hasAttr(attr.Name.Local) {
+						continue
+					}
+					var b strings.Builder
+					_ = xml.EscapeText(&b, []byte(attr.Value))
+					p.attrs[el.path] = append(p.attrs[el.path], attr.Name.Local+`="`+b.String()+`"`)
+					found = true
+				}
+			}
+		case xml.EndElement:
+			el := stack[len(stack)-1]
+			if el.skipped {
+				raw := string(data[el.start:el.end])
+				if el.lastPath != "" {
+					p.after[el.lastPath] = append(p.after[el.lastPath], raw)
+				} else {
+					p.first[el.parent] = append(p.first[el.parent], raw)
+				}
+				found = true
+				return false
+			}
+
+			if comments := pending[el.path]; len(comments) != 0 {
+				p.last[el.path] = append(p.last[el.path], comments...)
+				delete(pending, el.path)
+			}
+		case xml.Comment:
+			path := ""
+			if len(stack) != 0 {
+				el := stack[len(stack)-1]
+				// comments in simple elements are not preserved
+				if el.st == nil {
+					return false
+				}
+				path = el.path
+			}
+			pending[path] = append(pending[path], string(data[start:end]))
+			found = true
+		}
+
+		return false
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// comments after root element
+	p.last[""] = pending[""]
+
+	return p, found, nil
+}
+
+// hasAttr checks if type has attribute
+func (st *schemaType) hasAttr(name string) bool {
+	for _, attr := range st.attrs {
+		if attr.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PreserveXML copies comments, unknown elements and unknown attributes from old xml file to new one.
+// Comments are placed before the same element as in old file, unknown elements after the same sibling,
+// elements are matched by path built from element names and Name attributes.
+func PreserveXML(old, data []byte) ([]byte, error) {
+	p, found, err := collectPreserved(old)
+	if err != nil || !found {
+		return data, err
+	}
+
+	type insert struct {
+		offset int64
+		text   string
+	}
+	var inserts []insert
+	add := func(offset int64, text string) {
+		inserts = append(inserts, insert{offset: offset, text: text})
+	}
+
+	var rootEnd int64
+	err = walkXML(data, func(token xml.Token, stack []*xmlElement, start, end int64) bool {
+		if _, ok := token.(xml.EndElement); !ok {
+			return false
+		}
+
+		el := stack[len(stack)-1]
+		childIndent := el.indent + "    "
+		if el.parent == "" {
+			rootEnd = el.end
+		}
+
+		for _, raw := range p.before[el.path] {
+			add(el.start, raw+"\n"+el.indent)
+		}
+		for _, attr := range p.attrs[el.path] {
+			add(el.startEnd-1, " "+attr)
+		}
+		for _, raw := range p.after[el.path] {
+			add(el.end, "\n"+el.indent+raw)
+		}
+
+		first, last := p.first[el.path], p.last[el.path]
+		if len(first)+len(last) == 0 {
+			return false
+		}
+
+		if el.endStart == el.startEnd {
+			// empty element
+			for _, raw := range append(first, last...) {
+				add(el.startEnd, "\n"+childIndent+raw)
+			}
+			add(el.startEnd, "\n"+el.indent)
+		} else {
+			for _, raw := range first {
+				add(el.startEnd, "\n"+childIndent+raw)
+			}
+			// end tag is placed on separate line after children
+			offset := el.endStart
+			if prefix := "\n" + el.indent; bytes.HasSuffix(data[:offset], []byte(prefix)) {
+				offset -= int64(len(prefix))
+			}
+			for _, raw := range last {
+				add(offset, "\n"+childIndent+raw)
+			}
+		}
+
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, raw := range p.last[""] {
+		add(rootEnd, "\n"+raw)
+	}
+
+	// inserts at the same offset keep their order
+	sort.SliceStable(inserts, func(i, j int) bool { return inserts[i].offset < inserts[j].offset })
+
+	var b bytes.Buffer
+	var offset int64
+	for _, in := range inserts {
+		b.Write(data[offset:in.offset])
+		b.WriteString(in.text)
+		offset = in.offset
+	}
+	b.Write(data[offset:])
+
+	return b.Bytes(), nil
+}
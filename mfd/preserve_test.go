@@ -0,0 +1,106 @@
+package mfd
+
+import (
+	"testing"
+)
+
+const preserveOld = `<Package>
+    <!-- namespace comment -->
+    <Name>portal</Name>
+    <Entities>
+        <!-- news entity -->
+        <Entity Name="News" Namespace="portal" Table="news" Owner="me">
+            <Attributes>
+                <Attribute Name="ID" DBName="newsId" PK="true" Nullable="Yes" Min="0" Max="0"></Attribute>
+                <!-- title -->
+                <Attribute Name="Title" DBName="title" PK="false" Nullable="No" Min="0" Max="0"></Attribute>
+                <Extra a="1"><B/></Extra>
+            </Attributes>
+            <Searches>
+                <Custom/>
+                <!-- end of searches -->
+            </Searches>
+        </Entity>
+        <!-- removed entity -->
+        <Entity Name="Removed" Namespace="portal" Table="removed"></Entity>
+    </Entities>
+</Package>
+<!-- tail -->
+`
+
+const preserveExpected = `<Package xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema" xsi:noNamespaceSchemaLocation="mfd.xsd">
+    <!-- namespace comment -->
+    <Name>portal</Name>
+    <Entities>
+        <Entity Name="Added" Namespace="portal" Table="added">
+            <Attributes></Attributes>
+            <Searches></Searches>
+        </Entity>
+        <!-- news entity -->
+        <Entity Name="News" Namespace="portal" Table="news" Owner="me">
+            <Attributes>
+                <Attribute Name="ID" DBName="newsId" PK="true" Nullable="Yes" Min="0" Max="0"></Attribute>
+                <!-- title -->
+                <Attribute Name="Title" DBName="title" PK="false" Nullable="No" Min="0" Max="0"></Attribute>
+                <Extra a="1"><B/></Extra>
+            </Attributes>
+            <Searches>
+                <Custom/>
+                <!-- end of searches -->
+            </Searches>
+        </Entity>
+    </Entities>
+</Package>
+<!-- tail -->
+`
+
+func TestPreserveXML(t *testing.T) {
+	storage := StorageByFile("portal.xml")
+
+	ns := &Namespace{}
+	if err := storage.Unmarshal([]byte(preserveOld), ns); err != nil {
+		t.Fatal(err)
+	}
+
+	ns.Entities = append(ns.Entities[:1], &Entity{Name: "Added", Namespace: "portal", Table: "added"})
+	ns.Sort()
+
+	data, err := storage.Marshal(ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := PreserveXML([]byte(preserveOld), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != preserveExpected {
+		t.Errorf("PreserveXML() =\n%s\nwant\n%s", actual, preserveExpected)
+	}
+
+	// saving again does not change file
+	if again, err := PreserveXML(actual, data); err != nil || string(again) != preserveExpected {
+		t.Errorf("PreserveXML() is not stable =\n%s, err=%v", again, err)
+	}
+}
+
+func TestEntity_SortSearches(t *testing.T) {
+	entity := &Entity{
+		Attributes: Attributes{{Name: "ID"}, {Name: "Title"}, {Name: "Params"}},
+		Searches: Searches{
+			{Name: "CategoryTitle", AttrName: "Category.Title", SearchType: SearchILike},
+			{Name: "ParamsTitle", AttrName: "Params->Title", SearchType: SearchEquals},
+			{Name: "TitleILike", AttrName: "Title", SearchType: SearchILike},
+			{Name: "NotID", AttrName: "ID", SearchType: SearchNotEquals},
+			{Name: "IDs", AttrName: "ID", SearchType: SearchArray},
+		},
+	}
+	entity.SortSearches()
+
+	want := []string{"NotID", "IDs", "TitleILike", "ParamsTitle", "CategoryTitle"}
+	for i, search := range entity.Searches {
+		if search.Name != want[i] {
+			t.Errorf("SortSearches() search %d = %s, want %s", i, search.Name, want[i])
+		}
+	}
+}
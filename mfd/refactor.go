@@ -0,0 +1,420 @@
+package mfd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/dizzyfool/genna/util"
+)
+
+// tmplRename is renamed template attribute, its translation keys are renamed too
+type tmplRename struct {
+	namespace string
+	entity    string
+	name      string
+	newName   string
+}
+
+// RenameEntity renames entity and updates all references to it: vt entity, fks and foreign searches of other entities,
+// children of templates and translations. Project consistency is checked after renaming.
+func (p *Project) RenameEntity(name, newName string, translations ...*Translation) error {
+	entity := p.Entity(name)
+	if entity == nil {
+		return fmt.Errorf("entity %s not found", name)
+	}
+	if err := readOnlyError(entity); err != nil {
+		return err
+	}
+	if newName == "" {
+		return fmt.Errorf("new name of entity %s is empty", entity.Name)
+	}
+	if found := p.Entity(newName); found != nil && found != entity {
+		return fmt.Errorf("entity %s already exists in %s namespace", found.Name, found.Namespace)
+	}
+
+	oldName := entity.Name
+	entity.Name = newName
+	if ns := p.Namespace(entity.Namespace); ns != nil {
+		ns.reindex()
+	}
+
+	for _, namespace := range p.Namespaces {
+		for _, e := range namespace.Entities {
+			for _, attr := range e.Attributes {
+				if strings.EqualFold(attr.ForeignKey, oldName) {
+					attr.ForeignKey = newName
+				}
+			}
+
+			for _, search := range e.Searches {
+				if !search.IsForeignSearch() {
+					continue
+				}
+				if foreignName, _ := search.ForeignAttribute(); strings.EqualFold(foreignName, oldName) {
+					parts := strings.SplitN(search.AttrName, ".", 2)
+					search.AttrName = newName + "." + parts[1]
+				}
+			}
+		}
+	}
+
+	var renames []tmplRename
+	for _, vtNamespace := range p.VTNamespaces {
+		for _, vtEntity := range vtNamespace.Entities {
+			if vtEntity.Name == oldName {
+				vtEntity.Name = newName
+			}
+
+			for _, tmpl := range vtEntity.TmplAttributes {
+				childName, attrName := tmpl.ChildRelation()
+				if tmpl.Children == "" || childName != oldName {
+					continue
+				}
+				tmpl.Children = newName + "." + attrName
+
+				// default name of children template attribute is made from child entity name
+				oldPlural, newPlural := MakePlural(oldName), MakePlural(newName)
+				if tmpl.Name == oldPlural || strings.HasPrefix(tmpl.Name, oldPlural+"By") {
+					rename := tmplRename{namespace: vtNamespace.Name, entity: vtEntity.Name, name: tmpl.Name, newName: newPlural + strings.TrimPrefix(tmpl.Name, oldPlural)}
+					tmpl.Name = rename.newName
+					renames = append(renames, rename)
+				}
+			}
+		}
+	}
+
+	for _, translation := range translations {
+		if te := translation.Entity(entity.Namespace, oldName); te != nil {
+			te.rename(oldName, newName)
+		}
+		translation.renameKeys(renames)
+	}
+
+	p.UpdateLinks()
+
+	return p.IsConsistent()
+}
+
+// RenameAttribute renames attribute of entity and updates all references to it: searches of entity and foreign searches of other entities,
+// vt attributes, template attributes, children of templates and translations.
+// Searches with default names are renamed too. Project consistency is checked after renaming.
+func (p *Project) RenameAttribute(entityName, name, newName string, translations ...*Translation) error {
+	entity := p.Entity(entityName)
+	if entity == nil {
+		return fmt.Errorf("entity %s not found", entityName)
+	}
+	if err := readOnlyError(entity); err != nil {
+		return err
+	}
+
+	attr := entity.AttributeByName(name)
+	if attr == nil || IsJSON(name) {
+		return fmt.Errorf("attribute %s not found in %s entity", name, entity.Name)
+	}
+	if newName == "" {
+		return fmt.Errorf("new name of attribute %s is empty", attr.Name)
+	}
+	if found := entity.AttributeByName(newName); found != nil && found != attr {
+		return fmt.Errorf("attribute %s already exists in %s entity", found.Name, entity.Name)
+	}
+
+	oldName := attr.Name
+	attr.Name = newName
+
+	// own searches, searches with default names are renamed
+	searches := map[string]string{}
+	for _, search := range entity.Searches {
+		switch {
+		case search.AttrName == oldName:
+			search.AttrName = newName
+		case strings.HasPrefix(search.AttrName, oldName+JSONFieldSep):
+			search.AttrName = newName + strings.TrimPrefix(search.AttrName, oldName)
+		default:
+			continue
+		}
+
+		if search.Name == MakeSearchName(oldName, search.SearchType) {
+			searches[search.Name] = MakeSearchName(newName, search.SearchType)
+			search.Name = searches[search.Name]
+		}
+	}
+
+	// foreign searches of other entities
+	for _, namespace := range p.Namespaces {
+		for _, e := range namespace.Entities {
+			for _, search := range e.Searches {
+				if !search.IsForeignSearch() {
+					continue
+				}
+				if foreignName, foreignAttr := search.ForeignAttribute(); strings.EqualFold(foreignName, entity.Name) && foreignAttr == oldName {
+					parts := strings.SplitN(search.AttrName, ".", 2)
+					search.AttrName = parts[0] + "." + newName
+				}
+			}
+		}
+	}
+
+	var renames []tmplRename
+	for _, vtNamespace := range p.VTNamespaces {
+		for _, vtEntity := range vtNamespace.Entities {
+			// children of other entities referencing renamed attribute
+			for _, tmpl := range vtEntity.TmplAttributes {
+				if childName, attrName := tmpl.ChildRelation(); tmpl.Children != "" && childName == entity.Name && attrName == oldName {
+					tmpl.Children = childName + "." + newName
+				}
+			}
+
+			if vtEntity.Name != entity.Name {
+				continue
+			}
+
+			vtAttrs := map[string]string{}
+			for _, vtAttr := range vtEntity.Attributes {
+				oldVTName := vtAttr.Name
+				if vtAttr.AttrName == oldName {
+					vtAttr.AttrName = newName
+				}
+				// attribute is searched by its name if there is no search for it
+				if newSearch, ok := searches[vtAttr.SearchName]; ok {
+					vtAttr.SearchName = newSearch
+				} else if vtAttr.SearchName == oldName {
+					vtAttr.SearchName = newName
+				}
+
+				switch {
+				case vtAttr.Name == oldName:
+					vtAttr.Name = newName
+				case searches[vtAttr.Name] != "":
+					vtAttr.Name = searches[vtAttr.Name]
+				}
+				if vtAttr.Name != oldVTName {
+					vtAttrs[oldVTName] = vtAttr.Name
+				}
+			}
+
+			// fk template attribute name is made from fk attribute name without ID suffix
+			fkName, newFKName := util.ReplaceSuffix(oldName, util.ID, ""), util.ReplaceSuffix(newName, util.ID, "")
+			for _, tmpl := range vtEntity.TmplAttributes {
+				oldVTName := tmpl.AttrName
+				if newVTName, ok := vtAttrs[tmpl.AttrName]; ok {
+					tmpl.AttrName = newVTName
+				}
+
+				rename := tmplRename{namespace: vtNamespace.Name, entity: vtEntity.Name, name: tmpl.Name}
+				switch {
+				case tmpl.Name == oldVTName && tmpl.AttrName != oldVTName:
+					rename.newName = tmpl.AttrName
+				case tmpl.Name == fkName && oldVTName == oldName && fkName != oldName:
+					rename.newName = newFKName
+				default:
+					continue
+				}
+
+				tmpl.Name = rename.newName
+				renames = append(renames, rename)
+			}
+		}
+	}
+
+	for _, translation := range translations {
+		translation.renameKeys(renames)
+	}
+
+	p.UpdateLinks()
+
+	return p.IsConsistent()
+}
+
+// MoveEntity moves entity with its vt entity and translations to another namespace, namespace is created if not exists.
+// Namespace left without entities is removed from project. Project consistency is checked after moving.
+func (p *Project) MoveEntity(entityName, namespace string, translations ...*Translation) error {
+	entity := p.Entity(entityName)
+	if entity == nil {
+		return fmt.Errorf("entity %s not found", entityName)
+	}
+	if err := readOnlyError(entity); err != nil {
+		return err
+	}
+	if namespace == "" {
+		return fmt.Errorf("target namespace of entity %s is empty", entity.Name)
+	}
+	if p.ImportedNamespace(namespace) != nil {
+		return fmt.Errorf("namespace %s is imported and is read-only", namespace)
+	}
+
+	source := p.Namespace(entity.Namespace)
+	if source == nil {
+		return fmt.Errorf("namespace %s of entity %s not found", entity.Namespace, entity.Name)
+	}
+
+	target := p.Namespace(namespace)
+	if target == nil {
+		target = p.AddNamespace(namespace)
+	}
+	if source == target {
+		return nil
+	}
+
+	index := source.EntityIndex(entity.Name)
+	source.Entities = append(source.Entities[:index], source.Entities[index+1:]...)
+	source.reindex()
+	entity.Namespace = target.Name
+	target.AddEntity(entity)
+
+	if vtSource := p.VTNamespace(source.Name); vtSource != nil {
+		if index := vtSource.VTEntityIndex(entity.Name); index != -1 {
+			vtEntity := vtSource.Entities[index]
+			vtSource.Entities = append(vtSource.Entities[:index], vtSource.Entities[index+1:]...)
+
+			vtTarget := p.VTNamespace(target.Name)
+			if vtTarget == nil {
+				vtTarget = NewVTNamespace(target.Name)
+				p.VTNamespaces = append(p.VTNamespaces, vtTarget)
+			}
+			vtTarget.AddVTEntity(vtEntity)
+		}
+	}
+
+	p.moveTable(entity.Table, target.Name)
+
+	for _, translation := range translations {
+		if ns := translation.Namespace(source.Name); ns != nil {
+			if te := ns.Entity(entity.Name); te != nil {
+				ns.DeleteEntity(entity.Name)
+
+				nsTarget := translation.Namespace(target.Name)
+				if nsTarget == nil {
+					nsTarget = &TranslationNamespace{Name: target.Name}
+					translation.AddNamespace(nsTarget)
+				}
+				nsTarget.AddEntity(te)
+			}
+			if len(ns.Entities) == 0 {
+				translation.deleteNamespace(ns.Name)
+			}
+		}
+	}
+
+	if len(source.Entities) == 0 {
+		p.deleteNamespace(source.Name)
+	}
+
+	p.UpdateLinks()
+
+	return p.IsConsistent()
+}
+
+// moveTable moves table to namespace in table mapping if table is mapped
+func (p *Project) moveTable(table, namespace string) {
+	mapped := false
+	for i := 0; i < len(p.TableMapping.Entries); i++ {
+		entry := &p.TableMapping.Entries[i]
+
+		var tables []string
+		for _, t := range strings.Split(entry.Value, ",") {
+			if t == table {
+				mapped = true
+				continue
+			}
+			tables = append(tables, t)
+		}
+		entry.Value = strings.Join(tables, ",")
+
+		if entry.Value == "" {
+			p.TableMapping.Entries = append(p.TableMapping.Entries[:i], p.TableMapping.Entries[i+1:]...)
+			i--
+		}
+	}
+
+	if !mapped {
+		return
+	}
+
+	for i, entry := range p.TableMapping.Entries {
+		if entry.XMLName.Local == namespace {
+			p.TableMapping.Entries[i].Value += "," + table
+			return
+		}
+	}
+
+	p.TableMapping.Entries = append(p.TableMapping.Entries, Entry{XMLName: xml.Name{Local: namespace}, Value: table})
+}
+
+// deleteNamespace removes namespace and its vt namespace from project and databases
+func (p *Project) deleteNamespace(namespace string) {
+	if database := p.Database(namespace); database != nil {
+		var names []string
+		for _, name := range database.NamespaceNames() {
+			if !strings.EqualFold(name, namespace) {
+				names = append(names, name)
+			}
+		}
+		database.Namespaces = strings.Join(names, ",")
+	}
+
+	for i, name := range p.NamespaceNames {
+		if strings.EqualFold(name, namespace) {
+			p.NamespaceNames = append(p.NamespaceNames[:i], p.NamespaceNames[i+1:]...)
+			break
+		}
+	}
+
+	for i, ns := range p.Namespaces {
+		if strings.EqualFold(ns.Name, namespace) {
+			p.Namespaces = append(p.Namespaces[:i], p.Namespaces[i+1:]...)
+			break
+		}
+	}
+
+	for i, ns := range p.VTNamespaces {
+		if strings.EqualFold(ns.Name, namespace) {
+			p.VTNamespaces = append(p.VTNamespaces[:i], p.VTNamespaces[i+1:]...)
+			break
+		}
+	}
+}
+
+// deleteNamespace removes namespace from translation
+func (t *Translation) deleteNamespace(namespace string) {
+	for i, ns := range t.Namespaces {
+		if ns.Name == namespace {
+			t.Namespaces = append(t.Namespaces[:i], t.Namespaces[i+1:]...)
+			return
+		}
+	}
+}
+
+// renameKeys renames translation keys of renamed template attributes
+func (t *Translation) renameKeys(renames []tmplRename) {
+	for _, rename := range renames {
+		te := t.Entity(rename.namespace, rename.entity)
+		if te == nil {
+			continue
+		}
+
+		key, newKey := VarName(rename.name), VarName(rename.newName)
+		te.Form.Rename(key+"Label", newKey+"Label")
+		if te.List != nil {
+			te.List.Filter.Rename(key, newKey)
+			if !IsStatus(key) {
+				te.List.Headers.Rename(key, newKey)
+			}
+		}
+	}
+}
+
+// rename renames translation entity, its key and breadcrumbs made from default key
+func (e *TranslationEntity) rename(name, newName string) {
+	e.Name = newName
+
+	key, newKey := VarName(name), VarName(newName)
+	if e.Key != key {
+		return
+	}
+
+	e.Key = newKey
+	for _, suffix := range []string{"List", "Add", "Edit"} {
+		e.Crumbs.Rename(key+suffix, newKey+suffix)
+	}
+}
@@ -0,0 +1,174 @@
+package mfd
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+const refactorProject = "../generators/testdata/expected/newsportal.mfd"
+
+func loadRefactorProject(t *testing.T) (*Project, *Translation) {
+	t.Helper()
+
+	project, err := LoadProject(refactorProject, false, GoPG10)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+
+	translations, err := LoadTranslations(refactorProject, project.Languages)
+	if err != nil {
+		t.Fatalf("load translations: %v", err)
+	}
+	translation := translations[EnLang]
+
+	return project, &translation
+}
+
+func TestProject_RenameEntity(t *testing.T) {
+	project, translation := loadRefactorProject(t)
+
+	if err := project.RenameEntity("News", "Article", translation); err != nil {
+		t.Fatalf("RenameEntity() error = %v", err)
+	}
+
+	if project.Entity("News") != nil || project.VTEntity("News") != nil {
+		t.Errorf("old entity is found")
+	}
+	if e := project.Entity("Article"); e == nil || project.VTEntity("Article") == nil || project.VTEntity("Article").Entity != e {
+		t.Fatalf("renamed entity is not linked")
+	}
+
+	tmpl := project.VTEntity("Category").TmplAttributes.ByName("Articles")
+	if tmpl == nil || tmpl.Children != "Article.CategoryID" || tmpl.ChildEntity != project.Entity("Article") {
+		t.Errorf("children template = %+v", tmpl)
+	}
+
+	te := translation.Entity("portal", "Article")
+	if te == nil || te.Key != "article" || te.Crumbs.Index("articleList") == -1 || te.Crumbs.Index("newsList") != -1 {
+		t.Errorf("translation entity = %+v", te)
+	}
+
+	if err := project.RenameEntity("Country", "Tag"); err == nil {
+		t.Errorf("RenameEntity() to existing entity should fail")
+	}
+}
+
+func TestProject_RenameEntity_ForeignKey(t *testing.T) {
+	project, translation := loadRefactorProject(t)
+
+	if err := project.RenameEntity("Category", "Rubric", translation); err != nil {
+		t.Fatalf("RenameEntity() error = %v", err)
+	}
+
+	attr := project.Entity("News").AttributeByName("CategoryID")
+	if attr.ForeignKey != "Rubric" || attr.ForeignEntity != project.Entity("Rubric") {
+		t.Errorf("fk = %s", attr.ForeignKey)
+	}
+}
+
+func TestProject_RenameAttribute(t *testing.T) {
+	project, translation := loadRefactorProject(t)
+
+	if err := project.RenameAttribute("News", "Title", "Caption", translation); err != nil {
+		t.Fatalf("RenameAttribute() error = %v", err)
+	}
+
+	news := project.Entity("News")
+	if news.AttributeByName("Title") != nil || news.AttributeByName("Caption") == nil {
+		t.Errorf("attribute is not renamed")
+	}
+	if search := news.SearchByName("CaptionILike"); search == nil || search.AttrName != "Caption" || search.Attribute == nil {
+		t.Errorf("search is not renamed")
+	}
+
+	vtEntity := project.VTEntity("News")
+	if vtAttr := vtEntity.Attribute("Caption"); vtAttr == nil || vtAttr.AttrName != "Caption" || vtAttr.SearchName != "CaptionILike" {
+		t.Errorf("vt attribute = %+v", vtAttr)
+	}
+	if tmpl := vtEntity.TmplAttributes.ByName("Caption"); tmpl == nil || tmpl.AttrName != "Caption" || tmpl.VTAttribute == nil {
+		t.Errorf("template attribute = %+v", tmpl)
+	}
+
+	te := translation.Entity("portal", "News")
+	if te.Form.Index("captionLabel") == -1 || te.List.Filter.Index("caption") == -1 || te.List.Headers.Index("caption") == -1 {
+		t.Errorf("translation keys are not renamed: %v %v", te.Form.Keys(), te.List.Headers.Keys())
+	}
+}
+
+func TestProject_RenameAttribute_ForeignKey(t *testing.T) {
+	project, translation := loadRefactorProject(t)
+
+	if err := project.RenameAttribute("News", "CategoryID", "RubricID", translation); err != nil {
+		t.Fatalf("RenameAttribute() error = %v", err)
+	}
+
+	tmpl := project.VTEntity("Category").TmplAttributes.ByName("News")
+	if tmpl.Children != "News.RubricID" || tmpl.ChildAttribute != project.Entity("News").AttributeByName("RubricID") {
+		t.Errorf("children = %s", tmpl.Children)
+	}
+
+	vtEntity := project.VTEntity("News")
+	if vtAttr := vtEntity.Attribute("RubricID"); vtAttr == nil || vtAttr.SearchName != "RubricID" {
+		t.Errorf("vt attribute = %+v", vtAttr)
+	}
+	if vtEntity.TmplAttributes.ByName("Rubric") == nil || vtEntity.TmplAttributes.ByName("RubricID") == nil {
+		t.Errorf("fk template attributes are not renamed")
+	}
+
+	te := translation.Entity("portal", "News")
+	if te.Form.Index("rubricIdLabel") == -1 {
+		t.Errorf("translation keys are not renamed: %v", te.Form.Keys())
+	}
+}
+
+func TestProject_MoveEntity(t *testing.T) {
+	project, translation := loadRefactorProject(t)
+
+	if err := project.MoveEntity("Tag", "dict", translation); err != nil {
+		t.Fatalf("MoveEntity() error = %v", err)
+	}
+
+	if project.Namespace("portal").Entity("Tag") != nil || project.VTNamespace("portal").VTEntity("Tag") != nil {
+		t.Errorf("entity is left in old namespace")
+	}
+	if e := project.Namespace("dict").Entity("Tag"); e == nil || e.Namespace != "dict" || project.VTNamespace("dict").VTEntity("Tag") == nil {
+		t.Errorf("entity is not moved")
+	}
+	if translation.Entity("dict", "Tag") == nil || translation.Entity("portal", "Tag") != nil {
+		t.Errorf("translation is not moved")
+	}
+
+	for _, name := range project.Namespace("vfs").EntityNames() {
+		if err := project.MoveEntity(name, "dict"); err != nil {
+			t.Fatalf("MoveEntity() error = %v", err)
+		}
+	}
+	if project.Namespace("vfs") != nil {
+		t.Errorf("empty namespace is not removed")
+	}
+	for _, name := range project.NamespaceNames {
+		if name == "vfs" {
+			t.Errorf("empty namespace is listed")
+		}
+	}
+}
+
+func TestProject_moveTable(t *testing.T) {
+	project := NewProject("test", GoPG10)
+	project.TableMapping.Entries = []Entry{
+		{XMLName: xml.Name{Local: "common"}, Value: "users,tags"},
+		{XMLName: xml.Name{Local: "news"}, Value: "news"},
+	}
+
+	project.moveTable("tags", "news")
+	project.moveTable("users", "geo")
+
+	want := []Entry{
+		{XMLName: xml.Name{Local: "news"}, Value: "news,tags"},
+		{XMLName: xml.Name{Local: "geo"}, Value: "users"},
+	}
+	if got := project.TableMapping.Entries; !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package mfd
+
+import "context"
+
+// GenerateFunc runs generator with options, generated files are written to files
+type GenerateFunc[O any] func(ctx context.Context, options O, files *Files) error
+
+// Runner runs generator without cobra command, options are specific to generator
+type Runner[O any] struct {
+	options  O
+	output   Output
+	generate GenerateFunc[O]
+}
+
+// NewRunner creates runner of generator for usage as a library, default options are filled by def if it is set
+func NewRunner[O any](options O, def func(options *O), generate GenerateFunc[O]) *Runner[O] {
+	if def != nil {
+		def(&options)
+	}
+
+	return &Runner[O]{options: options, generate: generate}
+}
+
+// WithOutput sets output for generated files, files are written to disk by default
+func (r *Runner[O]) WithOutput(output Output) *Runner[O] {
+	r.output = output
+	return r
+}
+
+// Generate runs generator and returns written files
+func (r *Runner[O]) Generate(ctx context.Context) ([]string, error) {
+	files := NewFiles(r.output)
+	err := r.generate(ctx, r.options, files)
+
+	return files.Written(), err
+}
@@ -0,0 +1,26 @@
+package mfd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRunner(t *testing.T) {
+	type options struct{ Package string }
+
+	runner := NewRunner(options{}, func(o *options) { o.Package = "db" }, func(ctx context.Context, o options, files *Files) error {
+		_, err := files.Save([]byte("package "+o.Package+"\n"), "db/model.go")
+		return err
+	})
+
+	out := NewMemoryOutput()
+	files, err := runner.WithOutput(out).Generate(context.Background())
+	if err != nil || !reflect.DeepEqual(files, []string{"db/model.go"}) {
+		t.Fatalf("Generate() = %v, %v", files, err)
+	}
+
+	if content, err := out.ReadFile("db/model.go"); err != nil || string(content) != "package db\n" {
+		t.Errorf("Generate() wrote %q, %v", content, err)
+	}
+}
@@ -0,0 +1,478 @@
+package mfd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	XMLSchemaInstance = "http://www.w3.org/2001/XMLSchema-instance"
+	XMLSchema         = "http://www.w3.org/2001/XMLSchema"
+
+	// SchemaXSD is a file name of xsd schema referenced from xml files, schema command writes it next to project file
+	SchemaXSD = "mfd.xsd"
+
+	// SchemaJSON is a file name of json schema for yaml and json files
+	SchemaJSON = "mfd.schema.json"
+)
+
+// schemaEnums stores enumerations of attribute values by name
+var schemaEnums = map[string][]string{
+	"Nullable":   {NullableYes, NullableNo, NullableEmpty},
+	"SearchType": searchTypeNames(),
+	// empty mode is filled on load
+	"Mode": {"", ModeFull, ModeNone, ModeReadOnly, ModeReadOnlyWithTemplates},
+	"HTMLType": {
+		"", TypeHTMLNone, TypeHTMLInput, TypeHTMLText, TypeHTMLPassword, TypeHTMLEditor, TypeHTMLCheckbox,
+		TypeHTMLDateTime, TypeHTMLDate, TypeHTMLTime, TypeHTMLSelect, TypeHTMLFile, TypeHTMLImage,
+		TypeHTMLAutocomplete, TypeHTMLChildren,
+	},
+	"OutputGenerator": OutputGenerators,
+}
+
+func searchTypeNames() []string {
+	names := make([]string, len(SearchTypes))
+	for i, searchType := range SearchTypes {
+		names[i] = string(searchType)
+	}
+
+	return names
+}
+
+// schemaEnumFields maps Type.Field to enumeration name
+var schemaEnumFields = map[string]string{
+	"Attribute.Null":       "Nullable",
+	"Search.SearchType":    "SearchType",
+	"VTEntity.Mode":        "Mode",
+	"TmplAttribute.Form":   "HTMLType",
+	"TmplAttribute.Search": "HTMLType",
+	"OutputRule.Generator": "OutputGenerator",
+}
+
+// schemaRoots stores root elements of project files
+var schemaRoots = []interface{}{&Project{}, &Namespace{}, &VTNamespace{}, &Translation{}}
+
+// schemaType describes struct stored in project files
+type schemaType struct {
+	name  string
+	attrs []schemaNode
+	elems []schemaNode
+
+	// any stores elements with arbitrary names and string values, like Dictionary entries
+	any bool
+}
+
+// schemaNode describes attribute or element of schemaType
+type schemaNode struct {
+	name string
+
+	// item is a name of repeated child element, e.g. Entity in Entities>Entity
+	item     string
+	repeated bool
+
+	// kind is a simple type: string, int or bool, empty for complex types
+	kind string
+	enum string
+	typ  *schemaType
+}
+
+// simpleKind returns simple type of go type, empty for complex types
+func simpleKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.String:
+		return "string"
+	}
+
+	return ""
+}
+
+// cachedSchema builds schema once, built types are shared and must not be changed
+var cachedSchema = sync.OnceValues(buildSchema)
+
+// buildSchema describes root types and all types used by them
+func buildSchema() ([]*schemaType, []*schemaType) {
+	types := map[reflect.Type]*schemaType{}
+
+	var build func(t reflect.Type) *schemaType
+	build = func(t reflect.Type) *schemaType {
+		if st, ok := types[t]; ok {
+			return st
+		}
+
+		st := &schemaType{name: t.Name(), any: t == xmlMapType}
+		types[t] = st
+		if st.any {
+			return st
+		}
+
+		for _, f := range storageFields(t) {
+			field := t.Field(f.index)
+			if f.any {
+				st.any = true
+				continue
+			}
+
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			node := schemaNode{name: f.name, enum: schemaEnumFields[t.Name()+"."+field.Name]}
+			tag := field.Tag.Get("xml")
+			if strings.Contains(tag, ",attr") {
+				node.kind = simpleKind(ft)
+				st.attrs = append(st.attrs, node)
+				continue
+			}
+
+			if ft.Kind() == reflect.Slice {
+				node.repeated = true
+				if _, item, ok := strings.Cut(strings.Split(tag, ",")[0], ">"); ok {
+					node.item = item
+				}
+
+				ft = ft.Elem()
+				for ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+			}
+
+			if node.kind = simpleKind(ft); node.kind == "" {
+				node.typ = build(ft)
+			}
+			st.elems = append(st.elems, node)
+		}
+
+		return st
+	}
+
+	roots := make([]*schemaType, len(schemaRoots))
+	for i, root := range schemaRoots {
+		roots[i] = build(reflect.TypeOf(root).Elem())
+	}
+
+	all := make([]*schemaType, 0, len(types))
+	for _, st := range types {
+		all = append(all, st)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].name < all[j].name })
+
+	return roots, all
+}
+
+// rootElement returns xml name of root type
+func rootElement(t reflect.Type) string {
+	field, _ := t.FieldByName("XMLName")
+	name, _, _ := strings.Cut(field.Tag.Get("xml"), ",")
+	return name
+}
+
+// XSD returns xsd schema of project, namespace, vt namespace and translation xml files
+func XSD() []byte {
+	roots, types := cachedSchema()
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<xs:schema xmlns:xs="` + XMLSchema + `">` + "\n")
+
+	for i, root := range roots {
+		fmt.Fprintf(&b, "    <xs:element name=\"%s\" type=\"%s\"/>\n", rootElement(reflect.TypeOf(schemaRoots[i]).Elem()), root.name)
+	}
+
+	for _, st := range types {
+		fmt.Fprintf(&b, "    <xs:complexType name=\"%s\">\n", st.name)
+
+		if len(st.elems) != 0 || st.any {
+			// xs:all keeps any order of elements, but does not support repeated elements
+			group := "all"
+			for _, el := range st.elems {
+				if el.repeated && el.item == "" {
+					group = "sequence"
+				}
+			}
+			if st.any {
+				group = "sequence"
+			}
+
+			fmt.Fprintf(&b, "        <xs:%s>\n", group)
+			for _, el := range st.elems {
+				writeXSDElement(&b, el)
+			}
+			if st.any {
+				b.WriteString("            <xs:any processContents=\"skip\" minOccurs=\"0\" maxOccurs=\"unbounded\"/>\n")
+			}
+			fmt.Fprintf(&b, "        </xs:%s>\n", group)
+		}
+
+		for _, attr := range st.attrs {
+			fmt.Fprintf(&b, "        <xs:attribute name=\"%s\" type=\"%s\"/>\n", attr.name, xsdType(attr))
+		}
+
+		b.WriteString("    </xs:complexType>\n")
+	}
+
+	names := make([]string, 0, len(schemaEnums))
+	for name := range schemaEnums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(&b, "    <xs:simpleType name=\"%s\">\n        <xs:restriction base=\"xs:string\">\n", name)
+		for _, value := range schemaEnums[name] {
+			fmt.Fprintf(&b, "            <xs:enumeration value=\"%s\"/>\n", value)
+		}
+		b.WriteString("        </xs:restriction>\n    </xs:simpleType>\n")
+	}
+
+	b.WriteString("</xs:schema>\n")
+
+	return b.Bytes()
+}
+
+func xsdType(node schemaNode) string {
+	switch {
+	case node.enum != "":
+		return node.enum
+	case node.typ != nil:
+		return node.typ.name
+	case node.kind == "int":
+		return "xs:int"
+	case node.kind == "bool":
+		return "xs:boolean"
+	}
+
+	return "xs:string"
+}
+
+func writeXSDElement(b *bytes.Buffer, el schemaNode) {
+	switch {
+	case el.item != "":
+		fmt.Fprintf(b, "            <xs:element name=\"%s\" minOccurs=\"0\">\n", el.name)
+		b.WriteString("                <xs:complexType>\n                    <xs:sequence>\n")
+		fmt.Fprintf(b, "                        <xs:element name=\"%s\" type=\"%s\" minOccurs=\"0\" maxOccurs=\"unbounded\"/>\n", el.item, xsdType(el))
+		b.WriteString("                    </xs:sequence>\n                </xs:complexType>\n")
+		b.WriteString("            </xs:element>\n")
+	case el.repeated:
+		fmt.Fprintf(b, "            <xs:element name=\"%s\" type=\"%s\" minOccurs=\"0\" maxOccurs=\"unbounded\"/>\n", el.name, xsdType(el))
+	default:
+		fmt.Fprintf(b, "            <xs:element name=\"%s\" type=\"%s\" minOccurs=\"0\"/>\n", el.name, xsdType(el))
+	}
+}
+
+// JSONSchema returns json schema of project, namespace, vt namespace and translation yaml and json files
+func JSONSchema() ([]byte, error) {
+	roots, types := cachedSchema()
+
+	defs := map[string]interface{}{}
+	for _, st := range types {
+		if st.any && len(st.elems) == 0 && len(st.attrs) == 0 {
+			defs[st.name] = map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]string{"type": "string"},
+			}
+			continue
+		}
+
+		properties := map[string]interface{}{}
+		for _, node := range slices.Concat(st.attrs, st.elems) {
+			properties[node.name] = jsonSchemaType(node)
+		}
+
+		def := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if st.any {
+			def["additionalProperties"] = map[string]string{"type": "string"}
+		}
+		defs[st.name] = def
+	}
+
+	for name, values := range schemaEnums {
+		defs[name] = map[string]interface{}{"type": "string", "enum": values}
+	}
+
+	anyOf := make([]interface{}, len(roots))
+	for i, root := range roots {
+		anyOf[i] = map[string]string{"$ref": "#/$defs/" + root.name}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "mfd project file",
+		"anyOf":   anyOf,
+		"$defs":   defs,
+	}, "", "    ")
+}
+
+func jsonSchemaType(node schemaNode) interface{} {
+	var item interface{}
+	switch {
+	case node.enum != "":
+		item = map[string]string{"$ref": "#/$defs/" + node.enum}
+	case node.typ != nil:
+		item = map[string]string{"$ref": "#/$defs/" + node.typ.name}
+	case node.kind == "int":
+		item = map[string]string{"type": "integer"}
+	case node.kind == "bool":
+		item = map[string]string{"type": "boolean"}
+	default:
+		item = map[string]string{"type": "string"}
+	}
+
+	if node.repeated {
+		return map[string]interface{}{"type": "array", "items": item}
+	}
+
+	return item
+}
+
+// schemaEnum returns allowed values of struct field, nil if values are not restricted
+func schemaEnum(t reflect.Type, field string) []string {
+	return schemaEnums[schemaEnumFields[t.Name()+"."+field]]
+}
+
+// checkValue checks simple value of attribute or element
+func checkValue(node schemaNode, value string) error {
+	switch {
+	case node.enum != "":
+		for _, v := range schemaEnums[node.enum] {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q, expected one of %s", value, strings.Join(schemaEnums[node.enum], ", "))
+	case node.kind == "int":
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("int expected, got %q", value)
+		}
+	case node.kind == "bool":
+		if _, err := strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("bool expected, got %q", value)
+		}
+	}
+
+	return nil
+}
+
+// ValidateXML checks xml file against schema: values of known attributes and elements should match their types and enumerations.
+// Unknown attributes and elements are skipped for backward compatibility.
+func ValidateXML(data []byte) error {
+	roots, _ := cachedSchema()
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var errs []error
+	check := func(node schemaNode, owner, value string) {
+		if err := checkValue(node, value); err != nil {
+			line, _ := dec.InputPos()
+			errs = append(errs, fmt.Errorf("line %d: %s %s: %w", line, owner, node.name, err))
+		}
+	}
+
+	// stack of expected types, nil for skipped elements
+	var (
+		stack []*schemaType
+		text  *schemaNode
+		value string
+	)
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var st *schemaType
+			text = nil
+			if len(stack) == 0 {
+				for i, root := range roots {
+					if rootElement(reflect.TypeOf(schemaRoots[i]).Elem()) == t.Name.Local {
+						st = root
+					}
+				}
+				if st == nil {
+					return fmt.Errorf("unknown root element %s", t.Name.Local)
+				}
+			} else if parent := stack[len(stack)-1]; parent != nil {
+				st, text = parent.child(t.Name.Local)
+			}
+
+			if st != nil {
+				for _, attr := range t.Attr {
+					for _, node := range st.attrs {
+						if node.name == attr.Name.Local && attr.Name.Space == "" {
+							check(node, t.Name.Local, attr.Value)
+						}
+					}
+				}
+			}
+
+			stack = append(stack, st)
+			value = ""
+		case xml.CharData:
+			value += string(t)
+		case xml.EndElement:
+			if text != nil {
+				check(*text, t.Name.Local, value)
+				text = nil
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// child returns type of child element, for simple elements returns element node
+func (st *schemaType) child(name string) (*schemaType, *schemaNode) {
+	for i, el := range st.elems {
+		switch {
+		case el.item != "" && el.name == name:
+			// wrapper element
+			return &schemaType{name: el.name, elems: []schemaNode{{name: el.item, kind: el.kind, enum: el.enum, typ: el.typ}}}, nil
+		case el.name == name && el.typ != nil:
+			return el.typ, nil
+		case el.name == name:
+			return nil, &st.elems[i]
+		}
+	}
+
+	return nil, nil
+}
+
+// referSchema fills xml namespaces and reference to xsd schema of xml file
+func (p *Project) referSchema() {
+	p.XMLxsi, p.XMLxsd, p.XMLSchema = XMLSchemaInstance, XMLSchema, SchemaXSD
+}
+
+func (n *Namespace) referSchema() {
+	n.XMLxsi, n.XMLxsd, n.XMLSchema = XMLSchemaInstance, XMLSchema, SchemaXSD
+}
+
+func (n *VTNamespace) referSchema() {
+	n.XMLxsi, n.XMLxsd, n.XMLSchema = XMLSchemaInstance, XMLSchema, SchemaXSD
+}
+
+func (t *Translation) referSchema() {
+	t.XMLxsi, t.XMLxsd, t.XMLSchema = XMLSchemaInstance, XMLSchema, SchemaXSD
+}
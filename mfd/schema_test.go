@@ -0,0 +1,71 @@
+package mfd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidateXML(t *testing.T) {
+	for _, file := range []string{"newsportal.mfd", "portal.xml", "portal.vt.xml", "en.xml"} {
+		data, err := os.ReadFile("../generators/testdata/expected/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidateXML(data); err != nil {
+			t.Errorf("%s: %v", file, err)
+		}
+	}
+
+	tests := []struct {
+		name, data, err string
+	}{
+		{"enum", `<Package><Entities><Entity Name="News"><Attributes><Attribute Name="ID" Nullable="Maybe"></Attribute></Attributes></Entity></Entities></Package>`, `line 1: Attribute Nullable: invalid value "Maybe"`},
+		{"bool", `<VTNamespace><VTEntities><Entity Name="News" Audit="yes"></Entity></VTEntities></VTNamespace>`, `Entity Audit: bool expected, got "yes"`},
+		{"int element", "<Project>\n<GoPGVer>ten</GoPGVer></Project>", `line 2: GoPGVer GoPGVer: int expected, got "ten"`},
+		{"root", `<Model></Model>`, `unknown root element Model`},
+		{"unknown is skipped", `<Package><Custom Nullable="Maybe"></Custom><Entities><Entity Unknown="1"></Entity></Entities></Package>`, ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateXML([]byte(tt.data))
+			if tt.err == "" && err != nil {
+				t.Errorf("ValidateXML() unexpected error %v", err)
+			} else if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("ValidateXML() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	xsd := string(XSD())
+	for _, s := range []string{
+		`<xs:element name="Package" type="Namespace"/>`,
+		`<xs:attribute name="SearchType" type="SearchType"/>`,
+		`<xs:enumeration value="ReadOnlyWithTemplates"/>`,
+		`<xs:element name="Attribute" type="TmplAttribute" minOccurs="0" maxOccurs="unbounded"/>`,
+	} {
+		if !strings.Contains(xsd, s) {
+			t.Errorf("xsd has no %s", s)
+		}
+	}
+
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Defs map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	for _, def := range []string{"Project", "Namespace", "Entity", "Attribute", "Search", "VTNamespace", "VTEntity", "VTAttribute", "TmplAttribute", "HTMLType", "Nullable"} {
+		if _, ok := schema.Defs[def]; !ok {
+			t.Errorf("json schema has no %s definition", def)
+		}
+	}
+}
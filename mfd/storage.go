@@ -0,0 +1,529 @@
+package mfd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	FormatXML  = "xml"
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Storage encodes and decodes project files in one of on-disk formats
+type Storage interface {
+	// Marshal encodes project, namespace, vt namespace or translation
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes data into project, namespace, vt namespace or translation
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Format returns storage format by file extension: .yaml and .yml are yaml, .json is json, other files (.mfd, .xml) are xml
+func Format(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".json":
+		return FormatJSON
+	}
+
+	return FormatXML
+}
+
+// NewStorage returns storage for format
+func NewStorage(format string) (Storage, error) {
+	switch format {
+	case FormatXML:
+		return xmlStorage{}, nil
+	case FormatYAML:
+		return yamlStorage{}, nil
+	case FormatJSON:
+		return jsonStorage{}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %s", format)
+}
+
+// StorageByFile returns storage chosen by file extension
+func StorageByFile(filename string) Storage {
+	storage, _ := NewStorage(Format(filename))
+	return storage
+}
+
+// FileExt returns extension of namespace, vt namespace and translation files for project file
+func FileExt(project string) string {
+	switch Format(project) {
+	case FormatYAML:
+		return strings.ToLower(filepath.Ext(project))
+	case FormatJSON:
+		return ".json"
+	}
+
+	return ".xml"
+}
+
+// NamespaceFile returns path of namespace file placed next to project file, e.g. portal.xml
+func NamespaceFile(project, namespace string) string {
+	return path.Join(filepath.Dir(project), namespace+FileExt(project))
+}
+
+// VTNamespaceFile returns path of vt namespace file placed next to project file, e.g. portal.vt.xml
+func VTNamespaceFile(project, namespace string) string {
+	return path.Join(filepath.Dir(project), namespace+".vt"+FileExt(project))
+}
+
+// TranslationFile returns path of translation file placed next to project file, e.g. en.xml
+func TranslationFile(project, language string) string {
+	return path.Join(filepath.Dir(project), language+FileExt(project))
+}
+
+type xmlStorage struct{}
+
+func (xmlStorage) Marshal(v interface{}) ([]byte, error) {
+	if r, ok := v.(interface{ referSchema() }); ok {
+		r.referSchema()
+	}
+
+	b, err := xml.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	// need for json searching rules
+	b = bytes.ReplaceAll(b, []byte("-&gt;"), []byte("->"))
+
+	// append line break at end of file, if not exists
+	if !bytes.HasSuffix(b, []byte("\n")) {
+		b = append(b, []byte("\n")...)
+	}
+
+	return b, nil
+}
+
+func (xmlStorage) Unmarshal(data []byte, v interface{}) error {
+	return xml.Unmarshal(data, v)
+}
+
+// yamlStorage uses the same element and attribute names as xml, empty values are omitted
+type yamlStorage struct{}
+
+func (yamlStorage) Marshal(v interface{}) ([]byte, error) {
+	node := encodeNode(reflect.ValueOf(v), true)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (yamlStorage) Unmarshal(data []byte, v interface{}) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+
+	return decodeNode(&node, reflect.ValueOf(v), "")
+}
+
+// jsonStorage uses the same element and attribute names as xml, empty values are omitted
+type jsonStorage struct{}
+
+func (jsonStorage) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, encodeNode(reflect.ValueOf(v), true), ""); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+func (jsonStorage) Unmarshal(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	node, err := readJSON(dec)
+	if err != nil {
+		return err
+	}
+
+	return decodeNode(node, reflect.ValueOf(v), "")
+}
+
+var (
+	xmlNameType = reflect.TypeOf(xml.Name{})
+	xmlMapType  = reflect.TypeOf(XMLMap{})
+	entriesType = reflect.TypeOf([]Entry{})
+)
+
+// storageField is a struct field stored in yaml and json under its xml name
+type storageField struct {
+	index int
+	name  string
+	any   bool
+}
+
+// storageFields returns fields of struct in declaration order, fields skipped by xml, xml names and xmlns attributes are omitted
+func storageFields(t reflect.Type) []storageField {
+	var fields []storageField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Type == xmlNameType {
+			continue
+		}
+
+		tag := f.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		name, _, _ = strings.Cut(name, ">")
+		if strings.Contains(name, ":") {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, storageField{
+			index: i,
+			name:  name,
+			any:   opts == "any" && f.Type == entriesType,
+		})
+	}
+
+	return fields
+}
+
+func scalarNode(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+// encodeNode converts value to yaml node, returns nil for empty values unless keepEmpty is set
+func encodeNode(v reflect.Value, keepEmpty bool) *yaml.Node {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeNode(v.Elem(), keepEmpty)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		// nil and empty pointers differ, so keep empty pointed values
+		return encodeNode(v.Elem(), true)
+	case reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if v.Type() == xmlMapType {
+			m := v.Addr().Interface().(*XMLMap)
+			for _, el := range m.elements {
+				node.Content = append(node.Content, scalarNode("!!str", el.XMLName.Local), scalarNode("!!str", el.Value))
+			}
+		} else {
+			for _, f := range storageFields(v.Type()) {
+				if f.any {
+					for _, e := range v.Field(f.index).Interface().([]Entry) {
+						node.Content = append(node.Content, scalarNode("!!str", e.XMLName.Local), scalarNode("!!str", e.Value))
+					}
+					continue
+				}
+
+				if value := encodeNode(v.Field(f.index), false); value != nil {
+					node.Content = append(node.Content, scalarNode("!!str", f.name), value)
+				}
+			}
+		}
+		if len(node.Content) == 0 && !keepEmpty {
+			return nil
+		}
+		return node
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return nil
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := 0; i < v.Len(); i++ {
+			value := encodeNode(v.Index(i), true)
+			if value == nil {
+				value = scalarNode("!!null", "null")
+			}
+			node.Content = append(node.Content, value)
+		}
+		return node
+	}
+
+	if v.IsZero() && !keepEmpty {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return scalarNode("!!bool", strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return scalarNode("!!int", strconv.FormatInt(v.Int(), 10))
+	case reflect.Float32, reflect.Float64:
+		return scalarNode("!!float", strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	}
+
+	return scalarNode("!!str", fmt.Sprint(v.Interface()))
+}
+
+// decodeNode fills value from yaml node, p is a path of node used in errors
+func decodeNode(node *yaml.Node, v reflect.Value, p string) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return decodeNode(node.Content[0], v, p)
+	case yaml.AliasNode:
+		return decodeNode(node.Alias, v, p)
+	}
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeNode(node, v.Elem(), p)
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s: mapping expected", position(node, p))
+		}
+		return decodeStruct(node, v, p)
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%s: sequence expected", position(node, p))
+		}
+		slice := reflect.MakeSlice(v.Type(), len(node.Content), len(node.Content))
+		for i, item := range node.Content {
+			if err := decodeNode(item, slice.Index(i), fmt.Sprintf("%s[%d]", p, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%s: scalar expected", position(node, p))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(node.Value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(node.Value)
+		if err != nil {
+			return fmt.Errorf("%s: bool expected, got %q", position(node, p), node.Value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(node.Value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: int expected, got %q", position(node, p), node.Value)
+		}
+		v.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: float expected, got %q", position(node, p), node.Value)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("%s: unsupported type %s", position(node, p), v.Type())
+	}
+
+	return nil
+}
+
+func decodeStruct(node *yaml.Node, v reflect.Value, p string) error {
+	if v.Type() == xmlMapType {
+		m := v.Addr().Interface().(*XMLMap)
+		*m = XMLMap{elements: []xmlMapElement{}}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			m.elements = append(m.elements, xmlMapElement{XMLName: xml.Name{Local: node.Content[i].Value}, Value: node.Content[i+1].Value})
+		}
+		return nil
+	}
+
+	byName := map[string]storageField{}
+	anyField := -1
+	for _, f := range storageFields(v.Type()) {
+		if f.any {
+			anyField = f.index
+			continue
+		}
+		byName[f.name] = f
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+
+		if f, ok := byName[key]; ok {
+			fp := strings.TrimPrefix(p+"."+key, ".")
+			if err := decodeNode(value, v.Field(f.index), fp); err != nil {
+				return err
+			}
+			if enum := schemaEnum(v.Type(), v.Type().Field(f.index).Name); enum != nil && !slices.Contains(enum, value.Value) {
+				return fmt.Errorf("%s: invalid value %q, expected one of %s", position(value, fp), value.Value, strings.Join(enum, ", "))
+			}
+			continue
+		}
+
+		if anyField == -1 {
+			return fmt.Errorf("%s: unknown field %s", position(node.Content[i], p), key)
+		}
+
+		entries := v.Field(anyField)
+		entries.Set(reflect.Append(entries, reflect.ValueOf(Entry{XMLName: xml.Name{Local: key}, Value: value.Value})))
+	}
+
+	return nil
+}
+
+// position returns path of node with line if known
+func position(node *yaml.Node, p string) string {
+	if p == "" {
+		p = "root"
+	}
+	if node.Line > 0 {
+		return fmt.Sprintf("line %d: %s", node.Line, p)
+	}
+
+	return p
+}
+
+// writeJSON writes yaml node as indented json preserving keys order
+func writeJSON(buf *bytes.Buffer, node *yaml.Node, indent string) error {
+	if node == nil {
+		buf.WriteString("null")
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		open, closing, step := "{", "}", 2
+		if node.Kind == yaml.SequenceNode {
+			open, closing, step = "[", "]", 1
+		}
+		if len(node.Content) == 0 {
+			buf.WriteString(open + closing)
+			return nil
+		}
+
+		buf.WriteString(open + "\n")
+		for i := 0; i < len(node.Content); i += step {
+			buf.WriteString(indent + "    ")
+			if step == 2 {
+				if err := writeJSONString(buf, node.Content[i].Value); err != nil {
+					return err
+				}
+				buf.WriteString(": ")
+			}
+			if err := writeJSON(buf, node.Content[i+step-1], indent+"    "); err != nil {
+				return err
+			}
+			if i+step < len(node.Content) {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + closing)
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" {
+			return writeJSONString(buf, node.Value)
+		}
+		buf.WriteString(node.Value)
+	default:
+		return fmt.Errorf("unsupported node kind %d", node.Kind)
+	}
+
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	// html escaping breaks json searching rules like Params->Title
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+
+	return nil
+}
+
+// readJSON reads json value from decoder as yaml node preserving keys order
+func readJSON(dec *json.Decoder) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected end of json")
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if t == '[' {
+			node.Kind = yaml.SequenceNode
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, scalarNode("!!str", fmt.Sprint(key)))
+			}
+
+			value, err := readJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		// closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return scalarNode("!!str", t), nil
+	case json.Number:
+		return scalarNode("!!int", t.String()), nil
+	case bool:
+		return scalarNode("!!bool", strconv.FormatBool(t)), nil
+	}
+
+	return scalarNode("!!null", "null"), nil
+}
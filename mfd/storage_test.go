@@ -0,0 +1,70 @@
+package mfd
+
+import (
+	"testing"
+)
+
+func TestStorageFiles(t *testing.T) {
+	tests := []struct {
+		project, format, namespace, vt, translation string
+	}{
+		{"docs/newsportal.mfd", FormatXML, "docs/portal.xml", "docs/portal.vt.xml", "docs/en.xml"},
+		{"docs/newsportal.yaml", FormatYAML, "docs/portal.yaml", "docs/portal.vt.yaml", "docs/en.yaml"},
+		{"docs/newsportal.YML", FormatYAML, "docs/portal.yml", "docs/portal.vt.yml", "docs/en.yml"},
+		{"newsportal.json", FormatJSON, "portal.json", "portal.vt.json", "en.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.project, func(t *testing.T) {
+			if got := Format(tt.project); got != tt.format {
+				t.Errorf("Format() = %v, want %v", got, tt.format)
+			}
+			if got := NamespaceFile(tt.project, "portal"); got != tt.namespace {
+				t.Errorf("NamespaceFile() = %v, want %v", got, tt.namespace)
+			}
+			if got := VTNamespaceFile(tt.project, "portal"); got != tt.vt {
+				t.Errorf("VTNamespaceFile() = %v, want %v", got, tt.vt)
+			}
+			if got := TranslationFile(tt.project, "en"); got != tt.translation {
+				t.Errorf("TranslationFile() = %v, want %v", got, tt.translation)
+			}
+		})
+	}
+}
+
+func TestStorage_Unmarshal(t *testing.T) {
+	yamlData := []byte("Name: portal\nEntities:\n  - Name: News\n    Attributes:\n      - Name: ID\n        PK: true\n        Addable: false\n")
+	jsonData := []byte(`{"Name": "portal", "Entities": [{"Name": "News", "Attributes": [{"Name": "ID", "PK": true, "Addable": false}]}]}`)
+
+	for format, data := range map[string][]byte{FormatYAML: yamlData, FormatJSON: jsonData} {
+		storage, err := NewStorage(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ns := &Namespace{}
+		if err := storage.Unmarshal(data, ns); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		if len(ns.Entities) != 1 || len(ns.Entities[0].Attributes) != 1 {
+			t.Fatalf("%s: unexpected namespace %+v", format, ns)
+		}
+		attr := ns.Entities[0].Attributes[0]
+		if !attr.PrimaryKey || attr.Addable == nil || *attr.Addable || attr.Updatable != nil {
+			t.Errorf("%s: unexpected attribute %+v", format, attr)
+		}
+
+		if err := storage.Unmarshal([]byte("Name: portal\nUnknown: 1\n"), &Namespace{}); format == FormatYAML && err == nil {
+			t.Errorf("%s: expected error for unknown field", format)
+		}
+	}
+}
+
+func TestStorage_UnmarshalEnum(t *testing.T) {
+	data := []byte("Name: portal\nEntities:\n  - Name: News\n    Attributes:\n      - Name: ID\n        Nullable: Maybe\n")
+
+	err := yamlStorage{}.Unmarshal(data, &Namespace{})
+	if err == nil || err.Error() != `line 6: Entities[0].Attributes[0].Nullable: invalid value "Maybe", expected one of Yes, No, CheckEmpty` {
+		t.Errorf("Unmarshal() error = %v", err)
+	}
+}
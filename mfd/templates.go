@@ -0,0 +1,198 @@
+package mfd
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dizzyfool/genna/model"
+	"github.com/dizzyfool/genna/util"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+const (
+	// TemplateExt is an extension of custom template files
+	TemplateExt = ".tmpl"
+	// PartialsDir is a dir of custom partials in generator templates dir
+	PartialsDir = "partials"
+)
+
+func raw(str string) template.HTML {
+	return template.HTML(str)
+}
+
+func title(str string) template.HTML {
+	c := cases.Title(language.Und, cases.NoLower)
+	return template.HTML(c.String(str))
+}
+
+// dict creates map from key value pairs, it is used to pass several values to sub-template
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict expects key value pairs")
+	}
+
+	result := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		result[key] = pairs[i+1]
+	}
+
+	return result, nil
+}
+
+// comment formats text as go comment lines
+func comment(text string) template.HTML {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+strings.TrimSpace(line), " ")
+	}
+
+	return template.HTML(strings.Join(lines, "\n"))
+}
+
+// isNumber checks if go type is a number or pointer to number
+func isNumber(goType string) bool {
+	goType = strings.TrimPrefix(goType, "*")
+	for _, prefix := range []string{"int", "uint", "float"} {
+		if strings.HasPrefix(goType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TemplateFunctions are functions available in all templates.
+// It is read by concurrent generators and must not be modified, use CloneTemplateFunctions to add functions.
+var TemplateFunctions = template.FuncMap{
+	"raw":     raw,
+	"ToLower": strings.ToLower,
+	"ToUpper": strings.ToUpper,
+	"title":   title,
+	"notLast": func(index int, length int) bool {
+		return index+1 != length
+	},
+	"isLast": func(index int, length int) bool {
+		return index+1 == length
+	},
+	"dict":    dict,
+	"comment": comment,
+
+	// strings
+	"hasPrefix":  strings.HasPrefix,
+	"hasSuffix":  strings.HasSuffix,
+	"contains":   strings.Contains,
+	"replace":    strings.ReplaceAll,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+	"join":       strings.Join,
+	"split":      strings.Split,
+
+	// case conversions
+	"camelCase":  util.CamelCased,
+	"lowerFirst": util.LowerFirst,
+	"snakeCase":  util.Underscore,
+	"kebabCase":  URLName,
+	"varName":    VarName,
+	"jsonName":   JSONName,
+	"plural":     MakePlural,
+	"singular":   util.Singular,
+
+	// go type predicates
+	"isArray":   func(goType string) bool { return strings.HasPrefix(goType, "[]") },
+	"isMap":     func(goType string) bool { return strings.HasPrefix(goType, "map[") },
+	"isPointer": func(goType string) bool { return strings.HasPrefix(goType, "*") },
+	"isTime":    func(goType string) bool { return strings.TrimPrefix(goType, "*") == "time.Time" },
+	"isString":  func(goType string) bool { return strings.TrimPrefix(goType, "*") == model.TypeString },
+	"isBool":    func(goType string) bool { return strings.TrimPrefix(goType, "*") == model.TypeBool },
+	"isNumber":  isNumber,
+}
+
+// CloneTemplateFunctions returns copy of TemplateFunctions, it is safe to add functions to it
+func CloneTemplateFunctions() template.FuncMap {
+	return maps.Clone(TemplateFunctions)
+}
+
+// Templates are custom templates of generator from templates dir, nil Templates use built-in templates:
+// <dir>/<generator>/<name>.tmpl replaces built-in template with the same name,
+// <dir>/<generator>/partials/*.tmpl redefine named sub-templates of built-in templates.
+type Templates struct {
+	dir      string
+	partials []string
+}
+
+// LoadTemplates reads partials of generator from templates dir, empty dir means built-in templates
+func LoadTemplates(dir, generator string) (*Templates, error) {
+	templates := &Templates{}
+	if dir == "" {
+		return templates, nil
+	}
+
+	templates.dir = filepath.Join(dir, generator)
+	files, err := filepath.Glob(filepath.Join(templates.dir, PartialsDir, "*"+TemplateExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read partial %s, err=%w", file, err)
+		}
+		templates.partials = append(templates.partials, string(content))
+	}
+
+	return templates, nil
+}
+
+// Template returns template from path if it is set, from templates dir if file exists or built-in template
+func (t *Templates) Template(name, path, def string) (string, error) {
+	if path == "" && t != nil && t.dir != "" {
+		path = filepath.Join(t.dir, name+TemplateExt)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return def, nil
+		}
+	}
+
+	return LoadTemplate(path, def)
+}
+
+// Partials returns custom partials, they are parsed after template
+func (t *Templates) Partials() []string {
+	if t == nil {
+		return nil
+	}
+
+	return t.partials
+}
+
+// TemplateFiles returns template files of generator in templates dir
+func TemplateFiles(dir, generator string) []string {
+	var files []string
+	_ = filepath.WalkDir(filepath.Join(dir, generator), func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() && filepath.Ext(path) == TemplateExt {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	return files
+}
+
+// BuiltinTemplate is a built-in template of generator
+type BuiltinTemplate struct {
+	Name   string
+	Source string
+}
@@ -0,0 +1,100 @@
+package mfd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "repo", PartialsDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "repo", name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("repo.tmpl", `{{range .}}{{template "item" .}}{{end}}{{define "item"}}[{{.}}]{{end}}`)
+	write("flag.tmpl", `{{.}}`)
+	write(filepath.Join(PartialsDir, "item.tmpl"), `{{define "item"}}{{.Key | snakeCase}}={{.Value | plural}} {{end}}`)
+
+	templates, err := LoadTemplates(dir, "repo")
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	tests := []struct {
+		name, path, want string
+	}{
+		{name: "repo", want: `{{range .}}{{template "item" .}}{{end}}{{define "item"}}[{{.}}]{{end}}`},
+		{name: "repo", path: filepath.Join(dir, "repo", "flag.tmpl"), want: `{{.}}`},
+		{name: "search", want: "default"},
+	}
+	for _, tt := range tests {
+		if got, err := templates.Template(tt.name, tt.path, "default"); err != nil || got != tt.want {
+			t.Errorf("Template(%s, %s) = %q, %v, want %q", tt.name, tt.path, got, err, tt.want)
+		}
+	}
+
+	var nilTemplates *Templates
+	if got, err := nilTemplates.Template("repo", "", "default"); err != nil || got != "default" || nilTemplates.Partials() != nil {
+		t.Errorf("nil Templates should return built-in template, got %q, %v", got, err)
+	}
+
+	tmpl, _ := templates.Template("repo", "", "")
+	data := []map[string]string{{"Key": "NewsCategory", "Value": "tag"}}
+
+	var buffer bytes.Buffer
+	if err := Render(&buffer, tmpl, data, templates.Partials()...); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got, want := buffer.String(), "news_category=tags "; got != want {
+		t.Errorf("Render() with partials = %q, want %q", got, want)
+	}
+
+	if files := TemplateFiles(dir, "repo"); len(files) != 3 {
+		t.Errorf("TemplateFiles() = %v", files)
+	}
+}
+
+func TestTemplateFunctions(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		data any
+		want string
+	}{
+		{tmpl: `{{template "sub" dict "Name" .Name "Type" "int"}}{{define "sub"}}{{.Name}} {{.Type}}{{end}}`, data: map[string]string{"Name": "id"}, want: "id int"},
+		{tmpl: `{{camelCase .}} {{lowerFirst (camelCase .)}}`, data: "news_category", want: "NewsCategory newsCategory"},
+		{tmpl: `{{snakeCase .}} {{kebabCase .}} {{varName .}} {{jsonName .}}`, data: "NewsCategory", want: "news_category news-category newsCategory newsCategory"},
+		{tmpl: `{{plural .}} {{singular (plural .)}}`, data: "category", want: "categories category"},
+		{tmpl: `{{isArray .}} {{isPointer .}} {{isTime .}} {{isNumber .}} {{isString .}} {{isBool .}}`, data: "*time.Time", want: "false true true false false false"},
+		{tmpl: `{{isNumber .}} {{isArray .}} {{isMap .}}`, data: "*int64", want: "true false false"},
+		{tmpl: `{{comment .}}`, data: " News of portal\n  shown on main page \n", want: "// News of portal\n// shown on main page"},
+		{tmpl: `{{join (split . ",") ";"}} {{replace . "," ""}} {{trimPrefix . "a,"}} {{hasSuffix . "c"}}`, data: "a,b,c", want: "a;b;c abc b,c true"},
+	}
+	for _, tt := range tests {
+		var buffer bytes.Buffer
+		if err := RenderText(&buffer, tt.tmpl, tt.data); err != nil {
+			t.Errorf("RenderText(%s) error = %v", tt.tmpl, err)
+			continue
+		}
+		if got := buffer.String(); got != tt.want {
+			t.Errorf("RenderText(%s) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := RenderText(&buffer, `{{dict "Name"}}`, nil); err == nil {
+		t.Errorf("dict should fail for odd number of arguments")
+	}
+
+	funcs := CloneTemplateFunctions()
+	funcs["custom"] = raw
+	if _, ok := TemplateFunctions["custom"]; ok || len(funcs) != len(TemplateFunctions)+1 {
+		t.Errorf("CloneTemplateFunctions() should return copy")
+	}
+}
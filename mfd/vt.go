@@ -18,6 +18,9 @@ const (
 	TypeHTMLSelect   = "HTML_SELECT"
 	TypeHTMLFile     = "HTML_FILE"
 	TypeHTMLImage    = "HTML_IMAGE"
+
+	TypeHTMLAutocomplete = "HTML_AUTOCOMPLETE"
+	TypeHTMLChildren     = "HTML_CHILDREN"
 )
 
 const (
@@ -58,6 +61,19 @@ func (p *Project) AddVTEntity(namespace string, entity *VTEntity) *VTEntity {
 	return ns.AddVTEntity(entity)
 }
 
+// HasAudit returns true if any of vt entities writes audit log
+func (p *Project) HasAudit() bool {
+	for _, ns := range p.VTNamespaces {
+		for _, entity := range ns.Entities {
+			if entity.HasAudit() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // VTNamespaceNames returns every vt namespaces
 func (p *Project) VTNamespaceNames() []string {
 	res := make([]string, len(p.VTNamespaces))
@@ -70,10 +86,11 @@ func (p *Project) VTNamespaceNames() []string {
 
 // VTNamespace is xml element
 type VTNamespace struct {
-	XMLName xml.Name `xml:"VTNamespace" json:"-"`
-	XMLxsi  string   `xml:"xmlns:xsi,attr" json:"-"`
-	XMLxsd  string   `xml:"xmlns:xsd,attr" json:"-"`
-	Name    string
+	XMLName   xml.Name `xml:"VTNamespace" json:"-"`
+	XMLxsi    string   `xml:"xmlns:xsi,attr" json:"-"`
+	XMLxsd    string   `xml:"xmlns:xsd,attr" json:"-"`
+	XMLSchema string   `xml:"xsi:noNamespaceSchemaLocation,attr,omitempty" json:"-"`
+	Name      string
 
 	Entities []*VTEntity `xml:"VTEntities>Entity" json:"vtEntities"`
 }
@@ -147,10 +164,41 @@ type VTEntity struct {
 
 	Mode string `xml:"Mode,attr" json:"mode"`
 
+	// generate export to and import from csv/xlsx files
+	ImportExport bool `xml:"ImportExport,attr,omitempty" json:"importExport"`
+
+	// write changes made by vt services to audit log
+	Audit bool `xml:"Audit,attr,omitempty" json:"audit"`
+
+	// comma separated roles required to access entity in vt services and interface, empty for any user
+	Roles string `xml:"Roles,attr,omitempty" json:"roles"`
+
 	// corresponding entity
 	Entity *Entity `xml:"-" json:"-"`
 }
 
+// HasImport returns true if import from file should be generated
+func (e *VTEntity) HasImport() bool {
+	return e.ImportExport && e.Mode == ModeFull
+}
+
+// HasAudit returns true if changes made by vt service should be written to audit log
+func (e *VTEntity) HasAudit() bool {
+	return e.Audit && e.Mode == ModeFull
+}
+
+// RoleNames returns roles required to access entity
+func (e VTEntity) RoleNames() []string {
+	var roles []string
+	for _, role := range strings.Split(e.Roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
 // Attribute gets mfd.VTAttribute by its field name
 func (e *VTEntity) Attribute(name string) *VTAttribute {
 	for _, a := range e.Attributes {
@@ -225,8 +273,29 @@ type TmplAttribute struct {
 	Form   string `xml:"Form,attr" json:"form"`               // show in object editor
 	Search string `xml:"Search,attr" json:"search"`           // input type in search
 
+	Children string `xml:"Children,attr,omitempty" json:"children"` // has-many relation edited in form, e.g. City.RegionID
+
 	// corresponding vt attribute
 	VTAttribute *VTAttribute `xml:"-" json:"-"`
+
+	// corresponding child entity and its fk attribute
+	ChildEntity    *Entity    `xml:"-" json:"-"`
+	ChildAttribute *Attribute `xml:"-" json:"-"`
+}
+
+// ChildRelation returns child entity and its fk attribute names
+func (a *TmplAttribute) ChildRelation() (entity, attribute string) {
+	parts := strings.SplitN(a.Children, ".", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
+// IsChildren returns true if child entities should be edited inline in form
+func (a *TmplAttribute) IsChildren() bool {
+	return a.Children != "" && a.Form == TypeHTMLChildren
 }
 
 // Merge fills attribute (from db) values from old (in file) attribute
@@ -265,6 +334,17 @@ func (a VTAttributes) updateAttr(entity *Entity) {
 
 type TmplAttributes []*TmplAttribute
 
+// ByName gets mfd.TmplAttribute by its name
+func (a TmplAttributes) ByName(name string) *TmplAttribute {
+	for _, tmpl := range a {
+		if tmpl.Name == name {
+			return tmpl
+		}
+	}
+
+	return nil
+}
+
 // Merge adds new attribute, update if exists
 func (a TmplAttributes) Merge(tmpl *TmplAttribute) (TmplAttributes, *TmplAttribute) {
 	for i, existing := range a {
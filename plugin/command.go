@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	mfdFlag    = "mfd"
+	outputFlag = "output"
+	nsFlag     = "namespaces"
+	paramFlag  = "param"
+)
+
+// Options stores plugin command options
+type Options struct {
+	// Name of plugin, executable mfd-gen-<name> is run
+	Name string
+
+	// Args are passed to plugin executable
+	Args []string
+
+	// MFDPath stores path for mfd project
+	MFDPath string
+
+	// Output dir path
+	Output string
+
+	// Namespaces to generate
+	Namespaces []string
+
+	// Params are passed to plugin in request
+	Params map[string]string
+}
+
+// Command runs plugins
+type Command struct {
+	options Options
+}
+
+// New creates plugin command
+func New() *Command {
+	return &Command{}
+}
+
+// CreateCommand creates plugin command
+func CreateCommand() *cobra.Command {
+	c := New()
+
+	command := &cobra.Command{
+		Use:   "plugin [name] [-- args]",
+		Short: "Run mfd-gen-<name> plugin from PATH with loaded project, list plugins if name is not set",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if len(args) == 0 {
+				for _, plugin := range Discover() {
+					//nolint:forbidigo
+					fmt.Printf("%s\t%s\n", plugin.Name, plugin.Path)
+				}
+				return
+			}
+
+			if err := c.ReadFlags(command, args); err != nil {
+				log.Printf("read flags error: %s", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer cancel()
+
+			files, err := NewGenerator(c.options).Generate(ctx)
+			for _, file := range files {
+				log.Printf("written %s", file)
+			}
+			if err != nil {
+				log.Printf("plugin error: %s", err)
+				os.Exit(1)
+			}
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+	}
+
+	c.AddFlags(command)
+
+	return command
+}
+
+// AddFlags adds flags to command
+func (c *Command) AddFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringP(mfdFlag, "m", "", "mfd file path")
+	flags.StringP(outputFlag, "o", "", "output dir path")
+	flags.StringSliceP(nsFlag, "n", []string{}, "namespaces to generate. separate by comma\n")
+	flags.StringToString(paramFlag, map[string]string{}, "plugin parameters, e.g. --param topic=events,version=2")
+}
+
+// ReadFlags reads flags from command
+func (c *Command) ReadFlags(command *cobra.Command, args []string) error {
+	var err error
+
+	flags := command.Flags()
+
+	c.options.Name, c.options.Args = args[0], args[1:]
+
+	if c.options.MFDPath, err = flags.GetString(mfdFlag); err != nil {
+		return err
+	}
+	if c.options.MFDPath == "" {
+		return fmt.Errorf("required flag \"%s\" not set", mfdFlag)
+	}
+
+	if c.options.Output, err = flags.GetString(outputFlag); err != nil {
+		return err
+	}
+	if c.options.Output == "" {
+		return fmt.Errorf("required flag \"%s\" not set", outputFlag)
+	}
+
+	if c.options.Namespaces, err = flags.GetStringSlice(nsFlag); err != nil {
+		return err
+	}
+
+	if c.options.Params, err = flags.GetStringToString(paramFlag); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+// Prefix is a prefix of plugin executables, plugin "kafka" is run from mfd-gen-kafka executable
+const Prefix = "mfd-gen-"
+
+// Plugin is an executable found on PATH
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Request is sent to plugin stdin as json
+type Request struct {
+	// Version of mfd-generator
+	Version string `json:"version"`
+
+	// MFDPath stores path for mfd project
+	MFDPath string `json:"mfdPath"`
+
+	// Output dir, files returned by plugin are written relative to it
+	Output string `json:"output"`
+
+	// Generate is a list of namespaces to generate, all namespaces if empty
+	Generate []string `json:"generate,omitempty"`
+
+	// Params are plugin parameters from command line
+	Params map[string]string `json:"params,omitempty"`
+
+	Project            *mfd.Project               `json:"project"`
+	Namespaces         []*mfd.Namespace           `json:"namespaces"`
+	VTNamespaces       []*mfd.VTNamespace         `json:"vtNamespaces"`
+	ImportedNamespaces []*mfd.Namespace           `json:"importedNamespaces,omitempty"`
+	Translations       map[string]mfd.Translation `json:"translations,omitempty"`
+}
+
+// NewRequest creates request with linked project and its translations
+func NewRequest(project *mfd.Project, translations map[string]mfd.Translation, options Options) Request {
+	return Request{
+		Version:            mfd.Version,
+		MFDPath:            options.MFDPath,
+		Output:             options.Output,
+		Generate:           options.Namespaces,
+		Params:             options.Params,
+		Project:            project,
+		Namespaces:         project.Namespaces,
+		VTNamespaces:       project.VTNamespaces,
+		ImportedNamespaces: project.ImportedNamespaces,
+		Translations:       translations,
+	}
+}
+
+// Response is read from plugin stdout as json
+type Response struct {
+	Files []File `json:"files"`
+	Error string `json:"error,omitempty"`
+}
+
+// File is a file generated by plugin
+type File struct {
+	// Name is a path relative to output dir
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// Discover finds plugins on PATH, the first executable with the same name wins
+func Discover() []Plugin {
+	found := map[string]struct{}{}
+	var plugins []Plugin
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok {
+				continue
+			}
+			if _, ok := found[name]; ok {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if _, err := exec.LookPath(path); err != nil {
+				continue
+			}
+
+			found[name] = struct{}{}
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins
+}
+
+// Find finds plugin on PATH by its name, names with path separators are rejected
+func Find(name string) (Plugin, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return Plugin{}, fmt.Errorf("invalid plugin name %q", name)
+	}
+
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return Plugin{}, fmt.Errorf("plugin %s not found, err=%w", name, err)
+	}
+
+	return Plugin{Name: name, Path: path}, nil
+}
+
+// pluginName returns plugin name of executable file
+func pluginName(filename string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		filename = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+
+	name := strings.TrimPrefix(filename, Prefix)
+	return name, name != filename && name != ""
+}
+
+// Run sends request to plugin and reads its response, plugin stderr is passed to stderr
+func (p Plugin) Run(ctx context.Context, request Request, args []string, stderr io.Writer) (*Response, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request, err=%w", err)
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run plugin %s, err=%w", p.Name, err)
+	}
+
+	response := &Response{}
+	if err := json.Unmarshal(output.Bytes(), response); err != nil {
+		return nil, fmt.Errorf("read plugin %s response, err=%w", p.Name, err)
+	}
+
+	if response.Error != "" {
+		return nil, fmt.Errorf("plugin %s error: %s", p.Name, response.Error)
+	}
+
+	return response, nil
+}
+
+// Save writes files of response to output dir, go files are merged with existing files and formatted
+func Save(files *mfd.Files, output string, response *Response) error {
+	for _, file := range response.Files {
+		if !filepath.IsLocal(file.Name) {
+			return fmt.Errorf("file %s is outside of output dir", file.Name)
+		}
+	}
+
+	for _, file := range response.Files {
+		filename := filepath.Join(output, file.Name)
+
+		var err error
+		if filepath.Ext(filename) == ".go" {
+			_, err = files.SaveGo([]byte(file.Content), filename)
+		} else {
+			_, err = files.Save([]byte(file.Content), filename)
+		}
+		if err != nil {
+			return fmt.Errorf("save file %s, err=%w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// Runner runs plugin for mfd project without cobra command
+type Runner struct {
+	options Options
+	output  mfd.Output
+	stderr  io.Writer
+}
+
+// NewGenerator creates plugin runner for usage as a library
+func NewGenerator(options Options) *Runner {
+	return &Runner{options: options, stderr: os.Stderr}
+}
+
+// WithOutput sets output for generated files, files are written to disk by default
+func (r *Runner) WithOutput(output mfd.Output) *Runner {
+	r.output = output
+	return r
+}
+
+// WithStderr sets writer for plugin stderr, os.Stderr is used by default
+func (r *Runner) WithStderr(stderr io.Writer) *Runner {
+	r.stderr = stderr
+	return r
+}
+
+// Generate loads project, runs plugin and returns written files
+func (r *Runner) Generate(ctx context.Context) ([]string, error) {
+	if r.options.Name == "" || r.options.MFDPath == "" || r.options.Output == "" {
+		return nil, errors.New("plugin name, mfd file and output dir are required")
+	}
+
+	project, err := mfd.LoadProject(r.options.MFDPath, false, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := mfd.LoadTranslations(r.options.MFDPath, project.Languages)
+	if err != nil {
+		return nil, fmt.Errorf("read translations, err=%w", err)
+	}
+
+	return r.Run(ctx, NewRequest(project, translations, r.options))
+}
+
+// Run runs plugin with request and returns written files
+func (r *Runner) Run(ctx context.Context, request Request) ([]string, error) {
+	plugin, err := Find(r.options.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := plugin.Run(ctx, request, r.options.Args, r.stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	files := mfd.NewFiles(r.output)
+	err = Save(files, request.Output, response)
+
+	return files.Written(), err
+}
@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+const testMFDPath = "../generators/testdata/expected/newsportal.mfd"
+
+// writePlugin creates plugin script which saves request and prints response
+func writePlugin(t *testing.T, cat, dir, name, response string) {
+	t.Helper()
+
+	script := "#!/bin/sh\n" + cat + " > \"" + filepath.Join(dir, name+".json") + "\"\n" + cat + " <<'EOF'\n" + response + "\nEOF\n"
+	if err := os.WriteFile(filepath.Join(dir, Prefix+name), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunner_Generate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts are not supported on windows")
+	}
+
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat is not found")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+
+	writePlugin(t, cat, dir, "events", `{"files":[{"name":"events/portal.go","content":"package events\ntype NewsCreated struct{ID int}\n"},{"name":"events.json","content":"{}"}]}`)
+	writePlugin(t, cat, dir, "broken", `{"files":[{"name":"../main.go","content":"package main"}]}`)
+	writePlugin(t, cat, dir, "failed", `{"error":"topic is not set"}`)
+	if err := os.WriteFile(filepath.Join(dir, Prefix+"skipped"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins := Discover()
+	if len(plugins) != 3 || plugins[0].Name != "broken" || plugins[1].Name != "events" || plugins[2].Name != "failed" {
+		t.Errorf("Discover() = %v", plugins)
+	}
+
+	out := mfd.NewMemoryOutput()
+	files, err := NewGenerator(Options{
+		Name:       "events",
+		MFDPath:    testMFDPath,
+		Output:     "pkg",
+		Namespaces: []string{"portal"},
+		Params:     map[string]string{"topic": "news"},
+	}).WithOutput(out).Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("Generate() files = %v", files)
+	}
+
+	content, err := out.ReadFile(filepath.Join("pkg", "events", "portal.go"))
+	if want := "package events\n\ntype NewsCreated struct{ ID int }\n"; err != nil || string(content) != want {
+		t.Errorf("generated go file = %q, want %q", content, want)
+	}
+
+	var request struct {
+		Output     string         `json:"output"`
+		Generate   []string       `json:"generate"`
+		Params     map[string]any `json:"params"`
+		Project    map[string]any `json:"project"`
+		Namespaces []struct {
+			Name     string
+			Entities []mfd.Entity
+		} `json:"namespaces"`
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "events.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &request); err != nil {
+		t.Fatalf("unmarshal request error = %v", err)
+	}
+	if request.Output != "pkg" || len(request.Generate) != 1 || request.Params["topic"] != "news" || request.Project["name"] == nil {
+		t.Errorf("request = %+v", request)
+	}
+	if len(request.Namespaces) == 0 || len(request.Namespaces[0].Entities) == 0 {
+		t.Errorf("request namespaces = %+v", request.Namespaces)
+	}
+
+	for _, name := range []string{"broken", "failed", "unknown"} {
+		_, err := NewGenerator(Options{Name: name, MFDPath: testMFDPath, Output: "pkg"}).WithOutput(out).Generate(context.Background())
+		if err == nil {
+			t.Errorf("Generate() of %s plugin should fail", name)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	for _, name := range []string{"", "..", "../events", "events/..", "sub/events", `sub\events`} {
+		if _, err := Find(name); err == nil {
+			t.Errorf("Find(%q) should fail", name)
+		}
+	}
+}
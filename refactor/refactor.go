@@ -0,0 +1,202 @@
+package refactor
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	mfdFlag       = "mfd"
+	entityFlag    = "entity"
+	attributeFlag = "attribute"
+	nameFlag      = "name"
+	namespaceFlag = "namespace"
+)
+
+// Options stores refactor command options
+type Options struct {
+	// MFDPath stores path for mfd project
+	MFDPath string
+
+	// Entity is a name of refactored entity
+	Entity string
+
+	// Attribute is a name of renamed attribute
+	Attribute string
+
+	// Name is a new name of entity or attribute
+	Name string
+
+	// Namespace is a target namespace of moved entity
+	Namespace string
+}
+
+// Refactoring changes project and its translations
+type Refactoring func(project *mfd.Project, translations []*mfd.Translation) error
+
+// CreateCommand creates refactor command with rename-entity, rename-attribute and move-entity subcommands
+func CreateCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "refactor",
+		Short: "Rename or move entities and attributes updating all references in project",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := command.Help(); err != nil {
+				log.Printf("help error: %s", err)
+			}
+		},
+	}
+
+	command.AddCommand(
+		createCommand("rename-entity", "Rename entity", []string{entityFlag, nameFlag}, func(o Options) Refactoring {
+			return func(project *mfd.Project, translations []*mfd.Translation) error {
+				return project.RenameEntity(o.Entity, o.Name, translations...)
+			}
+		}),
+		createCommand("rename-attribute", "Rename attribute of entity", []string{entityFlag, attributeFlag, nameFlag}, func(o Options) Refactoring {
+			return func(project *mfd.Project, translations []*mfd.Translation) error {
+				return project.RenameAttribute(o.Entity, o.Attribute, o.Name, translations...)
+			}
+		}),
+		createCommand("move-entity", "Move entity to another namespace", []string{entityFlag, namespaceFlag}, func(o Options) Refactoring {
+			return func(project *mfd.Project, translations []*mfd.Translation) error {
+				return project.MoveEntity(o.Entity, o.Namespace, translations...)
+			}
+		}),
+	)
+
+	return command
+}
+
+// createCommand creates refactor subcommand with required flags
+func createCommand(use, short string, required []string, refactoring func(Options) Refactoring) *cobra.Command {
+	var options Options
+
+	command := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := ReadFlags(command, &options, required); err != nil {
+				log.Printf("read flags error: %s", err)
+				os.Exit(1)
+			}
+
+			if err := Refactor(options.MFDPath, refactoring(options)); err != nil {
+				log.Printf("refactor error: %s", err)
+				os.Exit(1)
+			}
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+	}
+
+	AddFlags(command, required)
+
+	return command
+}
+
+// AddFlags adds mfd flag and flags used by refactoring to command
+func AddFlags(command *cobra.Command, used []string) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringP(mfdFlag, "m", "", "mfd file path")
+	for _, flag := range used {
+		switch flag {
+		case entityFlag:
+			flags.StringP(entityFlag, "e", "", "entity name")
+		case attributeFlag:
+			flags.StringP(attributeFlag, "a", "", "attribute name")
+		case nameFlag:
+			flags.StringP(nameFlag, "n", "", "new name")
+		case namespaceFlag:
+			flags.StringP(namespaceFlag, "p", "", "target namespace, created if not exists")
+		}
+	}
+}
+
+// ReadFlags reads flags from command, all used flags are required
+func ReadFlags(command *cobra.Command, options *Options, used []string) error {
+	flags := command.Flags()
+
+	values := map[string]*string{
+		mfdFlag:       &options.MFDPath,
+		entityFlag:    &options.Entity,
+		attributeFlag: &options.Attribute,
+		nameFlag:      &options.Name,
+		namespaceFlag: &options.Namespace,
+	}
+
+	for _, flag := range append([]string{mfdFlag}, used...) {
+		value, err := flags.GetString(flag)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			return fmt.Errorf("required flag \"%s\" not set", flag)
+		}
+		*values[flag] = value
+	}
+
+	return nil
+}
+
+// Refactor loads project with translations, applies refactoring and saves all project files.
+// Project should be consistent before refactoring and stay consistent after it.
+func Refactor(mfdPath string, refactoring Refactoring) error {
+	project, err := mfd.LoadProject(mfdPath, false, 0)
+	if err != nil {
+		return fmt.Errorf("load project, err=%w", err)
+	}
+
+	loaded, err := mfd.LoadTranslations(mfdPath, project.Languages)
+	if err != nil {
+		return fmt.Errorf("load translations, err=%w", err)
+	}
+
+	translations := make([]*mfd.Translation, 0, len(project.Languages))
+	for _, lang := range project.Languages {
+		translation := loaded[lang]
+		translations = append(translations, &translation)
+	}
+
+	if err := refactoring(project, translations); err != nil {
+		return err
+	}
+
+	return Save(mfdPath, project, translations)
+}
+
+// Save saves project, namespaces, vt namespaces and translations
+func Save(mfdPath string, project *mfd.Project, translations []*mfd.Translation) error {
+	if err := mfd.SaveMFD(mfdPath, project); err != nil {
+		return err
+	}
+
+	if err := mfd.SaveProjectXML(mfdPath, project); err != nil {
+		return err
+	}
+
+	if err := mfd.SaveProjectVT(mfdPath, project); err != nil {
+		return err
+	}
+
+	for _, translation := range translations {
+		// translation files are created by xml-lang generator
+		if len(translation.Namespaces) == 0 {
+			continue
+		}
+		if err := mfd.SaveTranslation(*translation, mfdPath, translation.Language); err != nil {
+			return fmt.Errorf("save translation %s, err=%w", translation.Language, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package refactor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+const testdataPath = "../generators/testdata/expected"
+
+// copyProject copies testdata project with its namespaces and translations to temp dir
+func copyProject(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, pattern := range []string{"*.mfd", "*.xml"} {
+		files, err := filepath.Glob(filepath.Join(testdataPath, pattern))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, filepath.Base(file)), content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return filepath.Join(dir, "newsportal.mfd")
+}
+
+func TestRefactor(t *testing.T) {
+	mfdPath := copyProject(t)
+
+	err := Refactor(mfdPath, func(project *mfd.Project, translations []*mfd.Translation) error {
+		return project.RenameEntity("News", "Article", translations...)
+	})
+	if err != nil {
+		t.Fatalf("Refactor() error = %v", err)
+	}
+
+	project, err := mfd.LoadProject(mfdPath, false, 0)
+	if err != nil {
+		t.Fatalf("load refactored project: %v", err)
+	}
+	if project.Entity("News") != nil || project.Entity("Article") == nil || project.VTEntity("Article") == nil {
+		t.Errorf("entity is not renamed in saved project")
+	}
+	if tmpl := project.VTEntity("Category").TmplAttributes.ByName("Articles"); tmpl == nil || tmpl.Children != "Article.CategoryID" {
+		t.Errorf("children template = %+v", tmpl)
+	}
+
+	translations, err := mfd.LoadTranslations(mfdPath, project.Languages)
+	if err != nil {
+		t.Fatalf("load refactored translations: %v", err)
+	}
+	translation := translations[mfd.EnLang]
+	if te := translation.Entity("portal", "Article"); te == nil || te.Key != "article" || translation.Entity("portal", "News") != nil {
+		t.Errorf("translation entity = %+v", te)
+	}
+
+	// failed refactoring doesn't change files
+	xmlPath := filepath.Join(filepath.Dir(mfdPath), "portal.xml")
+	before, err := os.ReadFile(xmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Refactor(mfdPath, func(project *mfd.Project, translations []*mfd.Translation) error {
+		return project.RenameEntity("Article", "Tag", translations...)
+	})
+	if err == nil {
+		t.Errorf("Refactor() to existing entity should fail")
+	}
+	if after, err := os.ReadFile(xmlPath); err != nil || !bytes.Equal(before, after) {
+		t.Errorf("portal.xml is changed by failed refactoring, err=%v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	mfdFlag    = "mfd"
+	outputFlag = "output"
+)
+
+// Options stores schema command options
+type Options struct {
+	// Output is a directory for schema files
+	Output string
+}
+
+// Generator writes xsd and json schema of mfd project files
+type Generator struct {
+	options Options
+}
+
+// New creates schema generator
+func New() *Generator {
+	return &Generator{}
+}
+
+// CreateCommand creates schema command
+func CreateCommand() *cobra.Command {
+	generator := New()
+
+	command := &cobra.Command{
+		Use:   "schema",
+		Short: "Create xsd and json schema for mfd project files",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := generator.ReadFlags(command); err != nil {
+				log.Printf("read flags error: %s", err)
+				os.Exit(1)
+			}
+
+			files, err := Write(generator.options.Output)
+			if err != nil {
+				log.Printf("schema error: %s", err)
+				os.Exit(1)
+			}
+
+			for _, file := range files {
+				log.Printf("written %s", file)
+			}
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+	}
+
+	generator.AddFlags(command)
+
+	return command
+}
+
+// AddFlags adds flags to command
+func (g *Generator) AddFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringP(mfdFlag, "m", "", "mfd file path, schema is written next to it")
+	flags.StringP(outputFlag, "o", "", "output dir path, used if mfd file is not set")
+}
+
+// ReadFlags reads flags from command
+func (g *Generator) ReadFlags(command *cobra.Command) error {
+	flags := command.Flags()
+
+	mfdPath, err := flags.GetString(mfdFlag)
+	if err != nil {
+		return err
+	}
+
+	if g.options.Output, err = flags.GetString(outputFlag); err != nil {
+		return err
+	}
+
+	if mfdPath != "" {
+		g.options.Output = filepath.Dir(mfdPath)
+	}
+
+	if g.options.Output == "" {
+		return fmt.Errorf("one of flags \"%s\" or \"%s\" should be set", mfdFlag, outputFlag)
+	}
+
+	return nil
+}
+
+// Write writes xsd and json schema to output dir, returns list of written files
+func Write(output string) ([]string, error) {
+	jsonSchema, err := mfd.JSONSchema()
+	if err != nil {
+		return nil, fmt.Errorf("generate json schema, err=%w", err)
+	}
+
+	files := []string{filepath.Join(output, mfd.SchemaXSD), filepath.Join(output, mfd.SchemaJSON)}
+	for i, content := range [][]byte{mfd.XSD(), append(jsonSchema, '\n')} {
+		if _, err := mfd.Save(content, files[i]); err != nil {
+			return nil, fmt.Errorf("write %s, err=%w", files[i], err)
+		}
+	}
+
+	return files, nil
+}
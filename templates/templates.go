@@ -0,0 +1,175 @@
+package templates
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/generators/dbtest"
+	"github.com/vmkteam/mfd-generator/generators/model"
+	"github.com/vmkteam/mfd-generator/generators/repo"
+	"github.com/vmkteam/mfd-generator/generators/vt"
+	vttmpl "github.com/vmkteam/mfd-generator/generators/vt-template"
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputFlag     = "output"
+	generatorsFlag = "generators"
+	forceFlag      = "force"
+)
+
+// Generators are built-in templates by generator command name
+var Generators = map[string]func() []mfd.BuiltinTemplate{
+	"model":    model.Templates,
+	"repo":     repo.Templates,
+	"dbtest":   dbtest.Templates,
+	"vt":       vt.Templates,
+	"template": vttmpl.Templates,
+}
+
+// Options stores templates export options
+type Options struct {
+	// Output is a templates dir, templates of generator are written to <output>/<generator>
+	Output string
+
+	// Generators to export, all generators if empty
+	Generators []string
+
+	// Force overwrites existing templates
+	Force bool
+}
+
+// Exporter exports built-in templates to templates dir
+type Exporter struct {
+	options Options
+}
+
+// New creates exporter
+func New() *Exporter {
+	return &Exporter{}
+}
+
+// CreateCommand creates templates command with export subcommand
+func CreateCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "templates",
+		Short: "Manage custom templates used with --templates-dir flag of generators",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := command.Help(); err != nil {
+				log.Printf("help error: %s", err)
+			}
+		},
+	}
+
+	exporter := New()
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Export built-in templates to templates dir as a starting point for customization",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := exporter.ReadFlags(command); err != nil {
+				log.Printf("read flags error: %s", err)
+				os.Exit(1)
+			}
+
+			files, err := Export(exporter.options)
+			for _, file := range files {
+				log.Printf("written %s", file)
+			}
+			if err != nil {
+				log.Printf("export error: %s", err)
+				os.Exit(1)
+			}
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+	}
+	exporter.AddFlags(export)
+
+	command.AddCommand(export)
+
+	return command
+}
+
+// AddFlags adds flags to command
+func (e *Exporter) AddFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringP(outputFlag, "o", "", "templates dir path")
+	flags.StringSliceP(generatorsFlag, "g", []string{}, "generators to export: "+strings.Join(generatorNames(), ", ")+". separate by comma, all if not set")
+	flags.BoolP(forceFlag, "f", false, "overwrite existing templates")
+}
+
+// ReadFlags reads flags from command
+func (e *Exporter) ReadFlags(command *cobra.Command) error {
+	var err error
+
+	flags := command.Flags()
+
+	if e.options.Output, err = flags.GetString(outputFlag); err != nil {
+		return err
+	}
+	if e.options.Output == "" {
+		return fmt.Errorf("required flag \"%s\" not set", outputFlag)
+	}
+
+	if e.options.Generators, err = flags.GetStringSlice(generatorsFlag); err != nil {
+		return err
+	}
+
+	if e.options.Force, err = flags.GetBool(forceFlag); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Export writes built-in templates of generators to templates dir, existing templates are kept unless force is set.
+// Returns list of written files.
+func Export(options Options) ([]string, error) {
+	generators := options.Generators
+	if len(generators) == 0 {
+		generators = generatorNames()
+	}
+
+	files := mfd.NewFiles(nil)
+	for _, generator := range generators {
+		templates, ok := Generators[generator]
+		if !ok {
+			return files.Written(), fmt.Errorf("generator %s has no templates, use one of: %s", generator, strings.Join(generatorNames(), ", "))
+		}
+
+		for _, tmpl := range templates() {
+			filename := filepath.Join(options.Output, generator, tmpl.Name+mfd.TemplateExt)
+			if !options.Force && files.Exists(filename) {
+				continue
+			}
+
+			if _, err := files.Save([]byte(tmpl.Source), filename); err != nil {
+				return files.Written(), fmt.Errorf("save template %s, err=%w", filename, err)
+			}
+		}
+	}
+
+	return files.Written(), nil
+}
+
+// generatorNames returns sorted names of generators with templates
+func generatorNames() []string {
+	names := make([]string, 0, len(Generators))
+	for name := range Generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
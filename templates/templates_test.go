@@ -0,0 +1,54 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := Export(Options{Output: dir})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var count int
+	for _, templates := range Generators {
+		count += len(templates())
+	}
+	if len(files) != count {
+		t.Errorf("Export() written %d files, want %d", len(files), count)
+	}
+
+	// exported templates are loaded from templates dir instead of built-in
+	filename := filepath.Join(dir, "repo", "repo"+mfd.TemplateExt)
+	if err := os.WriteFile(filename, []byte("custom"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := mfd.LoadTemplates(dir, "repo")
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+	if tmpl, err := templates.Template("repo", "", ""); err != nil || tmpl != "custom" {
+		t.Errorf("Template() = %q, %v", tmpl, err)
+	}
+
+	// existing templates are kept without force
+	files, err = Export(Options{Output: dir, Generators: []string{"repo"}})
+	if err != nil || len(files) != 0 {
+		t.Errorf("Export() = %v, %v, want no files", files, err)
+	}
+
+	files, err = Export(Options{Output: dir, Generators: []string{"repo"}, Force: true})
+	if err != nil || len(files) != 1 {
+		t.Errorf("Export() with force = %v, %v", files, err)
+	}
+
+	if _, err := Export(Options{Output: dir, Generators: []string{"xml"}}); err == nil {
+		t.Errorf("Export() should fail for generator without templates")
+	}
+}
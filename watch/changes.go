@@ -0,0 +1,208 @@
+package watch
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+// EntityChange is a changed entity of project input, empty entity means change of whole namespace
+type EntityChange struct {
+	Input     Input
+	Namespace string
+	Entity    string
+}
+
+// Changes stores changes of project found by watcher
+type Changes struct {
+	// Full is set if project file was changed, all generators are run for whole project
+	Full bool
+
+	// Templates are changed custom templates
+	Templates []string
+
+	// Entities are changed entities
+	Entities []EntityChange
+}
+
+// IsEmpty checks if nothing was changed
+func (c Changes) IsEmpty() bool {
+	return !c.Full && len(c.Templates) == 0 && len(c.Entities) == 0
+}
+
+// State stores fingerprints of loaded project to find changed entities
+type State struct {
+	project  string
+	entities map[Input]map[string]map[string]string
+}
+
+// NewState creates fingerprints of project, its vt namespaces and translations
+func NewState(project *mfd.Project, translations map[string]mfd.Translation) State {
+	state := State{
+		project: fingerprint(project),
+		entities: map[Input]map[string]map[string]string{
+			InputNamespace: {},
+			InputVT:        {},
+			InputLang:      {},
+		},
+	}
+
+	for _, ns := range project.Namespaces {
+		state.entities[InputNamespace][ns.Name] = map[string]string{}
+		for _, entity := range ns.Entities {
+			state.entities[InputNamespace][ns.Name][entity.Name] = fingerprint(entity)
+		}
+	}
+
+	for _, ns := range project.VTNamespaces {
+		state.entities[InputVT][ns.Name] = map[string]string{}
+		for _, entity := range ns.Entities {
+			state.entities[InputVT][ns.Name][entity.Name] = fingerprint(entity)
+		}
+	}
+
+	languages := make([]string, 0, len(translations))
+	for lang := range translations {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	for _, lang := range languages {
+		for _, ns := range translations[lang].Namespaces {
+			if state.entities[InputLang][ns.Name] == nil {
+				state.entities[InputLang][ns.Name] = map[string]string{}
+			}
+			for _, entity := range ns.Entities {
+				state.entities[InputLang][ns.Name][entity.Name] += fingerprint(entity)
+			}
+		}
+	}
+
+	return state
+}
+
+// Changes returns changed entities of next state, entities and namespaces are sorted by name
+func (s State) Changes(next State) Changes {
+	if s.project != next.project {
+		return Changes{Full: true}
+	}
+
+	var changes Changes
+	for _, input := range []Input{InputNamespace, InputVT, InputLang} {
+		prev, cur := s.entities[input], next.entities[input]
+		for _, ns := range sortedKeys(cur) {
+			for _, entity := range sortedKeys(cur[ns]) {
+				if prev[ns][entity] != cur[ns][entity] {
+					changes.Entities = append(changes.Entities, EntityChange{Input: input, Namespace: ns, Entity: entity})
+				}
+			}
+		}
+
+		// deleted entities are handled by regeneration of whole namespace
+		for _, ns := range sortedKeys(prev) {
+			for entity := range prev[ns] {
+				if _, ok := cur[ns][entity]; !ok && cur[ns] != nil {
+					changes.Entities = append(changes.Entities, EntityChange{Input: input, Namespace: ns})
+					break
+				}
+			}
+		}
+	}
+
+	return changes
+}
+
+// fingerprint returns xml representation of value, links to other elements are not marshaled
+func fingerprint(v interface{}) string {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// fileState is a modification time and size of file, zero for not existing file
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Files stores states of watched files
+type Files map[string]fileState
+
+// StatFiles reads states of files
+func StatFiles(filenames []string) Files {
+	files := make(Files, len(filenames))
+	for _, filename := range filenames {
+		var state fileState
+		if info, err := os.Stat(filename); err == nil {
+			state = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		files[filename] = state
+	}
+
+	return files
+}
+
+// StatDir reads states of all files in dir, dependencies and vcs dirs are skipped
+func StatDir(dir string) Files {
+	files := Files{}
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != dir && (name == "node_modules" || name == ".git") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+
+	return files
+}
+
+// Changed returns sorted files which were created or modified in next state
+func (f Files) Changed(next Files) []string {
+	var changed []string
+	for filename, state := range next {
+		if prev, ok := f[filename]; !ok || prev != state {
+			changed = append(changed, filename)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed
+}
+
+// ProjectFiles returns project file with namespace, vt namespace and translation files
+func ProjectFiles(mfdPath string, project *mfd.Project) []string {
+	files := []string{mfdPath}
+	for _, ns := range project.NamespaceNames {
+		files = append(files, mfd.NamespaceFile(mfdPath, ns), mfd.VTNamespaceFile(mfdPath, ns))
+	}
+	for _, lang := range project.Languages {
+		files = append(files, mfd.TranslationFile(mfdPath, lang))
+	}
+
+	return files
+}
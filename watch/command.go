@@ -0,0 +1,289 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"time"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	mfdFlag      = "mfd"
+	runFlag      = "run"
+	intervalFlag = "interval"
+	debounceFlag = "debounce"
+	initialFlag  = "initial"
+
+	// maxPasses limits regeneration caused by project files written by xml generators
+	maxPasses = 4
+)
+
+// Options stores watch command options
+type Options struct {
+	// MFDPath stores path for mfd project
+	MFDPath string
+
+	// Runs are generator command lines, e.g. "model -o pkg/db -p db"
+	Runs []string
+
+	// Interval is a polling interval of watched files
+	Interval time.Duration
+
+	// Debounce is a time without changes before regeneration
+	Debounce time.Duration
+
+	// Initial runs all generators on start
+	Initial bool
+}
+
+// Watcher watches project files and custom templates and reruns affected generators
+type Watcher struct {
+	options Options
+
+	runs    []*Run
+	state   State
+	watched []string
+	files   Files
+}
+
+// New creates watcher
+func New() *Watcher {
+	return &Watcher{}
+}
+
+// CreateCommand creates watch command
+func CreateCommand() *cobra.Command {
+	watcher := New()
+
+	command := &cobra.Command{
+		Use:   "watch",
+		Short: "Watch mfd project and custom templates and regenerate affected code",
+		Long:  "",
+		Run: func(command *cobra.Command, args []string) {
+			if err := watcher.ReadFlags(command); err != nil {
+				log.Printf("read flags error: %s", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer cancel()
+
+			if err := watcher.Watch(ctx); err != nil {
+				log.Printf("watch error: %s", err)
+				os.Exit(1)
+			}
+		},
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+	}
+
+	watcher.AddFlags(command)
+
+	return command
+}
+
+// AddFlags adds flags to command
+func (w *Watcher) AddFlags(command *cobra.Command) {
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringP(mfdFlag, "m", "", "mfd file path")
+	flags.StringArrayP(runFlag, "r", []string{}, "generator command line without mfd flag, e.g. \"vt -o pkg/vt -x example.com/pkg/db\". may be repeated\n")
+	flags.Duration(intervalFlag, 500*time.Millisecond, "polling interval of watched files")
+	flags.Duration(debounceFlag, 300*time.Millisecond, "time without changes before regeneration")
+	flags.Bool(initialFlag, false, "run all generators on start")
+}
+
+// ReadFlags reads flags from command
+func (w *Watcher) ReadFlags(command *cobra.Command) error {
+	var err error
+
+	flags := command.Flags()
+
+	if w.options.MFDPath, err = flags.GetString(mfdFlag); err != nil {
+		return err
+	}
+	if w.options.MFDPath == "" {
+		return fmt.Errorf("required flag \"%s\" not set", mfdFlag)
+	}
+
+	if w.options.Runs, err = flags.GetStringArray(runFlag); err != nil {
+		return err
+	}
+	if len(w.options.Runs) == 0 {
+		return fmt.Errorf("required flag \"%s\" not set", runFlag)
+	}
+
+	if w.options.Interval, err = flags.GetDuration(intervalFlag); err != nil {
+		return err
+	}
+
+	if w.options.Debounce, err = flags.GetDuration(debounceFlag); err != nil {
+		return err
+	}
+
+	if w.options.Initial, err = flags.GetBool(initialFlag); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Watch watches project until context is done
+func (w *Watcher) Watch(ctx context.Context) error {
+	w.runs = make([]*Run, 0, len(w.options.Runs))
+	for _, line := range w.options.Runs {
+		run, err := NewRun(line, w.options.MFDPath)
+		if err != nil {
+			return err
+		}
+		w.runs = append(w.runs, run)
+	}
+
+	state, err := w.load()
+	if err != nil {
+		return err
+	}
+	w.state = state
+
+	if w.options.Initial {
+		w.generate(Changes{Full: true})
+	}
+	w.files = StatFiles(w.watched)
+
+	log.Printf("watching %d files of %s", len(w.files), w.options.MFDPath)
+
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	var changedAt time.Time
+	var templates []string
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		files := StatFiles(w.watched)
+		if changed := w.files.Changed(files); len(changed) > 0 {
+			w.files, changedAt = files, time.Now()
+			templates = append(templates, w.changedTemplates(changed)...)
+			continue
+		}
+
+		// waiting until files are not changed during debounce time
+		if changedAt.IsZero() || time.Since(changedAt) < w.options.Debounce {
+			continue
+		}
+		changedAt = time.Time{}
+
+		w.reload(templates)
+		templates = nil
+
+		// project files written by xml generators are already regenerated
+		w.files = StatFiles(w.watched)
+	}
+}
+
+// reload loads changed project and runs affected generators, project with errors is skipped until next change
+func (w *Watcher) reload(templates []string) {
+	state, err := w.load()
+	if err != nil {
+		log.Printf("load project error: %s", err)
+		return
+	}
+
+	changes := w.state.Changes(state)
+	changes.Templates = templates
+	w.state = state
+
+	if changes.IsEmpty() {
+		return
+	}
+
+	w.generate(changes)
+}
+
+// generate runs affected generators, changes made by xml generators in project files are regenerated by next passes
+func (w *Watcher) generate(changes Changes) {
+	for pass := 0; pass < maxPasses && !changes.IsEmpty(); pass++ {
+		w.run(changes)
+
+		state, err := w.load()
+		if err != nil {
+			log.Printf("load project error: %s", err)
+			return
+		}
+
+		changes = w.state.Changes(state)
+		w.state = state
+	}
+}
+
+// run runs affected generators and prints written files
+func (w *Watcher) run(changes Changes) {
+	for _, run := range w.runs {
+		for _, scope := range run.Scopes(changes) {
+			before := StatDir(run.Output)
+			start := time.Now()
+
+			if err := run.Generate(w.options.MFDPath, scope); err != nil {
+				log.Printf("%s [%s] error: %s", run.Name, scope, err)
+				continue
+			}
+
+			log.Printf("%s [%s] done in %s", run.Name, scope, time.Since(start).Round(time.Millisecond))
+			for _, filename := range before.Changed(StatDir(run.Output)) {
+				log.Printf("  written %s", filename)
+			}
+		}
+	}
+}
+
+// load loads project with translations and creates its state, list of watched files is updated
+func (w *Watcher) load() (State, error) {
+	project, err := mfd.LoadProject(w.options.MFDPath, false, 0)
+	if project != nil {
+		w.watched = ProjectFiles(w.options.MFDPath, project)
+		for _, run := range w.runs {
+			run.Outputs = nil
+			for _, rule := range project.OutputRules(run.Name) {
+				run.Outputs = append(run.Outputs, rule.TemplatePath(w.options.MFDPath))
+			}
+			w.watched = append(w.watched, run.templates()...)
+		}
+	}
+	if err != nil {
+		return State{}, err
+	}
+
+	translations, err := mfd.LoadTranslations(w.options.MFDPath, project.Languages)
+	if err != nil {
+		return State{}, fmt.Errorf("read translations, err=%w", err)
+	}
+
+	return NewState(project, translations), nil
+}
+
+// changedTemplates returns custom templates from changed files
+func (w *Watcher) changedTemplates(changed []string) []string {
+	var templates []string
+	for _, run := range w.runs {
+		for _, template := range run.templates() {
+			if slices.Contains(changed, template) {
+				templates = append(templates, template)
+			}
+		}
+	}
+
+	return templates
+}
@@ -0,0 +1,259 @@
+package watch
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vmkteam/mfd-generator/generators/dbtest"
+	"github.com/vmkteam/mfd-generator/generators/model"
+	"github.com/vmkteam/mfd-generator/generators/repo"
+	"github.com/vmkteam/mfd-generator/generators/vt"
+	vttmpl "github.com/vmkteam/mfd-generator/generators/vt-template"
+	xmllang "github.com/vmkteam/mfd-generator/generators/xml-lang"
+	xmlvt "github.com/vmkteam/mfd-generator/generators/xml-vt"
+	"github.com/vmkteam/mfd-generator/mfd"
+
+	"github.com/dizzyfool/genna/generators/base"
+	"github.com/spf13/cobra"
+)
+
+// Input is a kind of project file generator depends on
+type Input int
+
+const (
+	// InputNamespace is namespace xml with entities
+	InputNamespace Input = 1 << iota
+	// InputVT is vt namespace xml
+	InputVT
+	// InputLang is translation xml
+	InputLang
+)
+
+const (
+	nsFlag       = "namespaces"
+	entitiesFlag = "entities"
+	templateFlag = "-tmpl"
+
+	templatesDirFlag = "templates-dir"
+)
+
+// Generator describes generator which can be run by watcher
+type Generator struct {
+	// New creates generator
+	New func() base.Gen
+
+	// Inputs are project files generator depends on
+	Inputs Input
+
+	// Namespaces and Entities are set if generator supports partial update by --namespaces and --entities flags
+	Namespaces bool
+	Entities   bool
+}
+
+// Generators are generators supported by watcher by command name, xml generator is not supported as it reads database
+var Generators = map[string]Generator{
+	"model":    {New: func() base.Gen { return model.New() }, Inputs: InputNamespace},
+	"repo":     {New: func() base.Gen { return repo.New() }, Inputs: InputNamespace, Namespaces: true},
+	"dbtest":   {New: func() base.Gen { return dbtest.New() }, Inputs: InputNamespace, Namespaces: true, Entities: true},
+	"vt":       {New: func() base.Gen { return vt.New() }, Inputs: InputNamespace | InputVT, Namespaces: true, Entities: true},
+	"template": {New: func() base.Gen { return vttmpl.New() }, Inputs: InputVT | InputLang, Namespaces: true, Entities: true},
+	"xml-vt":   {New: func() base.Gen { return xmlvt.New() }, Inputs: InputNamespace, Namespaces: true, Entities: true},
+	"xml-lang": {New: func() base.Gen { return xmllang.New() }, Inputs: InputVT, Namespaces: true, Entities: true},
+}
+
+// Run is a generator command line run by watcher, e.g. "vt -o pkg/vt -x example.com/pkg/db"
+type Run struct {
+	Name string
+	Args []string
+
+	generator Generator
+
+	// Templates are custom templates of run from --*-tmpl flags and --templates-dir
+	Templates []string
+
+	// Outputs are templates of project output rules of generator, they are updated on project load
+	Outputs []string
+
+	// Output is output dir of generator, project dir for xml generators
+	Output string
+}
+
+// Scope limits run to namespace and its entities, empty scope means full run
+type Scope struct {
+	Namespace string
+	Entities  []string
+}
+
+// String returns scope for console output
+func (s Scope) String() string {
+	if s.Namespace == "" {
+		return "all"
+	}
+	if len(s.Entities) == 0 {
+		return s.Namespace
+	}
+
+	return s.Namespace + ": " + strings.Join(s.Entities, ", ")
+}
+
+// NewRun parses generator command line
+func NewRun(line, mfdPath string) (*Run, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty run")
+	}
+
+	generator, ok := Generators[fields[0]]
+	if !ok {
+		return nil, fmt.Errorf("generator %s is not supported, use one of: %s", fields[0], strings.Join(generatorNames(), ", "))
+	}
+
+	run := &Run{
+		Name:      fields[0],
+		Args:      fields[1:],
+		generator: generator,
+		Output:    filepath.Dir(mfdPath),
+	}
+
+	command := &cobra.Command{Use: run.Name}
+	generator.New().AddFlags(command)
+	if err := command.ParseFlags(run.args(mfdPath, Scope{})); err != nil {
+		return nil, fmt.Errorf("parse %s flags, err=%w", run.Name, err)
+	}
+
+	flags := command.Flags()
+	if output, err := flags.GetString(base.Output); err == nil && output != "" {
+		run.Output = output
+	}
+	for _, name := range templateFlags(run.Args) {
+		if value, err := flags.GetString(name); err == nil && value != "" {
+			run.Templates = append(run.Templates, value)
+		}
+	}
+	if dir, err := flags.GetString(templatesDirFlag); err == nil && dir != "" {
+		run.Templates = append(run.Templates, mfd.TemplateFiles(dir, run.Name)...)
+	}
+
+	return run, nil
+}
+
+// templates returns custom templates and output rule templates of run
+func (r *Run) templates() []string {
+	return slices.Concat(r.Templates, r.Outputs)
+}
+
+// DependsOn checks if run depends on input
+func (r *Run) DependsOn(input Input) bool {
+	return r.generator.Inputs&input != 0
+}
+
+// Generate runs generator limited by scope
+func (r *Run) Generate(mfdPath string, scope Scope) error {
+	generator := r.generator.New()
+
+	command := &cobra.Command{Use: r.Name}
+	generator.AddFlags(command)
+	if err := command.ParseFlags(r.args(mfdPath, scope)); err != nil {
+		return fmt.Errorf("parse %s flags, err=%w", r.Name, err)
+	}
+
+	if err := generator.ReadFlags(command); err != nil {
+		return fmt.Errorf("read flags, err=%w", err)
+	}
+
+	return generator.Generate()
+}
+
+// Scopes returns scopes of run for changed entities by namespace, nil is returned if run is not affected
+func (r *Run) Scopes(changes Changes) []Scope {
+	if changes.Full || slices.ContainsFunc(r.templates(), func(template string) bool { return slices.Contains(changes.Templates, template) }) {
+		return []Scope{{}}
+	}
+
+	// changed entities by namespace, nil means whole namespace
+	namespaces := map[string][]string{}
+	whole := map[string]bool{}
+	var names []string
+	for _, change := range changes.Entities {
+		if !r.DependsOn(change.Input) {
+			continue
+		}
+
+		if _, ok := namespaces[change.Namespace]; !ok && !whole[change.Namespace] {
+			names = append(names, change.Namespace)
+		}
+
+		// empty entity means namespace level change, e.g. deleted entity
+		switch {
+		case whole[change.Namespace]:
+		case change.Entity == "":
+			whole[change.Namespace] = true
+			delete(namespaces, change.Namespace)
+		case !slices.Contains(namespaces[change.Namespace], mfd.VarName(change.Entity)):
+			// generators find entities by var names
+			namespaces[change.Namespace] = append(namespaces[change.Namespace], mfd.VarName(change.Entity))
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	// generator can't be limited by namespace
+	if !r.generator.Namespaces {
+		return []Scope{{}}
+	}
+
+	sort.Strings(names)
+	scopes := make([]Scope, 0, len(names))
+	for _, name := range names {
+		scope := Scope{Namespace: name}
+		if r.generator.Entities {
+			scope.Entities = namespaces[name]
+		}
+		scopes = append(scopes, scope)
+	}
+
+	return scopes
+}
+
+// args returns run arguments with mfd file and scope flags
+func (r *Run) args(mfdPath string, scope Scope) []string {
+	args := append([]string{}, r.Args...)
+	args = append(args, "--mfd", mfdPath)
+
+	if scope.Namespace != "" {
+		args = append(args, "--"+nsFlag, scope.Namespace)
+	}
+	if len(scope.Entities) > 0 {
+		args = append(args, "--"+entitiesFlag, strings.Join(scope.Entities, ","))
+	}
+
+	return args
+}
+
+// templateFlags returns names of custom template flags used in run arguments
+func templateFlags(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		name, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if strings.HasPrefix(arg, "--") && strings.HasSuffix(name, templateFlag) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func generatorNames() []string {
+	names := make([]string, 0, len(Generators))
+	for name := range Generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,150 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vmkteam/mfd-generator/mfd"
+)
+
+const testProject = "../generators/testdata/expected/newsportal.mfd"
+
+func loadState(t *testing.T, change func(project *mfd.Project, translations map[string]mfd.Translation)) State {
+	t.Helper()
+
+	project, err := mfd.LoadProject(testProject, false, mfd.GoPG10)
+	if err != nil {
+		t.Fatalf("load project: %v", err)
+	}
+
+	translations, err := mfd.LoadTranslations(testProject, project.Languages)
+	if err != nil {
+		t.Fatalf("load translations: %v", err)
+	}
+
+	if change != nil {
+		change(project, translations)
+	}
+
+	return NewState(project, translations)
+}
+
+func TestState_Changes(t *testing.T) {
+	state := loadState(t, nil)
+
+	tests := []struct {
+		name   string
+		change func(project *mfd.Project, translations map[string]mfd.Translation)
+		want   Changes
+	}{
+		{
+			name: "nothing changed",
+		},
+		{
+			name: "entity changed",
+			change: func(project *mfd.Project, _ map[string]mfd.Translation) {
+				project.Entity("News").AttributeByName("Title").Max = 100
+			},
+			want: Changes{Entities: []EntityChange{{Input: InputNamespace, Namespace: "portal", Entity: "News"}}},
+		},
+		{
+			name: "vt entity and translation changed",
+			change: func(project *mfd.Project, translations map[string]mfd.Translation) {
+				project.VTEntity("City").Attribute("Title").Required = false
+				translation := translations[mfd.EnLang]
+				translation.Entity("geo", "City").Key = "town"
+			},
+			want: Changes{Entities: []EntityChange{
+				{Input: InputVT, Namespace: "geo", Entity: "City"},
+				{Input: InputLang, Namespace: "geo", Entity: "City"},
+			}},
+		},
+		{
+			name: "entity deleted",
+			change: func(project *mfd.Project, _ map[string]mfd.Translation) {
+				ns := project.Namespace("geo")
+				ns.Entities = ns.Entities[1:]
+			},
+			want: Changes{Entities: []EntityChange{{Input: InputNamespace, Namespace: "geo"}}},
+		},
+		{
+			name: "project changed",
+			change: func(project *mfd.Project, _ map[string]mfd.Translation) {
+				project.Languages = append(project.Languages, "ru")
+			},
+			want: Changes{Full: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := state.Changes(loadState(t, tt.change)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Changes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Scopes(t *testing.T) {
+	changes := Changes{Entities: []EntityChange{
+		{Input: InputNamespace, Namespace: "portal", Entity: "News"},
+		{Input: InputNamespace, Namespace: "portal", Entity: "NewsCategory"},
+		{Input: InputNamespace, Namespace: "geo"},
+		{Input: InputNamespace, Namespace: "geo", Entity: "City"},
+		{Input: InputLang, Namespace: "vfs", Entity: "VfsFile"},
+	}}
+
+	tests := []struct {
+		line    string
+		changes Changes
+		want    []Scope
+	}{
+		{line: "model -o db", changes: changes, want: []Scope{{}}},
+		{line: "repo -o db", changes: changes, want: []Scope{{Namespace: "geo"}, {Namespace: "portal"}}},
+		{line: "vt -o vt", changes: changes, want: []Scope{{Namespace: "geo"}, {Namespace: "portal", Entities: []string{"news", "newsCategory"}}}},
+		{line: "template -o front", changes: changes, want: []Scope{{Namespace: "vfs", Entities: []string{"vfsFile"}}}},
+		{line: "xml-lang", changes: Changes{Entities: changes.Entities[:1]}, want: nil},
+		{line: "xml-lang", changes: Changes{Full: true}, want: []Scope{{}}},
+		{line: "vt -o vt --model-tmpl model.tmpl", changes: Changes{Templates: []string{"model.tmpl"}}, want: []Scope{{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			run, err := NewRun(tt.line, testProject)
+			if err != nil {
+				t.Fatalf("NewRun() error = %v", err)
+			}
+
+			if got := run.Scopes(tt.changes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scopes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRun(t *testing.T) {
+	run, err := NewRun("vt -o pkg/vt --model-tmpl=model.tmpl --service-tmpl service.tmpl", testProject)
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+	if run.Output != "pkg/vt" || !reflect.DeepEqual(run.Templates, []string{"model.tmpl", "service.tmpl"}) {
+		t.Errorf("NewRun() = %+v", run)
+	}
+
+	dir := t.TempDir()
+	partial := filepath.Join(dir, "vt", "partials", "service.tmpl")
+	if err := os.MkdirAll(filepath.Dir(partial), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(partial, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run, err = NewRun("vt -o pkg/vt --templates-dir "+dir, testProject)
+	if err != nil || !reflect.DeepEqual(run.Templates, []string{partial}) {
+		t.Errorf("NewRun() with templates dir = %+v, %v", run, err)
+	}
+
+	if _, err := NewRun("xml -c postgres://localhost/db", testProject); err == nil {
+		t.Errorf("NewRun() should fail for unsupported generator")
+	}
+}